@@ -27,7 +27,9 @@ func Run() {
 		convertCommand(),
 		fmtCommand(),
 		runCommand(),
+		testCommand(),
 		toolsCommand(),
+		validateCommand(),
 	)
 
 	if err := cmd.Execute(); err != nil {
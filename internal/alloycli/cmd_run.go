@@ -124,6 +124,8 @@ depending on the nature of the reload error.
 	cmd.Flags().StringVar(&r.uiPrefix, "server.http.ui-path-prefix", r.uiPrefix, "Prefix to serve the HTTP UI at")
 	cmd.Flags().
 		BoolVar(&r.enablePprof, "server.http.enable-pprof", r.enablePprof, "Enable /debug/pprof profiling endpoints.")
+	cmd.Flags().
+		BoolVar(&r.enableOpenMetrics, "server.http.enable-openmetrics", r.enableOpenMetrics, "Serve the OpenMetrics format, which includes exemplars, on /metrics to scrapers which request it.")
 	cmd.Flags().
 		BoolVar(&r.disableSupportBundle, "server.http.disable-support-bundle", r.disableSupportBundle, "Disable /-/support support bundle retrieval.")
 
@@ -188,6 +190,7 @@ type alloyRun struct {
 	minStability                         featuregate.Stability
 	uiPrefix                             string
 	enablePprof                          bool
+	enableOpenMetrics                    bool
 	disableReporting                     bool
 	clusterEnabled                       bool
 	clusterNodeName                      string
@@ -347,10 +350,11 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 			return err
 		},
 
-		HTTPListenAddr:   fr.httpListenAddr,
-		MemoryListenAddr: fr.inMemoryAddr,
-		EnablePProf:      fr.enablePprof,
-		MinStability:     fr.minStability,
+		HTTPListenAddr:    fr.httpListenAddr,
+		MemoryListenAddr:  fr.inMemoryAddr,
+		EnablePProf:       fr.enablePprof,
+		EnableOpenMetrics: fr.enableOpenMetrics,
+		MinStability:      fr.minStability,
 		BundleContext: httpservice.SupportBundleContext{
 			RuntimeFlags:         runtimeFlags,
 			DisableSupportBundle: fr.disableSupportBundle,
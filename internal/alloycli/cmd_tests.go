@@ -0,0 +1,191 @@
+package alloycli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/pipelinetest"
+	"github.com/grafana/alloy/syntax/diag"
+)
+
+// testFileExtension is the extension of test files found when a directory is
+// passed to the test command.
+const testFileExtension = ".alloytest"
+
+func testCommand() *cobra.Command {
+	t := &alloyTest{
+		minStability: featuregate.StabilityGenerallyAvailable,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "test [flags] path...",
+		Short: "Run unit tests for configuration modules",
+		Long: `The test subcommand runs unit tests for Alloy configuration modules.
+
+Each path must point to a test file or to a directory. If path is a directory,
+all *` + testFileExtension + ` files in that directory are run. Subdirectories are not
+recursively searched.
+
+A test file contains one or more test blocks. Each test loads a module, passes
+fixture sinks to the module through its arguments, sends fixture inputs to
+values exported by the module, and checks what reached the sinks. Modules run
+without network-facing services, so no real backends are contacted.
+
+test exits with a non-zero exit code if any test fails.`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return t.Run(cmd, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&t.run, "run", t.run, "Only run tests with names matching this regular expression")
+	cmd.Flags().BoolVarP(&t.verbose, "verbose", "v", t.verbose, "Print logs of the modules under test")
+	cmd.Flags().Var(&t.minStability, "stability.level", fmt.Sprintf("Minimum stability level of features to enable. Supported values: %s", strings.Join(featuregate.AllowedValues(), ", ")))
+	cmd.Flags().BoolVar(&t.enableCommunityComps, "feature.community-components.enabled", t.enableCommunityComps, "Enable community components.")
+	return cmd
+}
+
+type alloyTest struct {
+	run                  string
+	verbose              bool
+	minStability         featuregate.Stability
+	enableCommunityComps bool
+}
+
+func (at *alloyTest) Run(cmd *cobra.Command, paths []string) error {
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	var filter func(string) bool
+	if at.run != "" {
+		re, err := regexp.Compile(at.run)
+		if err != nil {
+			return fmt.Errorf("invalid --run expression: %w", err)
+		}
+		filter = re.MatchString
+	}
+
+	files, err := findTestFiles(paths)
+	if err != nil {
+		return err
+	}
+
+	opts := pipelinetest.Options{
+		MinStability:         at.minStability,
+		EnableCommunityComps: at.enableCommunityComps,
+	}
+	if at.verbose {
+		opts.LogWriter = os.Stderr
+	}
+
+	out := cmd.OutOrStdout()
+
+	var ran, failed int
+	for _, path := range files {
+		f, err := pipelinetest.LoadFile(path)
+		if err != nil {
+			printLoadError(path, err)
+			return fmt.Errorf("could not load test file %s", path)
+		}
+
+		for _, res := range pipelinetest.Run(ctx, opts, f, filter) {
+			ran++
+			if !res.Passed() {
+				failed++
+			}
+			printTestResult(out, path, res)
+		}
+	}
+
+	switch {
+	case ran == 0:
+		return fmt.Errorf("no tests to run")
+	case failed > 0:
+		fmt.Fprintf(out, "FAIL (%d of %d tests failed)\n", failed, ran)
+		return fmt.Errorf("%d tests failed", failed)
+	default:
+		fmt.Fprintf(out, "PASS (%d tests)\n", ran)
+		return nil
+	}
+}
+
+// findTestFiles expands the list of paths into the list of test files to run.
+func findTestFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(curPath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// Skip all directories and don't recurse into child dirs that aren't at top-level
+			if d.IsDir() {
+				if curPath != path {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(curPath, testFileExtension) {
+				files = append(files, curPath)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func printLoadError(path string, err error) {
+	var diags diag.Diagnostics
+	if !errors.As(err, &diags) {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	bb, _ := os.ReadFile(path)
+	p := diag.NewPrinter(diag.PrinterConfig{
+		Color:              !color.NoColor,
+		ContextLinesBefore: 1,
+		ContextLinesAfter:  1,
+	})
+	_ = p.Fprint(os.Stderr, map[string][]byte{path: bb}, diags)
+}
+
+func printTestResult(w io.Writer, path string, res pipelinetest.Result) {
+	status := "ok"
+	if !res.Passed() {
+		status = "FAIL"
+	}
+	fmt.Fprintf(w, "--- %s: %s: %s (%.2fs)\n", status, path, res.Name, res.Duration.Seconds())
+
+	if res.Err != nil {
+		for _, line := range strings.Split(res.Err.Error(), "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	for _, failure := range res.Failures {
+		fmt.Fprintf(w, "    %s\n", failure)
+	}
+}
@@ -0,0 +1,96 @@
+package alloycli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestCommand(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pipeline.alloy"), []byte(`
+		argument "forward_to" { }
+
+		loki.process "default" {
+			forward_to = argument.forward_to.value
+
+			stage.drop {
+				expression = "level=debug"
+			}
+		}
+
+		export "receiver" {
+			value = loki.process.default.receiver
+		}
+	`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pipeline.alloytest"), []byte(`
+		test "drops_debug_lines" {
+			module = "pipeline.alloy"
+
+			logs_input "receiver" {
+				entry {
+					line = "level=info msg=first"
+				}
+				entry {
+					line = "level=debug msg=noise"
+				}
+				entry {
+					line = "level=info msg=second"
+				}
+			}
+
+			// Entries are matched in any order.
+			logs_output "forward_to" {
+				expect {
+					line = "level=info msg=second"
+				}
+				expect {
+					line = "level=info msg=first"
+				}
+			}
+		}
+
+		test "keeps_debug_lines" {
+			module = "pipeline.alloy"
+
+			logs_input "receiver" {
+				entry {
+					line = "level=debug msg=noise"
+				}
+			}
+
+			logs_output "forward_to" {
+				expect {
+					line = "level=debug msg=noise"
+				}
+			}
+		}
+	`), 0o644))
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		cmd := testCommand()
+		cmd.SetArgs(args)
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("--run", "drops_debug_lines", dir)
+	require.NoError(t, err, out)
+	require.Contains(t, out, "--- ok: "+filepath.Join(dir, "pipeline.alloytest")+": drops_debug_lines")
+	require.Contains(t, out, "PASS (1 tests)")
+
+	out, err = run(dir)
+	require.EqualError(t, err, "1 tests failed")
+	require.Contains(t, out, "--- FAIL: "+filepath.Join(dir, "pipeline.alloytest")+": keeps_debug_lines")
+	require.Contains(t, out, `logs_output "forward_to": missing entry "level=debug msg=noise"`)
+	require.Contains(t, out, "FAIL (1 of 2 tests failed)")
+
+	_, err = run("--run", "no_such_test", dir)
+	require.EqualError(t, err, "no tests to run")
+}
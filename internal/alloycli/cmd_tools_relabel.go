@@ -0,0 +1,117 @@
+package alloycli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/spf13/cobra"
+
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+)
+
+func relabelToolCommand() *cobra.Command {
+	r := &alloyRelabel{}
+
+	cmd := &cobra.Command{
+		Use:   "relabel --rules file [label set ...]",
+		Short: "Test relabeling rules against label sets",
+		Long: `The relabel subcommand applies relabeling rules to label sets, and prints
+the labels after every rule along with the final labels, or whether the label
+set was dropped.
+
+The rules file contains rule blocks in Alloy syntax, like the rule blocks of
+discovery.relabel or prometheus.relabel. If the rules file is "-", the rules
+are read from stdin.
+
+Label sets are written like Prometheus series, for example
+'{__address__="localhost:9090", job="prometheus"}'. If no label set is
+supplied, label sets are read from stdin, one per line.`,
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return r.Run(os.Stdout, os.Stdin, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&r.rulesFile, "rules", "", "file containing the rule blocks to apply")
+	_ = cmd.MarkFlagRequired("rules")
+	return cmd
+}
+
+type alloyRelabel struct {
+	rulesFile string
+}
+
+func (ar *alloyRelabel) Run(out io.Writer, stdin io.Reader, args []string) error {
+	if ar.rulesFile == "-" && len(args) == 0 {
+		return fmt.Errorf("label sets must be supplied as arguments when reading rules from stdin")
+	}
+
+	var (
+		src []byte
+		err error
+	)
+	if ar.rulesFile == "-" {
+		src, err = io.ReadAll(stdin)
+	} else {
+		src, err = os.ReadFile(ar.rulesFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read rules: %w", err)
+	}
+	rules, err := alloy_relabel.ParseRules(src)
+	if err != nil {
+		return err
+	}
+
+	inputs := args
+	if len(inputs) == 0 {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				inputs = append(inputs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read label sets: %w", err)
+		}
+	}
+
+	for i, input := range inputs {
+		lbls, err := parser.ParseMetric(input)
+		if err != nil {
+			return fmt.Errorf("invalid label set %q: %w", input, err)
+		}
+
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		printRelabelSteps(out, lbls, rules)
+	}
+	return nil
+}
+
+// printRelabelSteps prints the result of every rule applied to lbls, and the
+// final labels.
+func printRelabelSteps(out io.Writer, lbls labels.Labels, rules []*alloy_relabel.Config) {
+	steps, output, keep := alloy_relabel.ProcessSteps(lbls, rules...)
+
+	fmt.Fprintf(out, "Input:  %s\n", lbls)
+	for i, step := range steps {
+		if !step.Keep {
+			fmt.Fprintf(out, "  rule %d (%s): dropped\n", i+1, step.Rule.Action)
+			continue
+		}
+		fmt.Fprintf(out, "  rule %d (%s): %s\n", i+1, step.Rule.Action, step.Labels)
+	}
+	if !keep {
+		fmt.Fprintln(out, "Result: dropped")
+		return
+	}
+	fmt.Fprintf(out, "Result: %s\n", output)
+}
@@ -0,0 +1,50 @@
+package alloycli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelabelTool(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "rules.alloy")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`
+		rule {
+			source_labels = ["__address__"]
+			target_label  = "instance"
+		}
+
+		rule {
+			action        = "drop"
+			source_labels = ["job"]
+			regex         = "web"
+		}
+	`), 0o644))
+
+	var out bytes.Buffer
+	r := &alloyRelabel{rulesFile: rulesFile}
+	stdin := strings.NewReader(`{__address__="localhost:9090", job="api"}
+
+{__address__="localhost:8080", job="web"}
+`)
+	require.NoError(t, r.Run(&out, stdin, nil))
+
+	expected := `Input:  {__address__="localhost:9090", job="api"}
+  rule 1 (replace): {__address__="localhost:9090", instance="localhost:9090", job="api"}
+  rule 2 (drop): {__address__="localhost:9090", instance="localhost:9090", job="api"}
+Result: {__address__="localhost:9090", instance="localhost:9090", job="api"}
+
+Input:  {__address__="localhost:8080", job="web"}
+  rule 1 (replace): {__address__="localhost:8080", instance="localhost:8080", job="web"}
+  rule 2 (drop): dropped
+Result: dropped
+`
+	require.Equal(t, expected, out.String())
+
+	err := r.Run(&out, nil, []string{`{job="api"`})
+	require.ErrorContains(t, err, "invalid label set")
+}
@@ -0,0 +1,184 @@
+package alloycli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/featuregate"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/service"
+	cluster_service "github.com/grafana/alloy/internal/service/cluster"
+	httpservice "github.com/grafana/alloy/internal/service/http"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	otel_service "github.com/grafana/alloy/internal/service/otel"
+	remotecfgservice "github.com/grafana/alloy/internal/service/remotecfg"
+	uiservice "github.com/grafana/alloy/internal/service/ui"
+	"github.com/grafana/alloy/syntax/diag"
+)
+
+func validateCommand() *cobra.Command {
+	v := &alloyValidate{
+		minStability: featuregate.StabilityGenerallyAvailable,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "validate [flags] path",
+		Short: "Validate a configuration file or directory path",
+		Long: `The validate subcommand type-checks a configuration file or directory
+without running it.
+
+path must point to a file or directory. If path is a directory, all *.alloy
+files in that directory are validated together. Subdirectories are not
+recursively searched.
+
+The configuration is loaded the same way as by the run subcommand, including
+modules imported with import blocks and custom components defined with declare
+blocks. References between blocks, the arguments of every component, and the
+stability level of every component are checked, but no component is started.
+
+validate exits with a non-zero exit code if the configuration is invalid.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return v.Run(args[0])
+		},
+	}
+
+	cmd.Flags().BoolVar(&v.skipNetworkImports, "skip-network-imports", v.skipNetworkImports, "Don't fetch modules from import.http and import.git blocks")
+	cmd.Flags().Var(&v.minStability, "stability.level", fmt.Sprintf("Minimum stability level of features to enable. Supported values: %s", strings.Join(featuregate.AllowedValues(), ", ")))
+	cmd.Flags().BoolVar(&v.enableCommunityComps, "feature.community-components.enabled", v.enableCommunityComps, "Enable community components.")
+	return cmd
+}
+
+type alloyValidate struct {
+	skipNetworkImports   bool
+	minStability         featuregate.Stability
+	enableCommunityComps bool
+}
+
+func (av *alloyValidate) Run(configPath string) error {
+	sources, err := loadSourceFiles(configPath, "alloy", false, "")
+	if err != nil {
+		return fmt.Errorf("reading config path %q: %w", configPath, err)
+	}
+
+	// Validation messages are reported as diagnostics, so controller logs
+	// aren't useful here.
+	l, err := logging.New(io.Discard, logging.DefaultOptions)
+	if err != nil {
+		return err
+	}
+
+	// remotecfg creates its storage directory when built.
+	storagePath, err := os.MkdirTemp("", "alloy-validate-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(storagePath)
+
+	services, err := validationServices(l, configPath, storagePath, av.minStability)
+	if err != nil {
+		return err
+	}
+
+	var diags diag.Diagnostics
+	source, err := alloy_runtime.ParseSources(sources)
+	if err != nil {
+		if !errors.As(err, &diags) {
+			return err
+		}
+	} else {
+		diags = alloy_runtime.Validate(alloy_runtime.ValidateOptions{
+			Logger:               l,
+			MinStability:         av.minStability,
+			EnableCommunityComps: av.enableCommunityComps,
+			Services:             services,
+			SkipNetworkImports:   av.skipNetworkImports,
+		}, source, configPath)
+	}
+
+	if len(diags) > 0 {
+		p := diag.NewPrinter(diag.PrinterConfig{
+			Color:              !color.NoColor,
+			ContextLinesBefore: 1,
+			ContextLinesAfter:  1,
+		})
+		_ = p.Fprint(os.Stderr, sources, diags)
+
+		// Print newline after the diagnostics.
+		fmt.Fprintln(os.Stderr)
+	}
+
+	if diags.HasErrors() {
+		return fmt.Errorf("configuration is invalid")
+	}
+	return nil
+}
+
+// validationServices builds the services which may be configured by a
+// validated config. The services are only used for their definitions and are
+// never updated or run.
+func validationServices(l *logging.Logger, configPath, storagePath string, minStability featuregate.Stability) ([]service.Service, error) {
+	reg := prometheus.NewRegistry()
+
+	clusterService, err := cluster_service.New(cluster_service.Options{
+		Log:              l,
+		Metrics:          reg,
+		EnableClustering: false,
+		NodeName:         "alloy-validate",
+		AdvertiseAddress: "127.0.0.1:80",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("building cluster service: %w", err)
+	}
+
+	httpService := httpservice.New(httpservice.Options{
+		Logger:       l,
+		Gatherer:     reg,
+		ReadyFunc:    func() bool { return false },
+		ReloadFunc:   func() error { return nil },
+		MinStability: minStability,
+	})
+
+	remoteCfgService, err := remotecfgservice.New(remotecfgservice.Options{
+		Logger:      l,
+		ConfigPath:  configPath,
+		StoragePath: storagePath,
+		Metrics:     reg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the remotecfg service: %w", err)
+	}
+
+	liveDebuggingService := livedebugging.New()
+
+	uiService := uiservice.New(uiservice.Options{
+		CallbackManager: liveDebuggingService.Data().(livedebugging.CallbackManager),
+		Logger:          l,
+	})
+
+	otelService := otel_service.New(l)
+	if otelService == nil {
+		return nil, fmt.Errorf("failed to create otel service")
+	}
+
+	return []service.Service{
+		clusterService,
+		httpService,
+		labelstore.New(l, reg),
+		liveDebuggingService,
+		otelService,
+		remoteCfgService,
+		uiService,
+	}, nil
+}
@@ -0,0 +1,312 @@
+package positions
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"go.etcd.io/bbolt"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// Positions are written to the database in a single transaction at this
+// interval, so that frequent updates of the same entry only result in one
+// write.
+const bboltFlushPeriod = time.Second
+
+var (
+	positionsBucket = []byte("positions")
+	// keySeparator separates the path and the labels of an entry in a key.
+	// Paths and label sets can't contain a NUL byte.
+	keySeparator = []byte{0}
+)
+
+// bboltPositions stores positions in an embedded bbolt database. Every entry
+// is stored under its own key, so updating an entry doesn't require rewriting
+// the other entries.
+type bboltPositions struct {
+	logger log.Logger
+	cfg    Config
+	db     *bbolt.DB
+
+	mtx sync.Mutex
+	// pending holds the updates which aren't written to the database yet. A nil
+	// value means that the entry was removed.
+	pending map[Entry]*string
+	// flushing holds the updates being written to the database.
+	flushing map[Entry]*string
+
+	quitOnce sync.Once
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+func newBboltPositions(logger log.Logger, cfg Config) (Positions, error) {
+	if cfg.ReadOnly {
+		return nil, fmt.Errorf("the %s positions backend doesn't support read-only mode", BackendBbolt)
+	}
+
+	db, err := openBboltDB(cfg.PositionsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &bboltPositions{
+		logger:  logger,
+		cfg:     cfg,
+		db:      db,
+		pending: make(map[Entry]*string),
+		quit:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go p.run()
+	return p, nil
+}
+
+func openBboltDB(path string) (*bbolt.DB, error) {
+	cleanfn := filepath.Clean(path)
+	// The timeout avoids blocking forever when another process holds the lock on the database.
+	db, err := bbolt.Open(cleanfn, positionFileMode, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open positions database [%s]: %w", cleanfn, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(positionsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize positions database [%s]: %w", cleanfn, err)
+	}
+	return db, nil
+}
+
+// Stop flushes pending updates and closes the database. It's safe to call Stop
+// more than once.
+func (p *bboltPositions) Stop() {
+	p.quitOnce.Do(func() { close(p.quit) })
+	<-p.done
+}
+
+func (p *bboltPositions) PutString(path, labels string, pos string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.pending[Entry{path, labels}] = &pos
+}
+
+func (p *bboltPositions) Put(path, labels string, pos int64) {
+	p.PutString(path, labels, strconv.FormatInt(pos, 10))
+}
+
+func (p *bboltPositions) GetString(path, labels string) string {
+	entry := Entry{path, labels}
+
+	p.mtx.Lock()
+	pos, ok := p.pending[entry]
+	if !ok {
+		pos, ok = p.flushing[entry]
+	}
+	p.mtx.Unlock()
+	if ok {
+		if pos == nil {
+			return ""
+		}
+		return *pos
+	}
+
+	var res string
+	err := p.db.View(func(tx *bbolt.Tx) error {
+		res = string(tx.Bucket(positionsBucket).Get(encodeEntry(entry)))
+		return nil
+	})
+	if err != nil {
+		level.Error(p.logger).Log("msg", "error reading positions database", "error", err)
+	}
+	return res
+}
+
+func (p *bboltPositions) Get(path, labels string) (int64, error) {
+	pos := p.GetString(path, labels)
+	if pos == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(pos, 10, 64)
+}
+
+func (p *bboltPositions) Remove(path, labels string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.pending[Entry{path, labels}] = nil
+}
+
+func (p *bboltPositions) SyncPeriod() time.Duration {
+	return p.cfg.SyncPeriod
+}
+
+func (p *bboltPositions) run() {
+	defer func() {
+		p.flush()
+		if err := p.db.Close(); err != nil {
+			level.Error(p.logger).Log("msg", "error closing positions database", "error", err)
+		}
+		level.Debug(p.logger).Log("msg", "positions saved")
+		close(p.done)
+	}()
+
+	flushTicker := time.NewTicker(bboltFlushPeriod)
+	defer flushTicker.Stop()
+	cleanupTicker := time.NewTicker(p.cfg.SyncPeriod)
+	defer cleanupTicker.Stop()
+	for {
+		select {
+		case <-p.quit:
+			return
+		case <-flushTicker.C:
+			p.flush()
+		case <-cleanupTicker.C:
+			p.cleanup()
+		}
+	}
+}
+
+// flush writes the pending updates to the database.
+func (p *bboltPositions) flush() {
+	p.mtx.Lock()
+	if len(p.pending) == 0 {
+		p.mtx.Unlock()
+		return
+	}
+	p.flushing, p.pending = p.pending, make(map[Entry]*string)
+	p.mtx.Unlock()
+
+	err := p.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(positionsBucket)
+		for entry, pos := range p.flushing {
+			var err error
+			if pos == nil {
+				err = b.Delete(encodeEntry(entry))
+			} else {
+				err = b.Put(encodeEntry(entry), []byte(*pos))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if err != nil {
+		level.Error(p.logger).Log("msg", "error writing positions database", "error", err)
+		// Retry at the next flush, unless the entries were updated again in the meantime.
+		for entry, pos := range p.flushing {
+			if _, ok := p.pending[entry]; !ok {
+				p.pending[entry] = pos
+			}
+		}
+	}
+	p.flushing = nil
+}
+
+// cleanup removes the entries of the files which no longer exist.
+func (p *bboltPositions) cleanup() {
+	var entries []Entry
+	err := p.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(positionsBucket).ForEach(func(k, _ []byte) error {
+			entries = append(entries, decodeEntry(k))
+			return nil
+		})
+	})
+	if err != nil {
+		level.Error(p.logger).Log("msg", "error reading positions database", "error", err)
+		return
+	}
+
+	for _, entry := range entries {
+		// If the position file is prefixed with cursor, it's a
+		// cursor and not a file on disk.
+		if strings.HasPrefix(entry.Path, cursorKeyPrefix) || strings.HasPrefix(entry.Path, journalKeyPrefix) {
+			continue
+		}
+
+		if _, err := os.Stat(entry.Path); err != nil {
+			if os.IsNotExist(err) {
+				p.mtx.Lock()
+				// Don't remove an entry which was updated since the database was read.
+				if _, ok := p.pending[entry]; !ok {
+					p.pending[entry] = nil
+				}
+				p.mtx.Unlock()
+			} else {
+				level.Warn(p.logger).Log("msg", "could not determine if log file "+
+					"still exists while cleaning positions database", "error", err)
+			}
+		}
+	}
+}
+
+func encodeEntry(e Entry) []byte {
+	return []byte(e.Path + string(keySeparator) + e.Labels)
+}
+
+func decodeEntry(key []byte) Entry {
+	path, labels, _ := bytes.Cut(key, keySeparator)
+	return Entry{Path: string(path), Labels: string(labels)}
+}
+
+// ConvertYAMLPositionsFile copies the entries of the YAML positions file at
+// yamlPath to the bbolt positions database at dbPath if:
+// 1. The database is empty or doesn't exist
+// 2. There is a file at yamlPath and it is valid yaml
+// The YAML positions file is kept so that the YAML backend can still be used.
+func ConvertYAMLPositionsFile(yamlPath, dbPath string, l log.Logger) {
+	fi, err := os.Stat(yamlPath)
+	if err != nil || fi.Size() == 0 {
+		return
+	}
+	yamlPositions, err := readPositionsFile(Config{PositionsFile: yamlPath}, l)
+	if err != nil {
+		level.Error(l).Log("msg", "error reading positions file to convert", "path", yamlPath, "error", err)
+		return
+	}
+
+	db, err := openBboltDB(dbPath)
+	if err != nil {
+		level.Error(l).Log("msg", "error opening positions database to convert positions file", "path", dbPath, "error", err)
+		return
+	}
+	defer db.Close()
+
+	converted := false
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(positionsBucket)
+		// If the database already has entries, then don't convert.
+		if k, _ := b.Cursor().First(); k != nil {
+			return nil
+		}
+		for entry, pos := range yamlPositions {
+			if err := b.Put(encodeEntry(entry), []byte(pos)); err != nil {
+				return err
+			}
+		}
+		converted = true
+		return nil
+	})
+	if err != nil {
+		level.Error(l).Log("msg", "error writing positions database from positions file", "path", dbPath, "error", err)
+		return
+	}
+	if converted {
+		level.Info(l).Log("msg", "converted positions file to positions database", "positions_file", yamlPath, "path", dbPath, "entries", len(yamlPositions))
+	}
+}
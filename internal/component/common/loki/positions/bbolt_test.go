@@ -0,0 +1,172 @@
+package positions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func newBboltTestPositions(t *testing.T, path string) Positions {
+	t.Helper()
+
+	p, err := New(log.NewNopLogger(), Config{
+		SyncPeriod:    time.Hour,
+		PositionsFile: path,
+		Backend:       BackendBbolt,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func TestBboltPositions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.db")
+
+	p := newBboltTestPositions(t, path)
+	p.Put("/tmp/a.log", `{job="a"}`, 100)
+	p.Put("/tmp/b.log", "", 200)
+	p.PutString("cursor-c", "", "cursor")
+	p.Put("/tmp/removed.log", "", 300)
+	p.Remove("/tmp/removed.log", "")
+
+	// Updates which aren't flushed yet must be visible.
+	pos, err := p.Get("/tmp/a.log", `{job="a"}`)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), pos)
+	pos, err = p.Get("/tmp/removed.log", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), pos)
+	p.Stop()
+
+	// The positions are kept across restarts.
+	p = newBboltTestPositions(t, path)
+	defer p.Stop()
+
+	pos, err = p.Get("/tmp/a.log", `{job="a"}`)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), pos)
+	pos, err = p.Get("/tmp/a.log", `{job="other"}`)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), pos)
+	pos, err = p.Get("/tmp/b.log", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(200), pos)
+	require.Equal(t, "cursor", p.GetString("cursor-c", ""))
+	require.Equal(t, "", p.GetString("/tmp/removed.log", ""))
+}
+
+func TestBboltPositionsFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.db")
+
+	p := newBboltTestPositions(t, path)
+	defer p.Stop()
+
+	p.Put("/tmp/a.log", "", 100)
+	bp := p.(*bboltPositions)
+	bp.flush()
+
+	bp.mtx.Lock()
+	require.Empty(t, bp.pending)
+	bp.mtx.Unlock()
+	pos, err := p.Get("/tmp/a.log", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(100), pos)
+}
+
+func TestBboltPositionsCleanup(t *testing.T) {
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "existing.log")
+	require.NoError(t, os.WriteFile(existing, []byte("line\n"), 0644))
+
+	p := newBboltTestPositions(t, filepath.Join(tmpDir, "positions.db"))
+	defer p.Stop()
+
+	missing := filepath.Join(tmpDir, "missing.log")
+	p.Put(existing, "", 1)
+	p.Put(missing, "", 2)
+	p.PutString("cursor-c", "", "cursor")
+	bp := p.(*bboltPositions)
+	bp.flush()
+	bp.cleanup()
+	bp.flush()
+
+	require.Equal(t, "1", p.GetString(existing, ""))
+	require.Equal(t, "", p.GetString(missing, ""))
+	require.Equal(t, "cursor", p.GetString("cursor-c", ""))
+}
+
+func TestBboltPositionsStopTwice(t *testing.T) {
+	p := newBboltTestPositions(t, filepath.Join(t.TempDir(), "positions.db"))
+	p.Put("/tmp/a.log", "", 100)
+
+	p.Stop()
+	require.NotPanics(t, p.Stop)
+}
+
+func TestBboltPositionsReadOnly(t *testing.T) {
+	_, err := New(log.NewNopLogger(), Config{
+		SyncPeriod:    time.Hour,
+		PositionsFile: filepath.Join(t.TempDir(), "positions.db"),
+		Backend:       BackendBbolt,
+		ReadOnly:      true,
+	})
+	require.ErrorContains(t, err, "doesn't support read-only mode")
+}
+
+func TestInvalidBackend(t *testing.T) {
+	_, err := New(log.NewNopLogger(), Config{
+		SyncPeriod:    time.Hour,
+		PositionsFile: filepath.Join(t.TempDir(), "positions"),
+		Backend:       "sqlite",
+	})
+	require.EqualError(t, err, `invalid positions backend "sqlite", must be "yaml" or "bbolt"`)
+}
+
+func TestYAMLConversion(t *testing.T) {
+	tmpDir := t.TempDir()
+	yamlPath := filepath.Join(tmpDir, "positions.yml")
+	dbPath := filepath.Join(tmpDir, "positions.db")
+	require.NoError(t, writePositionFile(yamlPath, map[Entry]string{
+		{Path: "/tmp/a.log", Labels: `{job="a"}`}: "100",
+		{Path: "cursor-c", Labels: ""}:            "cursor",
+	}))
+
+	ConvertYAMLPositionsFile(yamlPath, dbPath, log.NewNopLogger())
+
+	p := newBboltTestPositions(t, dbPath)
+	require.Equal(t, "100", p.GetString("/tmp/a.log", `{job="a"}`))
+	require.Equal(t, "cursor", p.GetString("cursor-c", ""))
+	// Update an entry, it must not be overwritten by a later conversion.
+	p.Put("/tmp/a.log", `{job="a"}`, 500)
+	p.Stop()
+
+	ConvertYAMLPositionsFile(yamlPath, dbPath, log.NewNopLogger())
+
+	p = newBboltTestPositions(t, dbPath)
+	defer p.Stop()
+	require.Equal(t, "500", p.GetString("/tmp/a.log", `{job="a"}`))
+
+	// The YAML positions file is kept.
+	ps, err := readPositionsFile(Config{PositionsFile: yamlPath}, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Len(t, ps, 2)
+}
+
+func TestNewForComponentConvertsYAML(t *testing.T) {
+	dataPath := t.TempDir()
+	require.NoError(t, writePositionFile(filepath.Join(dataPath, "positions.yml"), map[Entry]string{
+		{Path: "/tmp/a.log", Labels: ""}: "42",
+	}))
+
+	p, err := NewForComponent(log.NewNopLogger(), dataPath, BackendBbolt)
+	require.NoError(t, err)
+	defer p.Stop()
+
+	pos, err := p.Get("/tmp/a.log", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(42), pos)
+	require.FileExists(t, filepath.Join(dataPath, "positions.db"))
+}
@@ -31,6 +31,26 @@ type Config struct {
 	PositionsFile     string        `mapstructure:"filename" yaml:"filename"`
 	IgnoreInvalidYaml bool          `mapstructure:"ignore_invalid_yaml" yaml:"ignore_invalid_yaml"`
 	ReadOnly          bool          `mapstructure:"-" yaml:"-"`
+	// Backend selects how positions are stored, BackendYAML is used if it's empty.
+	Backend string `mapstructure:"-" yaml:"-"`
+}
+
+// Backends which can store positions.
+const (
+	// BackendYAML periodically writes all the positions to a YAML file.
+	BackendYAML = "yaml"
+	// BackendBbolt stores every position under its own key in a bbolt database.
+	BackendBbolt = "bbolt"
+)
+
+// ValidateBackend returns an error if backend isn't a supported positions backend.
+func ValidateBackend(backend string) error {
+	switch backend {
+	case "", BackendYAML, BackendBbolt:
+		return nil
+	default:
+		return fmt.Errorf("invalid positions backend %q, must be %q or %q", backend, BackendYAML, BackendBbolt)
+	}
 }
 
 // RegisterFlagsWithPrefix registers flags where every name is prefixed by
@@ -155,8 +175,32 @@ func readLegacyFile(legacyPath string, l log.Logger) *LegacyFile {
 	return legacyPositions
 }
 
+// NewForComponent makes the Positions of a component which stores its data
+// in dataPath. The YAML positions file of the component is converted the first
+// time the bbolt backend is used.
+func NewForComponent(logger log.Logger, dataPath string, backend string) (Positions, error) {
+	yamlPath := filepath.Join(dataPath, "positions.yml")
+	cfg := Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: yamlPath,
+		Backend:       backend,
+	}
+	if backend == BackendBbolt {
+		cfg.PositionsFile = filepath.Join(dataPath, "positions.db")
+		ConvertYAMLPositionsFile(yamlPath, cfg.PositionsFile, logger)
+	}
+	return New(logger, cfg)
+}
+
 // New makes a new Positions.
 func New(logger log.Logger, cfg Config) (Positions, error) {
+	if err := ValidateBackend(cfg.Backend); err != nil {
+		return nil, err
+	}
+	if cfg.Backend == BackendBbolt {
+		return newBboltPositions(logger, cfg)
+	}
+
 	positionData, err := readPositionsFile(cfg, logger)
 	if err != nil {
 		return nil, err
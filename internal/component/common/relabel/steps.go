@@ -0,0 +1,73 @@
+package relabel
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+// Step is the result of applying a single relabeling rule to a label set.
+type Step struct {
+	// Rule is the applied rule.
+	Rule *Config
+	// Labels are the labels after the rule was applied. They are empty if the
+	// rule dropped the label set.
+	Labels labels.Labels
+	// Keep is false if the rule dropped the label set.
+	Keep bool
+}
+
+// ProcessSteps applies the rules to lbls in order like ProcessBuilder, and
+// returns the result of every applied rule along with the final labels. The
+// rules after the rule dropping the label set aren't applied, and the final
+// labels are empty in that case.
+func ProcessSteps(lbls labels.Labels, cfgs ...*Config) ([]Step, labels.Labels, bool) {
+	lb := &stepBuilder{b: labels.NewBuilder(lbls)}
+	steps := make([]Step, 0, len(cfgs))
+
+	for _, cfg := range cfgs {
+		if !doRelabel(cfg, lb) {
+			steps = append(steps, Step{Rule: cfg, Labels: labels.EmptyLabels()})
+			return steps, labels.EmptyLabels(), false
+		}
+		steps = append(steps, Step{Rule: cfg, Labels: lb.b.Labels(), Keep: true})
+	}
+	return steps, lb.b.Labels(), true
+}
+
+// ParseRules parses the rule blocks of an Alloy syntax source into relabeling
+// rules.
+func ParseRules(src []byte) ([]*Config, error) {
+	var body struct {
+		Rules []*Config `alloy:"rule,block,optional"`
+	}
+	if err := syntax.Unmarshal(src, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	return body.Rules, nil
+}
+
+// stepBuilder adapts labels.Builder to LabelBuilder.
+type stepBuilder struct {
+	b *labels.Builder
+}
+
+func (sb *stepBuilder) Get(label string) string {
+	return sb.b.Get(label)
+}
+
+func (sb *stepBuilder) Range(f func(label string, value string)) {
+	sb.b.Range(func(l labels.Label) {
+		f(l.Name, l.Value)
+	})
+}
+
+func (sb *stepBuilder) Set(label string, val string) {
+	sb.b.Set(label, val)
+}
+
+func (sb *stepBuilder) Del(ns ...string) {
+	sb.b.Del(ns...)
+}
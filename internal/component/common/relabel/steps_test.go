@@ -0,0 +1,57 @@
+package relabel
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessSteps(t *testing.T) {
+	rules, err := ParseRules([]byte(`
+		rule {
+			source_labels = ["__address__"]
+			target_label  = "instance"
+		}
+
+		rule {
+			action        = "keep"
+			source_labels = ["job"]
+			regex         = "api"
+		}
+
+		rule {
+			action = "labeldrop"
+			regex  = "__address__"
+		}
+	`))
+	require.NoError(t, err)
+	require.Len(t, rules, 3)
+
+	input := labels.FromStrings("__address__", "localhost:9090", "job", "api")
+	steps, output, keep := ProcessSteps(input, rules...)
+	require.True(t, keep)
+	require.Equal(t, []Step{
+		{Rule: rules[0], Labels: labels.FromStrings("__address__", "localhost:9090", "instance", "localhost:9090", "job", "api"), Keep: true},
+		{Rule: rules[1], Labels: labels.FromStrings("__address__", "localhost:9090", "instance", "localhost:9090", "job", "api"), Keep: true},
+		{Rule: rules[2], Labels: labels.FromStrings("instance", "localhost:9090", "job", "api"), Keep: true},
+	}, steps)
+	require.Equal(t, labels.FromStrings("instance", "localhost:9090", "job", "api"), output)
+
+	// The rules after the dropping rule aren't applied.
+	input = labels.FromStrings("__address__", "localhost:9090", "job", "web")
+	steps, output, keep = ProcessSteps(input, rules...)
+	require.False(t, keep)
+	require.Len(t, steps, 2)
+	require.False(t, steps[1].Keep)
+	require.True(t, output.IsEmpty())
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	_, err := ParseRules([]byte(`
+		rule {
+			action = "hashmod"
+		}
+	`))
+	require.ErrorContains(t, err, "requires non-zero modulus")
+}
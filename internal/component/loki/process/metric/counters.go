@@ -29,11 +29,16 @@ type CounterConfig struct {
 	Action          string `alloy:"action,attr"`
 	MatchAll        bool   `alloy:"match_all,attr,optional"`
 	CountEntryBytes bool   `alloy:"count_entry_bytes,attr,optional"`
+
+	// Exemplar fields
+	ExemplarSource string `alloy:"exemplar_source,attr,optional"`
+	ExemplarLabel  string `alloy:"exemplar_label,attr,optional"`
 }
 
 // DefaultCounterConfig sets the default for a Counter.
 var DefaultCounterConfig = CounterConfig{
-	MaxIdle: 5 * time.Minute,
+	MaxIdle:       5 * time.Minute,
+	ExemplarLabel: DefaultExemplarLabel,
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -60,6 +65,9 @@ func (c *CounterConfig) Validate() error {
 	if c.CountEntryBytes && (!c.MatchAll || c.Action != "add") {
 		return fmt.Errorf("the 'count_entry_bytes' counter field must be specified along with match_all set to true or action set to 'add'")
 	}
+	if c.ExemplarSource != "" && !model.LabelName(c.ExemplarLabel).IsValid() {
+		return fmt.Errorf("invalid exemplar_label %q", c.ExemplarLabel)
+	}
 	return nil
 }
 
@@ -109,6 +117,13 @@ func (e *expiringCounter) Add(val float64) {
 	e.lastModSec = time.Now().Unix()
 }
 
+// AddWithExemplar works like Add but also attaches an exemplar to the added
+// value.
+func (e *expiringCounter) AddWithExemplar(val float64, exemplar prometheus.Labels) {
+	e.Counter.(prometheus.ExemplarAdder).AddWithExemplar(val, exemplar)
+	e.lastModSec = time.Now().Unix()
+}
+
 // HasExpired implements Expirable
 func (e *expiringCounter) HasExpired(currentTimeSec int64, maxAgeSec int64) bool {
 	return currentTimeSec-e.lastModSec >= maxAgeSec
@@ -0,0 +1,23 @@
+package metric
+
+import (
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultExemplarLabel is the default label of the trace IDs of exemplars.
+const DefaultExemplarLabel = "trace_id"
+
+// ExemplarLabels returns the labels of an exemplar linking to the trace with
+// the given ID, or nil if the trace ID is empty or too long to fit in an
+// exemplar.
+func ExemplarLabels(label, traceID string) prometheus.Labels {
+	if traceID == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(label)+utf8.RuneCountInString(traceID) > prometheus.ExemplarMaxRunes {
+		return nil
+	}
+	return prometheus.Labels{label: traceID}
+}
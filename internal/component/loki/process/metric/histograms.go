@@ -12,7 +12,8 @@ import (
 
 // DefaultHistogramConfig sets the defaults for a Histogram.
 var DefaultHistogramConfig = HistogramConfig{
-	MaxIdle: 5 * time.Minute,
+	MaxIdle:       5 * time.Minute,
+	ExemplarLabel: DefaultExemplarLabel,
 }
 
 // HistogramConfig defines a histogram metric whose values are bucketed.
@@ -27,6 +28,10 @@ type HistogramConfig struct {
 
 	// Histogram-specific fields
 	Buckets []float64 `alloy:"buckets,attr"`
+
+	// Exemplar fields
+	ExemplarSource string `alloy:"exemplar_source,attr,optional"`
+	ExemplarLabel  string `alloy:"exemplar_label,attr,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -43,6 +48,9 @@ func (h *HistogramConfig) Validate() error {
 	if h.Source == "" {
 		h.Source = h.Name
 	}
+	if h.ExemplarSource != "" && !model.LabelName(h.ExemplarLabel).IsValid() {
+		return fmt.Errorf("invalid exemplar_label %q", h.ExemplarLabel)
+	}
 	return nil
 }
 
@@ -85,6 +93,13 @@ func (h *expiringHistogram) Observe(val float64) {
 	h.lastModSec = time.Now().Unix()
 }
 
+// ObserveWithExemplar works like Observe but also attaches an exemplar to the
+// observation.
+func (h *expiringHistogram) ObserveWithExemplar(val float64, exemplar prometheus.Labels) {
+	h.Histogram.(prometheus.ExemplarObserver).ObserveWithExemplar(val, exemplar)
+	h.lastModSec = time.Now().Unix()
+}
+
 // HasExpired implements Expirable
 func (h *expiringHistogram) HasExpired(currentTimeSec int64, maxAgeSec int64) bool {
 	return currentTimeSec-h.lastModSec >= maxAgeSec
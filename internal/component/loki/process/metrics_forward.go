@@ -0,0 +1,259 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component/loki/process/metric"
+)
+
+// metricsRegisterer registers the collectors of stage.metrics to the registry
+// of the metrics forwarded to metrics_forward_to, in addition to the component
+// registerer. The other collectors are only registered to the component
+// registerer.
+type metricsRegisterer struct {
+	prometheus_client.Registerer
+	forwarded *prometheus_client.Registry
+}
+
+var _ prometheus_client.Registerer = (*metricsRegisterer)(nil)
+
+func newMetricsRegisterer(reg prometheus_client.Registerer) *metricsRegisterer {
+	return &metricsRegisterer{
+		Registerer: reg,
+		forwarded:  prometheus_client.NewRegistry(),
+	}
+}
+
+// Register implements prometheus.Registerer.
+func (r *metricsRegisterer) Register(c prometheus_client.Collector) error {
+	if err := r.Registerer.Register(c); err != nil {
+		return err
+	}
+	if !isStageMetric(c) {
+		return nil
+	}
+	return r.forwarded.Register(c)
+}
+
+// MustRegister implements prometheus.Registerer.
+func (r *metricsRegisterer) MustRegister(cs ...prometheus_client.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Unregister implements prometheus.Registerer.
+func (r *metricsRegisterer) Unregister(c prometheus_client.Collector) bool {
+	if isStageMetric(c) {
+		r.forwarded.Unregister(c)
+	}
+	return r.Registerer.Unregister(c)
+}
+
+// isStageMetric returns true for the collectors of stage.metrics. The pattern
+// counters of stage.patterns use the same collectors, and are forwarded too.
+func isStageMetric(c prometheus_client.Collector) bool {
+	switch c.(type) {
+	case *metric.Counters, *metric.Gauges, *metric.Histograms:
+		return true
+	default:
+		return false
+	}
+}
+
+// metricsForwarder writes the metrics of stage.metrics to an appendable.
+type metricsForwarder struct {
+	mut      sync.Mutex
+	gatherer prometheus_client.Gatherer
+
+	// The series written by the last forward, by hash. They are marked as stale
+	// when they aren't gathered anymore, for example because they were removed
+	// after max_idle_duration.
+	written map[uint64]labels.Labels
+}
+
+func newMetricsForwarder() *metricsForwarder {
+	return &metricsForwarder{written: make(map[uint64]labels.Labels)}
+}
+
+// setGatherer sets the gatherer of the metrics of the current pipeline.
+func (f *metricsForwarder) setGatherer(g prometheus_client.Gatherer) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	f.gatherer = g
+}
+
+// forward writes the current values of the metrics to app with the timestamp
+// now, and marks the previously written series which are gone as stale.
+func (f *metricsForwarder) forward(ctx context.Context, appendable storage.Appendable, now time.Time) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	var families []*dto.MetricFamily
+	if f.gatherer != nil {
+		var err error
+		families, err = f.gatherer.Gather()
+		if err != nil {
+			return fmt.Errorf("failed to gather metrics: %w", err)
+		}
+	}
+
+	var (
+		app     = appendable.Appender(ctx)
+		ts      = now.UnixMilli()
+		written = make(map[uint64]labels.Labels, len(f.written))
+	)
+	for _, mf := range families {
+		for _, s := range familySamples(mf) {
+			if _, err := app.Append(0, s.labels, ts, s.value); err != nil {
+				_ = app.Rollback()
+				return fmt.Errorf("failed to write series %s: %w", s.labels, err)
+			}
+			written[s.labels.Hash()] = s.labels
+
+			if s.exemplar != nil {
+				ex := exemplar.Exemplar{
+					Labels: exemplarLabels(s.exemplar),
+					Value:  s.exemplar.GetValue(),
+					Ts:     s.exemplar.GetTimestamp().AsTime().UnixMilli(),
+					HasTs:  s.exemplar.GetTimestamp() != nil,
+				}
+				// Exemplars are best effort, the sample was written anyway.
+				_, _ = app.AppendExemplar(0, s.labels, ex)
+			}
+			_, _ = app.UpdateMetadata(0, s.labels, metadata.Metadata{
+				Type: s.metricType,
+				Help: mf.GetHelp(),
+			})
+		}
+	}
+
+	staleNaN := math.Float64frombits(value.StaleNaN)
+	for hash, lbls := range f.written {
+		if _, ok := written[hash]; ok {
+			continue
+		}
+		if _, err := app.Append(0, lbls, ts, staleNaN); err != nil {
+			_ = app.Rollback()
+			return fmt.Errorf("failed to mark series %s as stale: %w", lbls, err)
+		}
+	}
+
+	if err := app.Commit(); err != nil {
+		return fmt.Errorf("failed to commit metrics: %w", err)
+	}
+	f.written = written
+	return nil
+}
+
+type forwardedSample struct {
+	labels     labels.Labels
+	value      float64
+	metricType model.MetricType
+	exemplar   *dto.Exemplar
+}
+
+// familySamples returns the samples of a metric family as they're exposed in
+// the text format. Histograms are split into their bucket, sum and count
+// series.
+func familySamples(mf *dto.MetricFamily) []forwardedSample {
+	var samples []forwardedSample
+	for _, m := range mf.Metric {
+		switch mf.GetType() {
+		case dto.MetricType_COUNTER:
+			samples = append(samples, forwardedSample{
+				labels:     metricLabels(mf.GetName(), m),
+				value:      m.GetCounter().GetValue(),
+				metricType: model.MetricTypeCounter,
+				exemplar:   m.GetCounter().GetExemplar(),
+			})
+		case dto.MetricType_GAUGE:
+			samples = append(samples, forwardedSample{
+				labels:     metricLabels(mf.GetName(), m),
+				value:      m.GetGauge().GetValue(),
+				metricType: model.MetricTypeGauge,
+			})
+		case dto.MetricType_HISTOGRAM:
+			h := m.GetHistogram()
+			hasInf := false
+			for _, b := range h.GetBucket() {
+				if math.IsInf(b.GetUpperBound(), +1) {
+					hasInf = true
+				}
+				samples = append(samples, forwardedSample{
+					labels:     metricLabels(mf.GetName()+"_bucket", m, model.BucketLabel, formatFloat(b.GetUpperBound())),
+					value:      float64(b.GetCumulativeCount()),
+					metricType: model.MetricTypeHistogram,
+					exemplar:   b.GetExemplar(),
+				})
+			}
+			if !hasInf {
+				samples = append(samples, forwardedSample{
+					labels:     metricLabels(mf.GetName()+"_bucket", m, model.BucketLabel, "+Inf"),
+					value:      float64(h.GetSampleCount()),
+					metricType: model.MetricTypeHistogram,
+				})
+			}
+			samples = append(samples,
+				forwardedSample{
+					labels:     metricLabels(mf.GetName()+"_sum", m),
+					value:      h.GetSampleSum(),
+					metricType: model.MetricTypeHistogram,
+				},
+				forwardedSample{
+					labels:     metricLabels(mf.GetName()+"_count", m),
+					value:      float64(h.GetSampleCount()),
+					metricType: model.MetricTypeHistogram,
+				},
+			)
+		}
+	}
+	return samples
+}
+
+// metricLabels returns the labels of a series of m with the given name and
+// extra label pairs.
+func metricLabels(name string, m *dto.Metric, extra ...string) labels.Labels {
+	b := labels.NewScratchBuilder(len(m.GetLabel()) + len(extra)/2 + 1)
+	b.Add(model.MetricNameLabel, name)
+	for _, lp := range m.GetLabel() {
+		b.Add(lp.GetName(), lp.GetValue())
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		b.Add(extra[i], extra[i+1])
+	}
+	b.Sort()
+	return b.Labels()
+}
+
+func exemplarLabels(e *dto.Exemplar) labels.Labels {
+	b := labels.NewScratchBuilder(len(e.GetLabel()))
+	for _, lp := range e.GetLabel() {
+		b.Add(lp.GetName(), lp.GetValue())
+	}
+	b.Sort()
+	return b.Labels()
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, +1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,106 @@
+package process
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/loki/process/metric"
+	"github.com/grafana/alloy/internal/util/testappender"
+)
+
+func TestMetricsForwarder(t *testing.T) {
+	reg := prometheus_client.NewRegistry()
+	registerer := newMetricsRegisterer(reg)
+
+	counters, err := metric.NewCounters("loki_process_custom_lines_total", &metric.CounterConfig{
+		Action:  metric.CounterInc,
+		MaxIdle: time.Minute,
+	})
+	require.NoError(t, err)
+	histograms, err := metric.NewHistograms("loki_process_custom_duration_seconds", &metric.HistogramConfig{
+		MaxIdle: time.Minute,
+		Buckets: []float64{0.5, 1},
+	})
+	require.NoError(t, err)
+	// Collectors of other stages aren't forwarded.
+	other := prometheus_client.NewCounter(prometheus_client.CounterOpts{Name: "loki_process_dropped_lines_total"})
+	registerer.MustRegister(counters, histograms, other)
+
+	counters.With(model.LabelSet{"app": "api"}).Inc()
+	histograms.With(model.LabelSet{"app": "api"}).Observe(0.7)
+	other.Inc()
+
+	app := metadataAppender{testappender.NewCollectingAppender()}
+	appendable := testappender.ConstantAppendable{Inner: app}
+
+	forwarder := newMetricsForwarder()
+	forwarder.setGatherer(registerer.forwarded)
+
+	now := time.Now()
+	require.NoError(t, forwarder.forward(t.Context(), appendable, now))
+
+	expect := map[string]float64{
+		`{__name__="loki_process_custom_lines_total", app="api"}`:                        1,
+		`{__name__="loki_process_custom_duration_seconds_bucket", app="api", le="0.5"}`:  0,
+		`{__name__="loki_process_custom_duration_seconds_bucket", app="api", le="1"}`:    1,
+		`{__name__="loki_process_custom_duration_seconds_bucket", app="api", le="+Inf"}`: 1,
+		`{__name__="loki_process_custom_duration_seconds_sum", app="api"}`:               0.7,
+		`{__name__="loki_process_custom_duration_seconds_count", app="api"}`:             1,
+	}
+	samples := app.CollectedSamples()
+	require.Len(t, samples, len(expect))
+	for series, v := range expect {
+		require.Contains(t, samples, series)
+		require.Equal(t, v, samples[series].Value, series)
+		require.Equal(t, now.UnixMilli(), samples[series].Timestamp, series)
+	}
+
+	// The series of removed metrics, for example after max_idle_duration, are
+	// marked as stale.
+	histograms.DeleteAll()
+	counters.With(model.LabelSet{"app": "api"}).Inc()
+
+	now = now.Add(time.Minute)
+	require.NoError(t, forwarder.forward(t.Context(), appendable, now))
+
+	samples = app.CollectedSamples()
+	for series := range expect {
+		s := samples[series]
+		require.Equal(t, now.UnixMilli(), s.Timestamp, series)
+		if series == `{__name__="loki_process_custom_lines_total", app="api"}` {
+			require.Equal(t, 2.0, s.Value)
+			continue
+		}
+		require.True(t, value.IsStaleNaN(s.Value), series)
+	}
+
+	// Stale series are only marked once.
+	now = now.Add(time.Minute)
+	require.NoError(t, forwarder.forward(t.Context(), appendable, now))
+	require.Less(t, app.LatestSampleFor(`{__name__="loki_process_custom_duration_seconds_count", app="api"}`).Timestamp, now.UnixMilli())
+	require.False(t, math.IsNaN(app.LatestSampleFor(`{__name__="loki_process_custom_lines_total", app="api"}`).Value))
+}
+
+// metadataAppender is a collecting appender which ignores exemplars and
+// metadata, which the forwarder writes along with the samples.
+type metadataAppender struct {
+	testappender.CollectingAppender
+}
+
+func (a metadataAppender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, _ exemplar.Exemplar) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (a metadataAppender) UpdateMetadata(ref storage.SeriesRef, _ labels.Labels, _ metadata.Metadata) (storage.SeriesRef, error) {
+	return ref, nil
+}
@@ -10,11 +10,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/storage"
+
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/loki/process/stages"
+	"github.com/grafana/alloy/internal/component/prometheus"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
 	"github.com/grafana/alloy/internal/service/livedebugging"
 )
 
@@ -40,6 +44,28 @@ func init() {
 type Arguments struct {
 	ForwardTo []loki.LogsReceiver  `alloy:"forward_to,attr"`
 	Stages    []stages.StageConfig `alloy:"stage,enum,optional"`
+
+	// Where the metrics of stage.metrics are written to, and how often.
+	MetricsForwardTo       []storage.Appendable `alloy:"metrics_forward_to,attr,optional"`
+	MetricsForwardInterval time.Duration        `alloy:"metrics_forward_interval,attr,optional"`
+}
+
+// DefaultArguments holds the default arguments of loki.process.
+var DefaultArguments = Arguments{
+	MetricsForwardInterval: 15 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.MetricsForwardInterval <= 0 {
+		return fmt.Errorf("metrics_forward_interval must be greater than 0")
+	}
+	return nil
 }
 
 // Exports exposes the receiver that can be used to send log entries to
@@ -67,6 +93,11 @@ type Component struct {
 	fanoutMut sync.RWMutex
 	fanout    []loki.LogsReceiver
 
+	metricsMut       sync.RWMutex
+	metricsFanout    *prometheus.Fanout
+	metricsInterval  time.Duration
+	metricsForwarder *metricsForwarder
+
 	debugDataPublisher livedebugging.DebugDataPublisher
 }
 
@@ -80,6 +111,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:               o,
 		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
+		metricsForwarder:   newMetricsForwarder(),
 	}
 
 	// Create and immediately export the receiver which remains the same for
@@ -116,6 +148,8 @@ func (c *Component) Run(ctx context.Context) error {
 	go c.handleIn(ctx, wgIn)
 	wgOut.Add(1)
 	go c.handleOut(handleOutShutdown, wgOut)
+	wgIn.Add(1)
+	go c.forwardMetrics(ctx, wgIn)
 
 	wgIn.Wait()
 	return nil
@@ -130,6 +164,10 @@ func (c *Component) Update(args component.Arguments) error {
 	c.fanout = newArgs.ForwardTo
 	c.fanoutMut.Unlock()
 
+	if err := c.updateMetricsFanout(newArgs); err != nil {
+		return err
+	}
+
 	// Then update the pipeline itself.
 	c.mut.Lock()
 	defer c.mut.Unlock()
@@ -142,10 +180,14 @@ func (c *Component) Update(args component.Arguments) error {
 			c.entryHandler.Stop()
 		}
 
-		pipeline, err := stages.NewPipeline(c.opts.Logger, newArgs.Stages, &c.opts.ID, c.opts.Registerer, c.opts.MinStability)
+		// The metrics of stage.metrics are also registered to a registry of the
+		// pipeline, which is gathered to forward them to metrics_forward_to.
+		registerer := newMetricsRegisterer(c.opts.Registerer)
+		pipeline, err := stages.NewPipeline(c.opts.Logger, newArgs.Stages, &c.opts.ID, registerer, c.opts.MinStability)
 		if err != nil {
 			return err
 		}
+		c.metricsForwarder.setGatherer(registerer.forwarded)
 		entryHandler := loki.NewEntryHandler(c.processOut, func() { pipeline.Cleanup() })
 		c.entryHandler = pipeline.Wrap(entryHandler)
 		c.processIn = c.entryHandler.Chan()
@@ -155,6 +197,62 @@ func (c *Component) Update(args component.Arguments) error {
 	return nil
 }
 
+// updateMetricsFanout updates the appendables the metrics of stage.metrics are
+// written to. The fanout is only created once metrics_forward_to is set, as
+// it requires the label store service.
+func (c *Component) updateMetricsFanout(args Arguments) error {
+	c.metricsMut.Lock()
+	defer c.metricsMut.Unlock()
+
+	c.metricsInterval = args.MetricsForwardInterval
+	if c.metricsInterval <= 0 {
+		// Arguments which weren't decoded from Alloy syntax have no defaults.
+		c.metricsInterval = DefaultArguments.MetricsForwardInterval
+	}
+	if c.metricsFanout != nil {
+		c.metricsFanout.UpdateChildren(args.MetricsForwardTo)
+		return nil
+	}
+	if len(args.MetricsForwardTo) == 0 {
+		return nil
+	}
+
+	data, err := c.opts.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return err
+	}
+	c.metricsFanout = prometheus.NewFanout(args.MetricsForwardTo, c.opts.ID, c.opts.Registerer, data.(labelstore.LabelStore))
+	return nil
+}
+
+// forwardMetrics writes the metrics of stage.metrics to metrics_forward_to
+// every metrics_forward_interval.
+func (c *Component) forwardMetrics(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for {
+		c.metricsMut.RLock()
+		interval := c.metricsInterval
+		c.metricsMut.RUnlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		c.metricsMut.RLock()
+		fanout := c.metricsFanout
+		c.metricsMut.RUnlock()
+		if fanout == nil {
+			continue
+		}
+
+		if err := c.metricsForwarder.forward(ctx, fanout, time.Now()); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to forward metrics", "err", err)
+		}
+	}
+}
+
 func (c *Component) handleIn(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	componentID := livedebugging.ComponentID(c.opts.ID)
@@ -176,6 +274,7 @@ func (c *Component) handleIn(ctx context.Context, wg *sync.WaitGroup) {
 					}
 					return fmt.Sprintf("[IN]: timestamp: %s, entry: %s, labels: %s, structured_metadata: %s", entry.Timestamp.Format(time.RFC3339Nano), entry.Line, entry.Labels.String(), string(structured_metadata))
 				},
+				livedebugging.WithLabelSets(entry.Labels),
 			))
 			select {
 			case <-ctx.Done():
@@ -217,6 +316,7 @@ func (c *Component) handleOut(shutdownCh chan struct{}, wg *sync.WaitGroup) {
 					}
 					return fmt.Sprintf("[OUT]: timestamp: %s, entry: %s, labels: %s, structured_metadata: %s", entry.Timestamp.Format(time.RFC3339Nano), entry.Line, entry.Labels.String(), string(structured_metadata))
 				},
+				livedebugging.WithLabelSets(entry.Labels),
 			))
 
 			for _, f := range fanout {
@@ -0,0 +1,177 @@
+package stages
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const dedupDropReason = "dedup"
+
+var (
+	dedupUUIDRegex   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	dedupNumberRegex = regexp.MustCompile(`[0-9]+`)
+)
+
+// DedupConfig contains the configuration for a dedup stage.
+type DedupConfig struct {
+	Window      time.Duration `alloy:"window,attr,optional"`
+	MaskNumbers bool          `alloy:"mask_numbers,attr,optional"`
+	MaskUUIDs   bool          `alloy:"mask_uuids,attr,optional"`
+}
+
+// DefaultDedupConfig applies the default values on DedupConfig.
+var DefaultDedupConfig = DedupConfig{
+	Window: 10 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *DedupConfig) SetToDefault() {
+	*args = DefaultDedupConfig
+}
+
+// Validate implements syntax.Validator.
+func (args *DedupConfig) Validate() error {
+	if args.Window <= 0 {
+		return fmt.Errorf("window must be greater than 0")
+	}
+	return nil
+}
+
+// dedupStage suppresses the lines of a stream which are identical to a line
+// seen within the window, and emits a summary line with the number of
+// suppressed lines when the window closes.
+type dedupStage struct {
+	logger    log.Logger
+	cfg       DedupConfig
+	dropCount *prometheus.CounterVec
+}
+
+// dedupKey identifies identical lines of a stream.
+type dedupKey struct {
+	stream model.Fingerprint
+	line   uint64
+}
+
+// dedupWindow captures the lines suppressed within the window of a line.
+type dedupWindow struct {
+	key        dedupKey
+	deadline   time.Time
+	last       Entry // The last suppressed entry.
+	suppressed int   // The number of suppressed entries.
+}
+
+func newDedupStage(logger log.Logger, config DedupConfig, registerer prometheus.Registerer) Stage {
+	return &dedupStage{
+		logger:    log.With(logger, "component", "stage", "type", "dedup"),
+		cfg:       config,
+		dropCount: getDropCountMetric(registerer),
+	}
+}
+
+func (m *dedupStage) Run(in chan Entry) chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+
+		windows := make(map[dedupKey]*dedupWindow)
+		// The windows ordered by deadline. As all the windows have the same
+		// length, they're also ordered by creation.
+		var queue []*dedupWindow
+
+		for {
+			var expired <-chan time.Time
+			if len(queue) > 0 {
+				expired = time.After(time.Until(queue[0].deadline))
+			}
+
+			select {
+			case <-expired:
+				now := time.Now()
+				for len(queue) > 0 && !queue[0].deadline.After(now) {
+					m.flush(out, queue[0])
+					delete(windows, queue[0].key)
+					queue = queue[1:]
+				}
+			case e, ok := <-in:
+				if !ok {
+					level.Debug(m.logger).Log("msg", "flush dedup windows because inbound closed", "windows", len(queue))
+					for _, w := range queue {
+						m.flush(out, w)
+					}
+					return
+				}
+
+				key := dedupKey{stream: e.Labels.FastFingerprint(), line: xxhash.Sum64String(m.mask(e.Line))}
+				if w, ok := windows[key]; ok {
+					w.last = e
+					w.suppressed++
+					m.dropCount.WithLabelValues(dedupDropReason).Inc()
+					continue
+				}
+
+				w := &dedupWindow{key: key, deadline: time.Now().Add(m.cfg.Window)}
+				windows[key] = w
+				queue = append(queue, w)
+				out <- e
+			}
+		}
+	}()
+	return out
+}
+
+// mask replaces the parts of the line which are ignored when comparing lines.
+func (m *dedupStage) mask(line string) string {
+	if m.cfg.MaskUUIDs {
+		line = dedupUUIDRegex.ReplaceAllLiteralString(line, "<uuid>")
+	}
+	if m.cfg.MaskNumbers {
+		line = dedupNumberRegex.ReplaceAllLiteralString(line, "<number>")
+	}
+	return line
+}
+
+// flush emits the summary line of a window, if it suppressed any lines. The
+// summary line is the last suppressed line followed by the number of
+// suppressed lines.
+func (m *dedupStage) flush(out chan Entry, w *dedupWindow) {
+	if w.suppressed == 0 {
+		return
+	}
+
+	// copy extracted data.
+	extracted := make(map[string]interface{}, len(w.last.Extracted))
+	for k, v := range w.last.Extracted {
+		extracted[k] = v
+	}
+	out <- Entry{
+		Extracted: extracted,
+		Entry: loki.Entry{
+			Labels: w.last.Labels.Clone(),
+			Entry: logproto.Entry{
+				Timestamp:          w.last.Timestamp,
+				Line:               fmt.Sprintf("%s [repeated %d times]", w.last.Line, w.suppressed),
+				StructuredMetadata: w.last.StructuredMetadata,
+			},
+		},
+	}
+}
+
+// Name implements Stage
+func (m *dedupStage) Name() string {
+	return StageTypeDedup
+}
+
+// Cleanup implements Stage.
+func (*dedupStage) Cleanup() {
+	// no-op
+}
@@ -0,0 +1,108 @@
+package stages
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/util"
+)
+
+func TestDedupStageProcess(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	stage := newDedupStage(util.TestAlloyLogger(t), DedupConfig{Window: time.Minute}, registry)
+
+	out := processEntries(stage,
+		simpleEntry("connection refused", "one"),
+		simpleEntry("connection refused", "one"),
+		simpleEntry("connection refused", "two"),
+		simpleEntry("request served", "one"),
+		simpleEntry("connection refused", "one"),
+	)
+
+	// The windows are flushed when the input is closed.
+	require.Len(t, out, 4)
+	require.Equal(t, "connection refused", out[0].Line)
+	require.Equal(t, model.LabelValue("one"), out[0].Labels["value"])
+	require.Equal(t, "connection refused", out[1].Line)
+	require.Equal(t, model.LabelValue("two"), out[1].Labels["value"])
+	require.Equal(t, "request served", out[2].Line)
+	require.Equal(t, "connection refused [repeated 2 times]", out[3].Line)
+	require.Equal(t, model.LabelValue("one"), out[3].Labels["value"])
+
+	require.Equal(t, 2.0, testutil.ToFloat64(stage.(*dedupStage).dropCount.WithLabelValues(dedupDropReason)))
+}
+
+func TestDedupStageMask(t *testing.T) {
+	stage := newDedupStage(util.TestAlloyLogger(t), DedupConfig{
+		Window:      time.Minute,
+		MaskNumbers: true,
+		MaskUUIDs:   true,
+	}, prometheus.NewRegistry())
+
+	out := processEntries(stage,
+		simpleEntry("request 4f1c8e2a-9b3d-4c5e-8f7a-1b2c3d4e5f60 failed after 120ms", "one"),
+		simpleEntry("request 0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d failed after 95ms", "one"),
+		simpleEntry("request 5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9 failed after 3ms", "one"),
+		simpleEntry("request served", "one"),
+	)
+
+	require.Len(t, out, 3)
+	require.Equal(t, "request 4f1c8e2a-9b3d-4c5e-8f7a-1b2c3d4e5f60 failed after 120ms", out[0].Line)
+	require.Equal(t, "request served", out[1].Line)
+	// The summary line is the last suppressed line.
+	require.Equal(t, "request 5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9 failed after 3ms [repeated 2 times]", out[2].Line)
+}
+
+func TestDedupStageWindow(t *testing.T) {
+	stage := newDedupStage(util.TestAlloyLogger(t), DedupConfig{Window: 100 * time.Millisecond}, prometheus.NewRegistry())
+
+	in := make(chan Entry, 3)
+	out := stage.Run(in)
+
+	// Accumulate result
+	mu := new(sync.Mutex)
+	var res []Entry
+	go func() {
+		for e := range out {
+			mu.Lock()
+			res = append(res, e)
+			mu.Unlock()
+		}
+	}()
+
+	in <- simpleEntry("crash", "label")
+	in <- simpleEntry("crash", "label")
+	in <- simpleEntry("crash", "label")
+
+	// The summary line is emitted when the window closes, without waiting for
+	// more lines.
+	require.Eventually(t, func() bool { mu.Lock(); defer mu.Unlock(); return len(res) == 2 }, 2*time.Second, 20*time.Millisecond)
+
+	// A new window starts with the next line.
+	in <- simpleEntry("crash", "label")
+	close(in)
+
+	require.Eventually(t, func() bool { mu.Lock(); defer mu.Unlock(); return len(res) == 3 }, 2*time.Second, 20*time.Millisecond)
+	require.Equal(t, "crash", res[0].Line)
+	require.Equal(t, "crash [repeated 2 times]", res[1].Line)
+	require.Equal(t, "crash", res[2].Line)
+}
+
+func TestDedupStageStability(t *testing.T) {
+	cfg := `
+stage.dedup {
+	window = "5s"
+}`
+	_, err := NewPipeline(util.TestAlloyLogger(t), loadConfig(cfg), nil, prometheus.NewRegistry(), featuregate.StabilityGenerallyAvailable)
+	require.ErrorContains(t, err, `stage "dedup" is at stability level "experimental"`)
+
+	_, err = NewPipeline(util.TestAlloyLogger(t), loadConfig(cfg), nil, prometheus.NewRegistry(), featuregate.StabilityExperimental)
+	require.NoError(t, err)
+}
@@ -0,0 +1,176 @@
+package stages
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// drainWildcard is the template token of the variable parts of a pattern.
+const drainWildcard = "<*>"
+
+// drain clusters log lines into patterns with the Drain algorithm, described
+// in "Drain: An Online Log Parsing Approach with Fixed Depth Tree" by Pinjia
+// He et al.
+//
+// The lines are routed through a tree of fixed depth by their number of
+// tokens and their first tokens. The leaves of the tree hold the clusters of
+// the lines routed to them, and a line joins the most similar cluster of its
+// leaf, or creates a new cluster.
+type drain struct {
+	nextID        func() uint64
+	maxDepth      int
+	maxChildren   int
+	maxClusters   int
+	simThreshold  float64
+	root          *drainNode
+	clusters      *list.List // The clusters, from the most to the least recently used.
+	clustersCount int
+}
+
+type drainNode struct {
+	children map[string]*drainNode
+	clusters []*drainCluster
+}
+
+type drainCluster struct {
+	// id identifies the cluster for its whole lifetime, while its template
+	// becomes more general.
+	id     uint64
+	tokens []string
+	node   *drainNode
+	elem   *list.Element
+}
+
+// newDrain creates a drain whose clusters get their IDs from nextID.
+func newDrain(nextID func() uint64, maxDepth, maxChildren, maxClusters int, simThreshold float64) *drain {
+	return &drain{
+		nextID:       nextID,
+		maxDepth:     maxDepth,
+		maxChildren:  maxChildren,
+		maxClusters:  maxClusters,
+		simThreshold: simThreshold,
+		root:         newDrainNode(),
+		clusters:     list.New(),
+	}
+}
+
+func newDrainNode() *drainNode {
+	return &drainNode{children: make(map[string]*drainNode)}
+}
+
+// train adds a line to its cluster, and returns the ID and the template of the
+// cluster. It returns false if the line has no tokens.
+func (d *drain) train(line string) (uint64, string, bool) {
+	tokens := strings.Fields(line)
+	if len(tokens) == 0 {
+		return 0, "", false
+	}
+
+	leaf := d.leaf(tokens)
+	cluster := d.match(leaf, tokens)
+	if cluster == nil {
+		cluster = &drainCluster{id: d.nextID(), tokens: tokens, node: leaf}
+		cluster.elem = d.clusters.PushFront(cluster)
+		leaf.clusters = append(leaf.clusters, cluster)
+		d.clustersCount++
+		d.evict()
+	} else {
+		for i, token := range tokens {
+			if cluster.tokens[i] != token {
+				cluster.tokens[i] = drainWildcard
+			}
+		}
+		d.clusters.MoveToFront(cluster.elem)
+	}
+	return cluster.id, strings.Join(cluster.tokens, " "), true
+}
+
+// leaf returns the leaf node of the tree the tokens are routed to, and creates
+// the missing nodes.
+func (d *drain) leaf(tokens []string) *drainNode {
+	node := d.child(d.root, strconv.Itoa(len(tokens)), false)
+
+	// The first level of the tree is the number of tokens, and the last level
+	// holds the clusters.
+	for i := 0; i < d.maxDepth-2 && i < len(tokens); i++ {
+		node = d.child(node, tokens[i], true)
+	}
+	return node
+}
+
+// child returns the child node of a token. When the token may be a variable,
+// that is when it has digits or the node has too many children, the wildcard
+// node is returned instead.
+func (d *drain) child(node *drainNode, token string, mayBeVariable bool) *drainNode {
+	if child, ok := node.children[token]; ok {
+		return child
+	}
+	if mayBeVariable && (hasDigit(token) || len(node.children) >= d.maxChildren-1) {
+		token = drainWildcard
+		if child, ok := node.children[token]; ok {
+			return child
+		}
+	}
+
+	child := newDrainNode()
+	node.children[token] = child
+	return child
+}
+
+// match returns the most similar cluster of a leaf, or nil if no cluster is
+// similar enough.
+func (d *drain) match(leaf *drainNode, tokens []string) *drainCluster {
+	var (
+		best          *drainCluster
+		bestSim       = -1.0
+		bestWildcards = -1
+	)
+	for _, cluster := range leaf.clusters {
+		sim, wildcards := similarity(cluster.tokens, tokens)
+		if sim > bestSim || (sim == bestSim && wildcards > bestWildcards) {
+			best, bestSim, bestWildcards = cluster, sim, wildcards
+		}
+	}
+	if best == nil || bestSim < d.simThreshold {
+		return nil
+	}
+	return best
+}
+
+// evict removes the least recently used clusters over the maximum number of
+// clusters.
+func (d *drain) evict() {
+	for d.clustersCount > d.maxClusters {
+		cluster := d.clusters.Remove(d.clusters.Back()).(*drainCluster)
+		clusters := cluster.node.clusters
+		for i, c := range clusters {
+			if c == cluster {
+				cluster.node.clusters = append(clusters[:i], clusters[i+1:]...)
+				break
+			}
+		}
+		d.clustersCount--
+	}
+}
+
+// similarity returns the share of the tokens which are the same as the tokens
+// of a template of the same length, and the number of wildcards of the
+// template.
+func similarity(template, tokens []string) (float64, int) {
+	var same, wildcards int
+	for i, token := range template {
+		switch token {
+		case drainWildcard:
+			wildcards++
+		case tokens[i]:
+			same++
+		}
+	}
+	return float64(same) / float64(len(template)), wildcards
+}
+
+func hasDigit(s string) bool {
+	return strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
@@ -122,12 +122,13 @@ func (m *metricStage) Process(labels model.LabelSet, extracted map[string]interf
 		// There is a special case for counters where we count even if there is no match in the extracted map.
 		if c, ok := cc.collector.(*metric.Counters); ok {
 			if c != nil && c.Cfg.MatchAll {
+				exemplar := exemplarLabels(c.Cfg.ExemplarSource, c.Cfg.ExemplarLabel, extracted)
 				if c.Cfg.CountEntryBytes {
 					if entry != nil {
-						m.recordCounter(name, c, labels, len(*entry))
+						m.recordCounter(name, c, labels, len(*entry), exemplar)
 					}
 				} else {
-					m.recordCounter(name, c, labels, nil)
+					m.recordCounter(name, c, labels, nil, exemplar)
 				}
 				continue
 			}
@@ -135,7 +136,8 @@ func (m *metricStage) Process(labels model.LabelSet, extracted map[string]interf
 		switch {
 		case cc.cfg.Counter != nil:
 			if v, ok := extracted[cc.cfg.Counter.Source]; ok {
-				m.recordCounter(name, cc.collector.(*metric.Counters), labels, v)
+				exemplar := exemplarLabels(cc.cfg.Counter.ExemplarSource, cc.cfg.Counter.ExemplarLabel, extracted)
+				m.recordCounter(name, cc.collector.(*metric.Counters), labels, v, exemplar)
 			} else {
 				level.Debug(m.logger).Log("msg", "source does not exist", "err", fmt.Sprintf("source: %s, does not exist", cc.cfg.Counter.Source))
 			}
@@ -147,7 +149,8 @@ func (m *metricStage) Process(labels model.LabelSet, extracted map[string]interf
 			}
 		case cc.cfg.Histogram != nil:
 			if v, ok := extracted[cc.cfg.Histogram.Source]; ok {
-				m.recordHistogram(name, cc.collector.(*metric.Histograms), labels, v)
+				exemplar := exemplarLabels(cc.cfg.Histogram.ExemplarSource, cc.cfg.Histogram.ExemplarLabel, extracted)
+				m.recordHistogram(name, cc.collector.(*metric.Histograms), labels, v, exemplar)
 			} else {
 				level.Debug(m.logger).Log("msg", "source does not exist", "err", fmt.Sprintf("source: %s, does not exist", cc.cfg.Histogram.Source))
 			}
@@ -174,8 +177,26 @@ func (m *metricStage) Cleanup() {
 	}
 }
 
-// recordCounter will update a counter metric
-func (m *metricStage) recordCounter(name string, counter *metric.Counters, labels model.LabelSet, v interface{}) {
+// exemplarLabels returns the exemplar labels of a metric with an exemplar
+// source, or nil if the source wasn't extracted or is empty.
+func exemplarLabels(source, label string, extracted map[string]interface{}) prometheus.Labels {
+	if source == "" {
+		return nil
+	}
+	v, ok := extracted[source]
+	if !ok {
+		return nil
+	}
+	traceID, err := getString(v)
+	if err != nil {
+		return nil
+	}
+	return metric.ExemplarLabels(label, traceID)
+}
+
+// recordCounter will update a counter metric. The exemplar is attached to the
+// new value when it isn't nil.
+func (m *metricStage) recordCounter(name string, counter *metric.Counters, labels model.LabelSet, v interface{}, exemplar prometheus.Labels) {
 	// If value matching is defined, make sure value matches.
 	if counter.Cfg.Value != "" {
 		stringVal, err := getString(v)
@@ -192,19 +213,27 @@ func (m *metricStage) recordCounter(name string, counter *metric.Counters, label
 		}
 	}
 
+	var f float64
 	switch counter.Cfg.Action {
 	case metric.CounterInc:
-		counter.With(labels).Inc()
+		f = 1
 	case metric.CounterAdd:
-		f, err := getFloat(v)
+		var err error
+		f, err = getFloat(v)
 		if err != nil {
 			if Debug {
 				level.Debug(m.logger).Log("msg", "failed to convert extracted value to positive float", "metric", name, "err", err)
 			}
 			return
 		}
-		counter.With(labels).Add(f)
 	}
+
+	c := counter.With(labels)
+	if exemplar != nil {
+		c.(prometheus.ExemplarAdder).AddWithExemplar(f, exemplar)
+		return
+	}
+	c.Add(f)
 }
 
 // recordGauge will update a gauge metric
@@ -260,8 +289,9 @@ func (m *metricStage) recordGauge(name string, gauge *metric.Gauges, labels mode
 	}
 }
 
-// recordHistogram will update a Histogram metric
-func (m *metricStage) recordHistogram(name string, histogram *metric.Histograms, labels model.LabelSet, v interface{}) {
+// recordHistogram will update a Histogram metric. The exemplar is attached to
+// the observation when it isn't nil.
+func (m *metricStage) recordHistogram(name string, histogram *metric.Histograms, labels model.LabelSet, v interface{}, exemplar prometheus.Labels) {
 	// If value matching is defined, make sure value matches.
 	if histogram.Cfg.Value != "" {
 		stringVal, err := getString(v)
@@ -284,7 +314,12 @@ func (m *metricStage) recordHistogram(name string, histogram *metric.Histograms,
 		}
 		return
 	}
-	histogram.With(labels).Observe(f)
+	h := histogram.With(labels)
+	if exemplar != nil {
+		h.(prometheus.ExemplarObserver).ObserveWithExemplar(f, exemplar)
+		return
+	}
+	h.Observe(f)
 }
 
 // getFloat will take the provided value and return a float64 if possible
@@ -475,6 +475,67 @@ func TestMetricStage_Process(t *testing.T) {
 	}
 }
 
+func TestMetricsWithExemplars(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	testConfig := `
+stage.json {
+	expressions = { "trace_id" = "trace_id", "duration" = "duration" }
+}
+stage.metrics {
+	metric.counter {
+		name            = "requests_total"
+		match_all       = true
+		action          = "inc"
+		exemplar_source = "trace_id"
+	}
+	metric.histogram {
+		name            = "request_duration_seconds"
+		source          = "duration"
+		buckets         = [0.1, 1]
+		exemplar_source = "trace_id"
+		exemplar_label  = "traceID"
+	}
+}`
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testConfig), nil, registry, featuregate.StabilityGenerallyAvailable)
+	require.NoError(t, err)
+
+	processEntries(pl,
+		newEntry(nil, model.LabelSet{"app": "api"}, `{"trace_id": "abc123", "duration": "0.5"}`, time.Now()),
+		// Entries without a trace ID are still counted, without exemplars.
+		newEntry(nil, model.LabelSet{"app": "api"}, `{"duration": "2"}`, time.Now()),
+	)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 2)
+
+	for _, mf := range families {
+		require.Len(t, mf.Metric, 1)
+		m := mf.Metric[0]
+
+		switch mf.GetName() {
+		case "loki_process_custom_requests_total":
+			require.Equal(t, 2.0, m.Counter.GetValue())
+			require.NotNil(t, m.Counter.Exemplar)
+			require.Equal(t, 1.0, m.Counter.Exemplar.GetValue())
+			require.Len(t, m.Counter.Exemplar.Label, 1)
+			require.Equal(t, "trace_id", m.Counter.Exemplar.Label[0].GetName())
+			require.Equal(t, "abc123", m.Counter.Exemplar.Label[0].GetValue())
+		case "loki_process_custom_request_duration_seconds":
+			require.Equal(t, uint64(2), m.Histogram.GetSampleCount())
+			buckets := m.Histogram.Bucket
+			require.Len(t, buckets, 2)
+			require.Nil(t, buckets[0].Exemplar)
+			require.NotNil(t, buckets[1].Exemplar)
+			require.Equal(t, 0.5, buckets[1].Exemplar.GetValue())
+			require.Equal(t, "traceID", buckets[1].Exemplar.Label[0].GetName())
+			require.Equal(t, "abc123", buckets[1].Exemplar.Label[0].GetValue())
+		default:
+			t.Fatalf("unexpected metric %s", mf.GetName())
+		}
+	}
+}
+
 func metricNames(sc StageConfig) []string {
 	cfg := sc.MetricsConfig
 	result := make([]string, 0, len(cfg.Metrics))
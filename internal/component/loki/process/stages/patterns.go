@@ -0,0 +1,213 @@
+package stages
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component/loki/process/metric"
+)
+
+const (
+	patternsLinesMetric = "loki_process_pattern_lines_total"
+	patternsBytesMetric = "loki_process_pattern_bytes_total"
+)
+
+// PatternsConfig contains the configuration for a patterns stage.
+type PatternsConfig struct {
+	IDKey               string        `alloy:"id_key,attr,optional"`
+	TemplateKey         string        `alloy:"template_key,attr,optional"`
+	SimilarityThreshold float64       `alloy:"similarity_threshold,attr,optional"`
+	MaxDepth            int           `alloy:"max_depth,attr,optional"`
+	MaxChildren         int           `alloy:"max_children,attr,optional"`
+	MaxClusters         int           `alloy:"max_clusters,attr,optional"`
+	MaxIdle             time.Duration `alloy:"max_idle_duration,attr,optional"`
+}
+
+// DefaultPatternsConfig applies the default values on PatternsConfig.
+var DefaultPatternsConfig = PatternsConfig{
+	IDKey:               "pattern_id",
+	TemplateKey:         "pattern",
+	SimilarityThreshold: 0.4,
+	MaxDepth:            4,
+	MaxChildren:         100,
+	MaxClusters:         300,
+	MaxIdle:             5 * time.Minute,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *PatternsConfig) SetToDefault() {
+	*args = DefaultPatternsConfig
+}
+
+// Validate implements syntax.Validator.
+func (args *PatternsConfig) Validate() error {
+	if args.IDKey == "" {
+		return fmt.Errorf("id_key must not be empty")
+	}
+	if args.SimilarityThreshold < 0 || args.SimilarityThreshold > 1 {
+		return fmt.Errorf("similarity_threshold must be between 0 and 1, got %f", args.SimilarityThreshold)
+	}
+	if args.MaxDepth < 3 {
+		return fmt.Errorf("max_depth must be greater or equal than 3")
+	}
+	if args.MaxChildren < 2 {
+		return fmt.Errorf("max_children must be greater or equal than 2")
+	}
+	if args.MaxClusters <= 0 {
+		return fmt.Errorf("max_clusters must be greater than 0")
+	}
+	if args.MaxIdle < 1*time.Second {
+		return fmt.Errorf("max_idle_duration must be greater or equal than 1s")
+	}
+	return nil
+}
+
+// patternsStage clusters the lines of every stream into patterns, and
+// extracts the pattern of every line.
+type patternsStage struct {
+	logger  log.Logger
+	cfg     PatternsConfig
+	lines   *metric.Counters
+	bytes   *metric.Counters
+	streams map[model.Fingerprint]*patternsStream
+	// lastID is the ID of the last pattern created in any stream.
+	lastID uint64
+
+	lastEviction time.Time
+}
+
+// patternsStream holds the patterns of a stream.
+type patternsStream struct {
+	drain    *drain
+	lastSeen time.Time
+}
+
+func newPatternsStage(logger log.Logger, config PatternsConfig, registerer prometheus.Registerer) (Stage, error) {
+	lines, err := metric.NewCounters(patternsLinesMetric, &metric.CounterConfig{
+		Description: "Number of log lines per pattern",
+		Action:      metric.CounterAdd,
+		MaxIdle:     config.MaxIdle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := metric.NewCounters(patternsBytesMetric, &metric.CounterConfig{
+		Description: "Number of bytes of log lines per pattern",
+		Action:      metric.CounterAdd,
+		MaxIdle:     config.MaxIdle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// It is safe to .MustRegister here because the metrics created above are unchecked.
+	registerer.MustRegister(lines, bytes)
+
+	s := &patternsStage{
+		logger:       log.With(logger, "component", "stage", "type", "patterns"),
+		cfg:          config,
+		lines:        lines,
+		bytes:        bytes,
+		streams:      make(map[model.Fingerprint]*patternsStream),
+		lastEviction: time.Now(),
+	}
+	return s, nil
+}
+
+// Run implements Stage. The pattern is extracted like a Processor, and is
+// attached to the entry as structured metadata.
+func (m *patternsStage) Run(in chan Entry) chan Entry {
+	return RunWith(in, func(e Entry) Entry {
+		m.Process(e.Labels, e.Extracted, &e.Timestamp, &e.Line)
+
+		for _, key := range []string{m.cfg.IDKey, m.cfg.TemplateKey} {
+			if key == "" {
+				continue
+			}
+			if v, ok := e.Extracted[key].(string); ok {
+				e.StructuredMetadata = append(e.StructuredMetadata, logproto.LabelAdapter{Name: key, Value: v})
+			}
+		}
+		return e
+	})
+}
+
+// Process implements Processor.
+func (m *patternsStage) Process(labels model.LabelSet, extracted map[string]interface{}, _ *time.Time, entry *string) {
+	if entry == nil {
+		return
+	}
+
+	now := time.Now()
+	m.evictIdleStreams(now)
+
+	fp := labels.FastFingerprint()
+	stream, ok := m.streams[fp]
+	if !ok {
+		stream = &patternsStream{
+			drain: newDrain(m.nextPatternID, m.cfg.MaxDepth, m.cfg.MaxChildren, m.cfg.MaxClusters, m.cfg.SimilarityThreshold),
+		}
+		m.streams[fp] = stream
+	}
+	stream.lastSeen = now
+
+	clusterID, template, ok := stream.drain.train(*entry)
+	if !ok {
+		return
+	}
+	id := patternID(clusterID)
+
+	extracted[m.cfg.IDKey] = id
+	if m.cfg.TemplateKey != "" {
+		extracted[m.cfg.TemplateKey] = template
+	}
+
+	// The template isn't used as a label, since it changes while the pattern
+	// becomes more general.
+	metricLabels := model.LabelSet{"pattern_id": model.LabelValue(id)}
+	m.lines.With(metricLabels).Inc()
+	m.bytes.With(metricLabels).Add(float64(len(*entry)))
+}
+
+// evictIdleStreams forgets the patterns of the streams without lines within
+// max_idle_duration.
+func (m *patternsStage) evictIdleStreams(now time.Time) {
+	if now.Sub(m.lastEviction) < m.cfg.MaxIdle {
+		return
+	}
+	m.lastEviction = now
+
+	for fp, stream := range m.streams {
+		if now.Sub(stream.lastSeen) >= m.cfg.MaxIdle {
+			delete(m.streams, fp)
+		}
+	}
+}
+
+// nextPatternID returns the ID of a new pattern. IDs are unique across the
+// streams of the stage.
+func (m *patternsStage) nextPatternID() uint64 {
+	m.lastID++
+	return m.lastID
+}
+
+// patternID formats the ID of the Drain cluster of a pattern.
+func patternID(clusterID uint64) string {
+	return strconv.FormatUint(clusterID, 10)
+}
+
+// Name implements Stage.
+func (m *patternsStage) Name() string {
+	return StageTypePatterns
+}
+
+// Cleanup implements Stage.
+func (m *patternsStage) Cleanup() {
+	m.lines.DeleteAll()
+	m.bytes.DeleteAll()
+}
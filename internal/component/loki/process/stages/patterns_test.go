@@ -0,0 +1,129 @@
+package stages
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/loki/pkg/push"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+// testDrainIDs returns a function which generates sequential cluster IDs.
+func testDrainIDs() func() uint64 {
+	var id uint64
+	return func() uint64 {
+		id++
+		return id
+	}
+}
+
+func TestDrain(t *testing.T) {
+	d := newDrain(testDrainIDs(), 4, 100, 300, 0.4)
+
+	tt := []struct {
+		line     string
+		id       uint64
+		template string
+	}{
+		{"login succeeded for user alice from 10.0.0.1", 1, "login succeeded for user alice from 10.0.0.1"},
+		// The cluster keeps its ID while its template becomes more general.
+		{"login succeeded for user bob from 10.0.0.2", 1, "login succeeded for user <*> from <*>"},
+		{"login succeeded for user carol from 10.0.0.3", 1, "login succeeded for user <*> from <*>"},
+		// Lines with a different number of tokens are in different clusters.
+		{"login failed for user dave", 2, "login failed for user dave"},
+		// Lines with different first tokens are in different clusters.
+		{"login failed for user erin from 10.0.0.5", 3, "login failed for user erin from 10.0.0.5"},
+		{"login succeeded for user frank from 10.0.0.4", 1, "login succeeded for user <*> from <*>"},
+	}
+	for _, tc := range tt {
+		id, template, ok := d.train(tc.line)
+		require.True(t, ok)
+		require.Equal(t, tc.id, id, tc.line)
+		require.Equal(t, tc.template, template, tc.line)
+	}
+
+	_, _, ok := d.train("   ")
+	require.False(t, ok)
+}
+
+func TestDrainMaxClusters(t *testing.T) {
+	d := newDrain(testDrainIDs(), 4, 100, 2, 0.4)
+
+	d.train("first pattern of lines")
+	d.train("a completely different one")
+	d.train("first pattern of lines")
+	// The least recently used cluster is evicted.
+	d.train("yet another unrelated line")
+	require.Equal(t, 2, d.clustersCount)
+
+	_, template, _ := d.train("first pattern of logs")
+	require.Equal(t, "first pattern of <*>", template)
+	// The evicted cluster is created again with a new ID.
+	id, template, _ := d.train("a completely different two")
+	require.Equal(t, uint64(4), id)
+	require.Equal(t, "a completely different two", template)
+}
+
+var testPatternsAlloy = `
+stage.patterns {
+	max_idle_duration = "1m"
+}
+stage.labels {
+	values = { "pattern_id" = "" }
+}
+`
+
+func TestPatternsStage(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testPatternsAlloy), nil, registry, featuregate.StabilityExperimental)
+	require.NoError(t, err)
+
+	lines := []string{
+		"GET /api/users 200 12ms",
+		"GET /api/users 200 15ms",
+		"GET /api/orders 500 3ms",
+	}
+	var out []Entry
+	for _, line := range lines {
+		out = append(out, processEntries(pl, newEntry(nil, nil, line, time.Now()))...)
+	}
+	require.Len(t, out, 3)
+
+	expectTemplates := []string{
+		"GET /api/users 200 12ms",
+		"GET /api/users 200 <*>",
+		"GET /api/orders 500 3ms",
+	}
+	// The first two lines belong to the same pattern, whose ID doesn't change
+	// when its template becomes more general.
+	expectIDs := []string{"1", "1", "2"}
+	for i, e := range out {
+		id := expectIDs[i]
+		require.Equal(t, expectTemplates[i], e.Extracted["pattern"])
+		require.Equal(t, id, e.Extracted["pattern_id"])
+		require.Equal(t, id, string(e.Labels["pattern_id"]))
+		require.Equal(t, push.LabelsAdapter{
+			{Name: "pattern_id", Value: id},
+			{Name: "pattern", Value: expectTemplates[i]},
+		}, e.StructuredMetadata)
+	}
+
+	expect := `
+# HELP loki_process_pattern_lines_total Number of log lines per pattern
+# TYPE loki_process_pattern_lines_total counter
+loki_process_pattern_lines_total{pattern_id="1"} 2
+loki_process_pattern_lines_total{pattern_id="2"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expect), patternsLinesMetric))
+}
+
+func TestPatternsStageStability(t *testing.T) {
+	_, err := NewPipeline(util_log.Logger, loadConfig(testPatternsAlloy), nil, prometheus.NewRegistry(), featuregate.StabilityGenerallyAvailable)
+	require.ErrorContains(t, err, `stage "patterns" is at stability level "experimental"`)
+}
@@ -19,6 +19,7 @@ import (
 type StageConfig struct {
 	CRIConfig             *CRIConfig             `alloy:"cri,block,optional"`
 	DecolorizeConfig      *DecolorizeConfig      `alloy:"decolorize,block,optional"`
+	DedupConfig           *DedupConfig           `alloy:"dedup,block,optional"`
 	DockerConfig          *DockerConfig          `alloy:"docker,block,optional"`
 	DropConfig            *DropConfig            `alloy:"drop,block,optional"`
 	EventLogMessageConfig *EventLogMessageConfig `alloy:"eventlogmessage,block,optional"`
@@ -35,6 +36,7 @@ type StageConfig struct {
 	MultilineConfig       *MultilineConfig       `alloy:"multiline,block,optional"`
 	OutputConfig          *OutputConfig          `alloy:"output,block,optional"`
 	PackConfig            *PackConfig            `alloy:"pack,block,optional"`
+	PatternsConfig        *PatternsConfig        `alloy:"patterns,block,optional"`
 	RegexConfig           *RegexConfig           `alloy:"regex,block,optional"`
 	ReplaceConfig         *ReplaceConfig         `alloy:"replace,block,optional"`
 	StaticLabelsConfig    *StaticLabelsConfig    `alloy:"static_labels,block,optional"`
@@ -44,6 +46,7 @@ type StageConfig struct {
 	TenantConfig          *TenantConfig          `alloy:"tenant,block,optional"`
 	TimestampConfig       *TimestampConfig       `alloy:"timestamp,block,optional"`
 	WindowsEventConfig    *WindowsEventConfig    `alloy:"windowsevent,block,optional"`
+	XMLConfig             *XMLConfig             `alloy:"xml,block,optional"`
 }
 
 var rateLimiter *rate.Limiter
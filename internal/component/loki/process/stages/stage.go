@@ -18,6 +18,7 @@ import (
 const (
 	StageTypeCRI        = "cri"
 	StageTypeDecolorize = "decolorize"
+	StageTypeDedup      = "dedup"
 	StageTypeDocker     = "docker"
 	StageTypeDrop       = "drop"
 	//TODO(thampiotr): Add support for eventlogmessage stage
@@ -35,6 +36,7 @@ const (
 	StageTypeMultiline          = "multiline"
 	StageTypeOutput             = "output"
 	StageTypePack               = "pack"
+	StageTypePatterns           = "patterns"
 	StageTypePipeline           = "pipeline"
 	StageTypeRegex              = "regex"
 	StageTypeReplace            = "replace"
@@ -45,10 +47,13 @@ const (
 	StageTypeTenant             = "tenant"
 	StageTypeTimestamp          = "timestamp"
 	StageTypeWindowsEvent       = "windowsevent"
+	StageTypeXML                = "xml"
 )
 
 // Add stages that are not GA. Stages that are not specified here are considered GA.
 var stagesUnstable = map[string]featuregate.Stability{
+	StageTypeDedup:        featuregate.StabilityExperimental,
+	StageTypePatterns:     featuregate.StabilityExperimental,
 	StageTypeWindowsEvent: featuregate.StabilityExperimental,
 }
 
@@ -218,11 +223,18 @@ func New(logger log.Logger, jobName *string, cfg StageConfig, registerer prometh
 		if err != nil {
 			return nil, err
 		}
+	case cfg.DedupConfig != nil:
+		s = newDedupStage(logger, *cfg.DedupConfig, registerer)
 	case cfg.MultilineConfig != nil:
 		s, err = newMultilineStage(logger, *cfg.MultilineConfig)
 		if err != nil {
 			return nil, err
 		}
+	case cfg.PatternsConfig != nil:
+		s, err = newPatternsStage(logger, *cfg.PatternsConfig, registerer)
+		if err != nil {
+			return nil, err
+		}
 	case cfg.PackConfig != nil:
 		s = newPackStage(logger, *cfg.PackConfig, registerer)
 	case cfg.LabelAllowConfig != nil:
@@ -256,6 +268,11 @@ func New(logger log.Logger, jobName *string, cfg StageConfig, registerer prometh
 		s = newEventLogMessageStage(logger, cfg.EventLogMessageConfig)
 	case cfg.WindowsEventConfig != nil:
 		s = newWindowsEventStage(logger, cfg.WindowsEventConfig)
+	case cfg.XMLConfig != nil:
+		s, err = newXMLStage(logger, *cfg.XMLConfig)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		panic(fmt.Sprintf("unreachable; should have decoded into one of the StageConfig fields: %+v", cfg))
 	}
@@ -0,0 +1,201 @@
+package stages
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// Config Errors
+const (
+	ErrXPathExpressionsRequired = "XPath expression is required"
+	ErrCouldNotCompileXPath     = "could not compile XPath expression"
+	ErrEmptyXMLStageConfig      = "empty xml stage configuration"
+	ErrEmptyXMLStageSource      = "empty source"
+	ErrMalformedXML             = "malformed xml"
+)
+
+// XMLConfig represents an XML Stage configuration
+type XMLConfig struct {
+	Expressions   map[string]string `alloy:"expressions,attr"`
+	Source        *string           `alloy:"source,attr,optional"`
+	DropMalformed bool              `alloy:"drop_malformed,attr,optional"`
+}
+
+// validateXMLConfig validates an xml config and returns a map of necessary XPath expressions.
+func validateXMLConfig(c *XMLConfig) (map[string]*xpath.Expr, error) {
+	if c == nil {
+		return nil, errors.New(ErrEmptyXMLStageConfig)
+	}
+
+	if len(c.Expressions) == 0 {
+		return nil, errors.New(ErrXPathExpressionsRequired)
+	}
+
+	if c.Source != nil && *c.Source == "" {
+		return nil, errors.New(ErrEmptyXMLStageSource)
+	}
+
+	expressions := map[string]*xpath.Expr{}
+
+	for n, e := range c.Expressions {
+		var err error
+		expr := e
+		// If there is no expression, select the first element named like the key.
+		if e == "" {
+			expr = "//" + n
+		}
+		expressions[n], err = xpath.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrCouldNotCompileXPath, err)
+		}
+	}
+	return expressions, nil
+}
+
+// xmlStage sets extracted data using XPath expressions
+type xmlStage struct {
+	cfg         *XMLConfig
+	expressions map[string]*xpath.Expr
+	logger      log.Logger
+}
+
+// newXMLStage creates a new xml pipeline stage from a config.
+func newXMLStage(logger log.Logger, cfg XMLConfig) (Stage, error) {
+	expressions, err := validateXMLConfig(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &xmlStage{
+		cfg:         &cfg,
+		expressions: expressions,
+		logger:      log.With(logger, "component", "stage", "type", "xml"),
+	}, nil
+}
+
+func (x *xmlStage) Run(in chan Entry) chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+		for e := range in {
+			err := x.processEntry(e.Extracted, &e.Line)
+			if err != nil && x.cfg.DropMalformed {
+				continue
+			}
+			out <- e
+		}
+	}()
+	return out
+}
+
+func (x *xmlStage) processEntry(extracted map[string]interface{}, entry *string) error {
+	// If a source key is provided, the xml stage should process it
+	// from the extracted map, otherwise should fall back to the entry
+	input := entry
+
+	if x.cfg.Source != nil {
+		if _, ok := extracted[*x.cfg.Source]; !ok {
+			if Debug {
+				level.Debug(x.logger).Log("msg", "source does not exist in the set of extracted values", "source", *x.cfg.Source)
+			}
+			return nil
+		}
+
+		value, err := getString(extracted[*x.cfg.Source])
+		if err != nil {
+			if Debug {
+				level.Debug(x.logger).Log("msg", "failed to convert source value to string", "source", *x.cfg.Source, "err", err, "type", reflect.TypeOf(extracted[*x.cfg.Source]))
+			}
+			return nil
+		}
+
+		input = &value
+	}
+
+	if input == nil {
+		if Debug {
+			level.Debug(x.logger).Log("msg", "cannot parse a nil entry")
+		}
+		return nil
+	}
+
+	doc, err := xmlquery.Parse(strings.NewReader(*input))
+	if err == nil && !hasElement(doc) {
+		err = errors.New("no root element")
+	}
+	if err != nil {
+		if Debug {
+			level.Debug(x.logger).Log("msg", "failed to parse log line as xml", "err", err)
+		}
+		return errors.New(ErrMalformedXML)
+	}
+
+	nav := xmlquery.CreateXPathNavigator(doc)
+	for n, e := range x.expressions {
+		switch r := e.Evaluate(nav.Copy()).(type) {
+		case float64, string, bool:
+			extracted[n] = r
+		case *xpath.NodeIterator:
+			extracted[n] = firstNodeValue(r)
+		default:
+			if Debug {
+				level.Debug(x.logger).Log("msg", "unexpected XPath result type", "type", reflect.TypeOf(r))
+			}
+		}
+	}
+	if Debug {
+		level.Debug(x.logger).Log("msg", "extracted data debug in xml stage", "extracted data", fmt.Sprintf("%v", extracted))
+	}
+	return nil
+}
+
+// hasElement returns true if the document has a root element.
+func hasElement(doc *xmlquery.Node) bool {
+	for n := doc.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type == xmlquery.ElementNode {
+			return true
+		}
+	}
+	return false
+}
+
+// firstNodeValue returns the value of the first selected node, or nil if no
+// node was selected. Elements which contain other elements are returned as
+// XML, while other nodes are returned as their text.
+func firstNodeValue(it *xpath.NodeIterator) interface{} {
+	if !it.MoveNext() {
+		return nil
+	}
+	nav, ok := it.Current().(*xmlquery.NodeNavigator)
+	if !ok {
+		return it.Current().Value()
+	}
+
+	// The navigator of an attribute is positioned on its element, so the
+	// value of the attribute must be read from the navigator.
+	node := nav.Current()
+	if nav.NodeType() == xpath.ElementNode {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == xmlquery.ElementNode {
+				return node.OutputXML(false)
+			}
+		}
+	}
+	return nav.Value()
+}
+
+// Name implements Stage
+func (x *xmlStage) Name() string {
+	return StageTypeXML
+}
+
+// Cleanup implements Stage.
+func (*xmlStage) Cleanup() {
+	// no-op
+}
@@ -0,0 +1,296 @@
+package stages
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/util"
+)
+
+var testXMLAlloySingleStageWithoutSource = `
+stage.xml {
+    expressions = {
+        "out"      = "/event/message",
+        "app"      = "",
+        "level"    = "/event/@level",
+        "nested"   = "",
+        "duration" = "number(/event/duration)",
+        "retries"  = "count(//retry)",
+        "unknown"  = "",
+    }
+}
+`
+
+var testXMLAlloyMultiStageWithSource = `
+stage.xml {
+    expressions = { "extra" = "" }
+}
+
+stage.xml {
+    expressions = { "user" = "/data/user" }
+	source      = "extra"
+}`
+
+var testXMLLogLine = `<?xml version="1.0" encoding="UTF-8"?>
+<event level="WARN">
+	<time>2012-11-01T22:08:41+00:00</time>
+	<app>loki</app>
+	<nested><child>value</child></nested>
+	<duration>125</duration>
+	<retry>1</retry>
+	<retry>2</retry>
+	<message>this is a log line</message>
+	<extra>&lt;data&gt;&lt;user&gt;marco&lt;/user&gt;&lt;/data&gt;</extra>
+</event>
+`
+
+func TestPipeline_XML(t *testing.T) {
+	t.Parallel()
+	logger := util.TestAlloyLogger(t)
+
+	tests := map[string]struct {
+		config          string
+		entry           string
+		expectedExtract map[string]interface{}
+	}{
+		"successfully run a pipeline with 1 xml stage without source": {
+			testXMLAlloySingleStageWithoutSource,
+			testXMLLogLine,
+			map[string]interface{}{
+				"out":      "this is a log line",
+				"app":      "loki",
+				"level":    "WARN",
+				"nested":   "<child>value</child>",
+				"duration": float64(125),
+				"retries":  float64(2),
+				"unknown":  nil,
+			},
+		},
+		"successfully run a pipeline with 2 xml stages with source": {
+			testXMLAlloyMultiStageWithSource,
+			testXMLLogLine,
+			map[string]interface{}{
+				"extra": "<data><user>marco</user></data>",
+				"user":  "marco",
+			},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			pl, err := NewPipeline(logger, loadConfig(testData.config), nil, prometheus.DefaultRegisterer, featuregate.StabilityGenerallyAvailable)
+			require.NoError(t, err, "Expected pipeline creation to not result in error")
+			out := processEntries(pl, newEntry(nil, nil, testData.entry, time.Now()))[0]
+			assert.Equal(t, testData.expectedExtract, out.Extracted)
+		})
+	}
+}
+
+func TestXMLConfig_validate(t *testing.T) {
+	t.Parallel()
+
+	var emptyString = ""
+	var logString = "log"
+
+	tests := map[string]struct {
+		config        *XMLConfig
+		wantExprCount int
+		err           error
+	}{
+		"empty config": {
+			nil,
+			0,
+			errors.New(ErrEmptyXMLStageConfig),
+		},
+		"no expressions": {
+			&XMLConfig{},
+			0,
+			errors.New(ErrXPathExpressionsRequired),
+		},
+		"invalid expression": {
+			&XMLConfig{
+				Expressions: map[string]string{
+					"extr1": "/event[",
+				},
+			},
+			0,
+			errors.New(ErrCouldNotCompileXPath),
+		},
+		"empty source": {
+			&XMLConfig{
+				Expressions: map[string]string{
+					"extr1": "/event/message",
+				},
+				Source: &emptyString,
+			},
+			0,
+			errors.New(ErrEmptyXMLStageSource),
+		},
+		"valid without source": {
+			&XMLConfig{
+				Expressions: map[string]string{
+					"expr1": "/event/message",
+					"expr2": "",
+					"expr3": "count(//retry)",
+				},
+			},
+			3,
+			nil,
+		},
+		"valid with source": {
+			&XMLConfig{
+				Expressions: map[string]string{
+					"expr1": "/event/message",
+				},
+				Source: &logString,
+			},
+			1,
+			nil,
+		},
+	}
+	for tName, tt := range tests {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+			c, err := validateXMLConfig(tt.config)
+			if tt.err != nil {
+				require.ErrorContains(t, err, tt.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, c, tt.wantExprCount)
+		})
+	}
+}
+
+func TestXMLParser_Parse(t *testing.T) {
+	t.Parallel()
+	logger := util.TestAlloyLogger(t)
+
+	var logString = "log"
+	tests := map[string]struct {
+		config          StageConfig
+		extracted       map[string]interface{}
+		entry           string
+		expectedExtract map[string]interface{}
+	}{
+		"successfully decode xml on extracted[source]": {
+			StageConfig{XMLConfig: &XMLConfig{
+				Expressions: map[string]string{
+					"app":     "",
+					"message": "/event/message",
+				},
+				Source: &logString,
+			}},
+			map[string]interface{}{
+				"log": testXMLLogLine,
+			},
+			"not xml",
+			map[string]interface{}{
+				"app":     "loki",
+				"message": "this is a log line",
+				"log":     testXMLLogLine,
+			},
+		},
+		"namespaced elements": {
+			StageConfig{XMLConfig: &XMLConfig{
+				Expressions: map[string]string{
+					"user": "//*[local-name()='user']",
+				},
+			}},
+			map[string]interface{}{},
+			`<e:event xmlns:e="urn:example"><e:user>marco</e:user></e:event>`,
+			map[string]interface{}{
+				"user": "marco",
+			},
+		},
+		"missing extracted[source]": {
+			StageConfig{XMLConfig: &XMLConfig{
+				Expressions: map[string]string{
+					"app": "",
+				},
+				Source: &logString,
+			}},
+			map[string]interface{}{},
+			testXMLLogLine,
+			map[string]interface{}{},
+		},
+		"invalid xml on entry": {
+			StageConfig{XMLConfig: &XMLConfig{
+				Expressions: map[string]string{
+					"expr1": "",
+				},
+			}},
+			map[string]interface{}{},
+			"ts=now log=notxml",
+			map[string]interface{}{},
+		},
+		"invalid xml on extracted[source]": {
+			StageConfig{XMLConfig: &XMLConfig{
+				Expressions: map[string]string{
+					"app": "",
+				},
+				Source: &logString,
+			}},
+			map[string]interface{}{
+				"log": "<event><app>loki</event>",
+			},
+			testXMLLogLine,
+			map[string]interface{}{
+				"log": "<event><app>loki</event>",
+			},
+		},
+		"nil source": {
+			StageConfig{XMLConfig: &XMLConfig{
+				Expressions: map[string]string{
+					"app": "",
+				},
+				Source: &logString,
+			}},
+			map[string]interface{}{
+				"log": nil,
+			},
+			testXMLLogLine,
+			map[string]interface{}{
+				"log": nil,
+			},
+		},
+	}
+	for tName, tt := range tests {
+		t.Run(tName, func(t *testing.T) {
+			t.Parallel()
+			p, err := New(logger, nil, tt.config, nil, featuregate.StabilityGenerallyAvailable)
+			require.NoError(t, err, "failed to create xml parser: %s", err)
+			out := processEntries(p, newEntry(tt.extracted, nil, tt.entry, time.Now()))[0]
+
+			assert.Equal(t, tt.expectedExtract, out.Extracted)
+		})
+	}
+}
+
+func TestValidateXMLDrop(t *testing.T) {
+	logger := util.TestAlloyLogger(t)
+	labels := map[string]string{"foo": "bar"}
+	cfg := &XMLConfig{
+		DropMalformed: true,
+		Expressions:   map[string]string{"page": ""},
+	}
+	s, err := newXMLStage(logger, *cfg)
+	require.NoError(t, err)
+	out := processEntries(s, newEntry(map[string]interface{}{
+		"test_label": "unimportant value",
+	}, toLabelSet(labels), `<page>1</page>`, time.Now()))
+	assert.Equal(t, 1, len(out), "stage should have kept one valid xml line but got %v", out)
+
+	out = processEntries(s, newEntry(map[string]interface{}{
+		"test_label": "unimportant value",
+	}, toLabelSet(labels), `<page>1</pages>`, time.Now()))
+	assert.Equal(t, 0, len(out), "stage should have kept zero valid xml line but got %v", out)
+}
@@ -136,6 +136,7 @@ func (c *Component) Run(ctx context.Context) error {
 				func() string {
 					return fmt.Sprintf("entry: %s, labels: %s => %s", entry.Line, entry.Labels.String(), lbls.String())
 				},
+				livedebugging.WithLabelSets(entry.Labels, lbls),
 			))
 
 			if len(lbls) == 0 {
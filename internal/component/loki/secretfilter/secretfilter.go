@@ -128,6 +128,13 @@ type GitLeaksConfig struct {
 	}
 }
 
+// EmbeddedGitleaksConfig returns the embedded gitleaks.toml configuration.
+func EmbeddedGitleaksConfig() (GitLeaksConfig, error) {
+	var gitleaksCfg GitLeaksConfig
+	_, err := toml.DecodeFS(embedFs, "gitleaks.toml", &gitleaksCfg)
+	return gitleaksCfg, err
+}
+
 // metrics holds the set of metrics for secrets that are being redacted.
 type metrics struct {
 	// Total number of secrets redacted
@@ -246,6 +253,7 @@ func (c *Component) Run(ctx context.Context) error {
 				func() string {
 					return fmt.Sprintf("%s => %s", entry.Line, newEntry.Line)
 				},
+				livedebugging.WithLabelSets(entry.Labels),
 			))
 
 			for _, f := range c.fanout {
@@ -392,7 +400,8 @@ func (c *Component) Update(args component.Arguments) error {
 	var gitleaksCfg GitLeaksConfig
 	if c.args.GitleaksConfig == "" {
 		// If no config file is explicitly provided, use the embedded one
-		_, err := toml.DecodeFS(embedFs, "gitleaks.toml", &gitleaksCfg)
+		var err error
+		gitleaksCfg, err = EmbeddedGitleaksConfig()
 		if err != nil {
 			return err
 		}
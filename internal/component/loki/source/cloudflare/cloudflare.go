@@ -9,7 +9,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -46,6 +45,7 @@ type Arguments struct {
 	FieldsType       string              `alloy:"fields_type,attr,optional"`
 	AdditionalFields []string            `alloy:"additional_fields,attr,optional"`
 	ForwardTo        []loki.LogsReceiver `alloy:"forward_to,attr"`
+	PositionsBackend string              `alloy:"positions_backend,attr,optional"`
 }
 
 // Convert returns a cloudflaretarget Config struct from the Arguments.
@@ -70,6 +70,8 @@ var DefaultArguments = Arguments{
 	Workers:    3,
 	PullRange:  1 * time.Minute,
 	FieldsType: string(cft.FieldsTypeDefault),
+
+	PositionsBackend: positions.BackendYAML,
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -86,7 +88,7 @@ func (c *Arguments) Validate() error {
 	if err != nil {
 		return fmt.Errorf("invalid fields_type set; the available values are 'default', 'minimal', 'extended', 'custom' and 'all'")
 	}
-	return nil
+	return positions.ValidateBackend(c.PositionsBackend)
 }
 
 // Component implements the loki.source.cloudflare component.
@@ -100,6 +102,8 @@ type Component struct {
 
 	posFile positions.Positions
 	handler loki.LogsReceiver
+
+	positionsBackend string
 }
 
 // New creates a new loki.source.cloudflare component.
@@ -108,12 +112,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
-		SyncPeriod:        10 * time.Second,
-		PositionsFile:     filepath.Join(o.DataPath, "positions.yml"),
-		IgnoreInvalidYaml: false,
-		ReadOnly:          false,
-	})
+	positionsFile, err := positions.NewForComponent(o.Logger, o.DataPath, args.PositionsBackend)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +123,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		handler: loki.NewLogsReceiver(),
 		fanout:  args.ForwardTo,
 		posFile: positionsFile,
+
+		positionsBackend: args.PositionsBackend,
 	}
 
 	// Call to Update() to start readers and set receivers once at the start.
@@ -163,6 +164,9 @@ func (c *Component) Update(args component.Arguments) error {
 	defer c.mut.Unlock()
 
 	newArgs := args.(Arguments)
+	if newArgs.PositionsBackend != c.positionsBackend {
+		return fmt.Errorf("positions_backend can't be changed without restarting Alloy")
+	}
 	c.fanout = newArgs.ForwardTo
 
 	if c.target != nil {
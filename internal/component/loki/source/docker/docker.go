@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"reflect"
 	"sort"
 	"sync"
@@ -62,6 +61,7 @@ type Arguments struct {
 	RelabelRules     alloy_relabel.Rules     `alloy:"relabel_rules,attr,optional"`
 	HTTPClientConfig *types.HTTPClientConfig `alloy:"http_client_config,block,optional"`
 	RefreshInterval  time.Duration           `alloy:"refresh_interval,attr,optional"`
+	PositionsBackend string                  `alloy:"positions_backend,attr,optional"`
 }
 
 // GetDefaultArguments return an instance of Arguments with the optional fields
@@ -70,6 +70,7 @@ func GetDefaultArguments() Arguments {
 	return Arguments{
 		HTTPClientConfig: types.CloneDefaultHTTPClientConfig(),
 		RefreshInterval:  60 * time.Second,
+		PositionsBackend: positions.BackendYAML,
 	}
 }
 
@@ -83,6 +84,9 @@ func (a *Arguments) Validate() error {
 	if _, err := url.Parse(a.Host); err != nil {
 		return fmt.Errorf("failed to parse Docker host %q: %w", a.Host, err)
 	}
+	if err := positions.ValidateBackend(a.PositionsBackend); err != nil {
+		return err
+	}
 	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
 	if a.HTTPClientConfig != nil {
 		if a.RefreshInterval <= 0 {
@@ -113,6 +117,8 @@ type Component struct {
 	rcs           []*relabel.Config
 	defaultLabels model.LabelSet
 
+	positionsBackend string
+
 	receiversMut sync.RWMutex
 	receivers    []loki.LogsReceiver
 }
@@ -123,12 +129,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
-		SyncPeriod:        10 * time.Second,
-		PositionsFile:     filepath.Join(o.DataPath, "positions.yml"),
-		IgnoreInvalidYaml: false,
-		ReadOnly:          false,
-	})
+	positionsFile, err := positions.NewForComponent(o.Logger, o.DataPath, args.PositionsBackend)
 	if err != nil {
 		return nil, err
 	}
@@ -141,6 +142,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		manager:   newManager(o.Logger, nil),
 		receivers: args.ForwardTo,
 		posFile:   positionsFile,
+
+		positionsBackend: args.PositionsBackend,
 	}
 
 	// Call to Update() to start readers and set receivers once at the start.
@@ -197,6 +200,9 @@ func (c *Component) Update(args component.Arguments) error {
 
 	c.mut.Lock()
 	defer c.mut.Unlock()
+	if newArgs.PositionsBackend != c.positionsBackend {
+		return fmt.Errorf("positions_backend can't be changed without restarting Alloy")
+	}
 
 	managerOpts, err := c.getManagerOptions(newArgs)
 	if err != nil {
@@ -49,15 +49,25 @@ type Arguments struct {
 	FileWatch           FileWatch           `alloy:"file_watch,block,optional"`
 	TailFromEnd         bool                `alloy:"tail_from_end,attr,optional"`
 	LegacyPositionsFile string              `alloy:"legacy_positions_file,attr,optional"`
+	PositionsBackend    string              `alloy:"positions_backend,attr,optional"`
 }
 
+// Watch modes used to detect changes in the tailed files.
+const (
+	WatchModePoll    = "poll"
+	WatchModeInotify = "inotify"
+)
+
 type FileWatch struct {
+	Mode             string        `alloy:"mode,attr,optional"`
 	MinPollFrequency time.Duration `alloy:"min_poll_frequency,attr,optional"`
 	MaxPollFrequency time.Duration `alloy:"max_poll_frequency,attr,optional"`
 }
 
 var DefaultArguments = Arguments{
+	PositionsBackend: positions.BackendYAML,
 	FileWatch: FileWatch{
+		Mode:             WatchModePoll,
 		MinPollFrequency: 250 * time.Millisecond,
 		MaxPollFrequency: 250 * time.Millisecond,
 	},
@@ -68,6 +78,19 @@ func (a *Arguments) SetToDefault() {
 	*a = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if err := positions.ValidateBackend(a.PositionsBackend); err != nil {
+		return err
+	}
+	switch a.FileWatch.Mode {
+	case WatchModePoll, WatchModeInotify:
+		return nil
+	default:
+		return fmt.Errorf("invalid file_watch mode %q, must be %q or %q", a.FileWatch.Mode, WatchModePoll, WatchModeInotify)
+	}
+}
+
 type DecompressionConfig struct {
 	Enabled      bool              `alloy:"enabled,attr"`
 	InitialDelay time.Duration     `alloy:"initial_delay,attr,optional"`
@@ -106,12 +129,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if args.LegacyPositionsFile != "" {
 		positions.ConvertLegacyPositionsFile(args.LegacyPositionsFile, newPositionsPath, o.Logger)
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
-		SyncPeriod:        10 * time.Second,
-		PositionsFile:     newPositionsPath,
-		IgnoreInvalidYaml: false,
-		ReadOnly:          false,
-	})
+	positionsFile, err := positions.NewForComponent(o.Logger, o.DataPath, args.PositionsBackend)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +137,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
 		metrics: newMetrics(o.Registerer),
+		args:    args,
 
 		handler:       loki.NewLogsReceiver(),
 		receivers:     args.ForwardTo,
@@ -189,6 +208,9 @@ func (c *Component) Update(args component.Arguments) error {
 
 	c.mut.Lock()
 	defer c.mut.Unlock()
+	if newArgs.PositionsBackend != c.args.PositionsBackend {
+		return fmt.Errorf("positions_backend can't be changed without restarting Alloy")
+	}
 	c.args = newArgs
 	c.receivers = newArgs.ForwardTo
 
@@ -311,6 +333,7 @@ func (c *Component) createReader(path string, labels model.LabelSet) (reader, er
 			labels,
 			c.args.Encoding,
 			pollOptions,
+			c.args.FileWatch.Mode,
 			c.args.TailFromEnd,
 			c.IsStopping,
 		)
@@ -20,6 +20,7 @@ type metrics struct {
 	readLines        *prometheus.CounterVec
 	encodingFailures *prometheus.CounterVec
 	filesActive      prometheus.Gauge
+	watchMode        *prometheus.GaugeVec
 }
 
 // newMetrics creates a new set of file metrics. If reg is non-nil, the metrics
@@ -48,6 +49,10 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 		Name: "loki_source_file_files_active_total",
 		Help: "Number of active files.",
 	})
+	m.watchMode = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "loki_source_file_watch_mode",
+		Help: "Watch mode used to tail a file, set to 1 for the mode in use.",
+	}, []string{"path", "mode"})
 
 	if reg != nil {
 		m.readBytes = util.MustRegisterOrGet(reg, m.readBytes).(*prometheus.GaugeVec)
@@ -55,6 +60,7 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 		m.readLines = util.MustRegisterOrGet(reg, m.readLines).(*prometheus.CounterVec)
 		m.encodingFailures = util.MustRegisterOrGet(reg, m.encodingFailures).(*prometheus.CounterVec)
 		m.filesActive = util.MustRegisterOrGet(reg, m.filesActive).(prometheus.Gauge)
+		m.watchMode = util.MustRegisterOrGet(reg, m.watchMode).(*prometheus.GaugeVec)
 	}
 
 	return &m
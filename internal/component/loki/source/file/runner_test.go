@@ -48,6 +48,7 @@ func TestRunnerTailer(t *testing.T) {
 			MinPollFrequency: 25 * time.Millisecond,
 			MaxPollFrequency: 25 * time.Millisecond,
 		},
+		WatchModePoll,
 		false,
 		func() bool { return true },
 	)
@@ -16,11 +16,13 @@ import (
 	"github.com/grafana/loki/v3/pkg/util"
 	"github.com/grafana/tail"
 	"github.com/grafana/tail/watch"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"go.uber.org/atomic"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/ianaindex"
 	"golang.org/x/text/transform"
+	"gopkg.in/tomb.v1"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
@@ -40,6 +42,14 @@ type tailer struct {
 
 	tailFromEnd bool
 	pollOptions watch.PollingFileWatcherOptions
+	watchMode   string
+
+	// mode is the watch mode currently used to tail the file. It differs from
+	// watchMode once the tailer fell back to polling.
+	mode           *atomic.String
+	fellBackToPoll *atomic.Bool
+	// sending is true while readLines waits for the entry handler to accept a line.
+	sending *atomic.Bool
 
 	posAndSizeMtx sync.Mutex
 
@@ -58,7 +68,7 @@ type tailer struct {
 }
 
 func newTailer(metrics *metrics, logger log.Logger, receiver loki.LogsReceiver, positions positions.Positions, path string,
-	labels model.LabelSet, encoding string, pollOptions watch.PollingFileWatcherOptions, tailFromEnd bool, componentStopping func() bool) (*tailer, error) {
+	labels model.LabelSet, encoding string, pollOptions watch.PollingFileWatcherOptions, watchMode string, tailFromEnd bool, componentStopping func() bool) (*tailer, error) {
 
 	tailer := &tailer{
 		metrics:           metrics,
@@ -71,6 +81,10 @@ func newTailer(metrics *metrics, logger log.Logger, receiver loki.LogsReceiver,
 		running:           atomic.NewBool(false),
 		tailFromEnd:       tailFromEnd,
 		pollOptions:       pollOptions,
+		watchMode:         watchMode,
+		mode:              atomic.NewString(WatchModePoll),
+		fellBackToPoll:    atomic.NewBool(false),
+		sending:           atomic.NewBool(false),
 		componentStopping: componentStopping,
 	}
 
@@ -153,6 +167,9 @@ func (t *tailer) Run() {
 
 	defer handler.Stop()
 
+	t.metrics.watchMode.DeletePartialMatch(prometheus.Labels{"path": t.path})
+	t.metrics.watchMode.WithLabelValues(t.path, t.mode.Load()).Set(1)
+
 	// updatePosition closes the channel t.posdone on exit
 	go t.updatePosition()
 	t.metrics.filesActive.Add(1.)
@@ -200,21 +217,17 @@ func (t *tailer) initRun() (loki.EntryHandler, error) {
 		}
 	}
 
-	tail, err := tail.TailFile(t.path, tail.Config{
-		Follow:    true,
-		Poll:      true,
-		ReOpen:    true,
-		MustExist: true,
-		Location: &tail.SeekInfo{
-			Offset: pos,
-			Whence: 0,
-		},
-		Logger:      util.NewLogAdapter(t.logger),
-		PollOptions: t.pollOptions,
-	})
+	mode := t.selectWatchMode()
+	tail, err := t.tailFile(pos, mode)
+	if err != nil && mode == WatchModeInotify {
+		t.fallBackToPoll("failed to watch the file with inotify", err)
+		mode = WatchModePoll
+		tail, err = t.tailFile(pos, mode)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to tail the file: %w", err)
 	}
+	t.mode.Store(mode)
 
 	t.tail = tail
 	labelsMiddleware := t.labels.Merge(model.LabelSet{filenameLabel: model.LabelValue(t.path)})
@@ -225,6 +238,39 @@ func (t *tailer) initRun() (loki.EntryHandler, error) {
 	return handler, nil
 }
 
+// selectWatchMode returns the watch mode to use for the next run of the tailer.
+func (t *tailer) selectWatchMode() string {
+	if t.watchMode != WatchModeInotify || t.fellBackToPoll.Load() {
+		return WatchModePoll
+	}
+	if err := checkInotifySupport(t.path); err != nil {
+		t.fallBackToPoll("the file can't be watched with inotify", err)
+		return WatchModePoll
+	}
+	return WatchModeInotify
+}
+
+// fallBackToPoll makes the tailer use polling for all its next runs.
+func (t *tailer) fallBackToPoll(msg string, err error) {
+	level.Warn(t.logger).Log("msg", msg+", falling back to polling", "path", t.path, "err", err)
+	t.fellBackToPoll.Store(true)
+}
+
+func (t *tailer) tailFile(pos int64, mode string) (*tail.Tail, error) {
+	return tail.TailFile(t.path, tail.Config{
+		Follow:    true,
+		Poll:      mode == WatchModePoll,
+		ReOpen:    true,
+		MustExist: true,
+		Location: &tail.SeekInfo{
+			Offset: pos,
+			Whence: 0,
+		},
+		Logger:      util.NewLogAdapter(t.logger),
+		PollOptions: t.pollOptions,
+	})
+}
+
 // updatePosition is run in a goroutine and checks the current size of the file
 // and saves it to the positions file at a regular interval. If there is ever
 // an error it stops the tailer and exits, the tailer will be re-opened by the
@@ -233,6 +279,7 @@ func (t *tailer) initRun() (loki.EntryHandler, error) {
 func (t *tailer) updatePosition() {
 	positionSyncPeriod := t.positions.SyncPeriod()
 	positionWait := time.NewTicker(positionSyncPeriod)
+	var stalled positionStallDetector
 	defer func() {
 		positionWait.Stop()
 		level.Info(t.logger).Log("msg", "position timer: exited", "path", t.path)
@@ -253,6 +300,15 @@ func (t *tailer) updatePosition() {
 				}
 				return
 			}
+			if t.mode.Load() == WatchModeInotify && t.readStalled(&stalled) {
+				// Unread data stayed in the file for a whole sync period, which happens on filesystems
+				// which don't emit inotify events. Stop the tailer so that it's restarted with polling.
+				t.fallBackToPoll("file changes were not notified", fmt.Errorf("no data read for %s", positionSyncPeriod))
+				if err := t.tail.Stop(); err != nil {
+					level.Error(t.logger).Log("msg", "position timer: error stopping tailer", "path", t.path, "error", err)
+				}
+				return
+			}
 		case <-t.posquit:
 			return
 		}
@@ -281,7 +337,12 @@ func (t *tailer) readLines(handler loki.EntryHandler) {
 	for {
 		line, ok := <-t.tail.Lines
 		if !ok {
-			level.Info(t.logger).Log("msg", "tail routine: tail channel closed, stopping tailer", "path", t.path, "reason", t.tail.Tomb.Err())
+			reason := t.tail.Tomb.Err()
+			level.Info(t.logger).Log("msg", "tail routine: tail channel closed, stopping tailer", "path", t.path, "reason", reason)
+			// The inotify watcher can fail after the tailer started, for example when the limit of inotify watches is reached.
+			if reason != nil && reason != tomb.ErrStillAlive && t.mode.Load() == WatchModeInotify && !t.fellBackToPoll.Load() {
+				t.fallBackToPoll("the inotify watcher stopped", reason)
+			}
 			return
 		}
 
@@ -305,6 +366,7 @@ func (t *tailer) readLines(handler loki.EntryHandler) {
 		}
 
 		t.metrics.readLines.WithLabelValues(t.path).Inc()
+		t.sending.Store(true)
 		entries <- loki.Entry{
 			Labels: model.LabelSet{},
 			Entry: logproto.Entry{
@@ -312,6 +374,7 @@ func (t *tailer) readLines(handler loki.EntryHandler) {
 				Line:      text,
 			},
 		}
+		t.sending.Store(false)
 	}
 }
 
@@ -414,8 +477,70 @@ func (t *tailer) cleanupMetrics() {
 	t.metrics.readLines.DeleteLabelValues(t.path)
 	t.metrics.readBytes.DeleteLabelValues(t.path)
 	t.metrics.totalBytes.DeleteLabelValues(t.path)
+	t.metrics.watchMode.DeletePartialMatch(prometheus.Labels{"path": t.path})
 }
 
 func (t *tailer) Path() string {
 	return t.path
 }
+
+// readStalled returns true if the tailer didn't read the data written to the
+// file since the previous call, while not being blocked by the entry handler.
+func (t *tailer) readStalled(d *positionStallDetector) bool {
+	size, err := t.tail.Size()
+	if err != nil {
+		return false
+	}
+	pos, err := t.tail.Tell()
+	if err != nil {
+		return false
+	}
+	if !d.update(pos, size) || t.sending.Load() {
+		return false
+	}
+
+	// tail doesn't consume a last line which isn't terminated yet, so the
+	// position stays at its start until the line is completed. This isn't a
+	// stall.
+	complete, err := hasCompleteLine(t.path, pos, size)
+	return err == nil && complete
+}
+
+// hasCompleteLine returns true if the data of the file at path between the
+// offsets start and end contains a newline.
+func hasCompleteLine(path string, start, end int64) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	r := io.NewSectionReader(f, start, end-start)
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if bytes.IndexByte(buf[:n], '\n') >= 0 {
+			return true, nil
+		}
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+}
+
+// positionStallDetector detects when the position of a tailer doesn't move
+// even though there is unread data in the file.
+type positionStallDetector struct {
+	lastPos   int64
+	hadUnread bool
+}
+
+// update returns true if the file had unread data at the previous update and
+// the position didn't move since then.
+func (d *positionStallDetector) update(pos, size int64) bool {
+	stalled := d.hadUnread && pos == d.lastPos && size > pos
+	d.lastPos, d.hadUnread = pos, size > pos
+	return stalled
+}
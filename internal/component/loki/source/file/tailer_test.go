@@ -11,6 +11,8 @@ import (
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/tail/watch"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -120,6 +122,7 @@ func TestTailer(t *testing.T) {
 			MinPollFrequency: 25 * time.Millisecond,
 			MaxPollFrequency: 25 * time.Millisecond,
 		},
+		WatchModePoll,
 		false,
 		func() bool { return true },
 	)
@@ -203,6 +206,7 @@ func TestTailerPositionFileEntryDeleted(t *testing.T) {
 			MinPollFrequency: 25 * time.Millisecond,
 			MaxPollFrequency: 25 * time.Millisecond,
 		},
+		WatchModePoll,
 		false,
 		func() bool { return false },
 	)
@@ -266,6 +270,7 @@ func TestTailerDeleteFileInstant(t *testing.T) {
 			MinPollFrequency: 25 * time.Millisecond,
 			MaxPollFrequency: 25 * time.Millisecond,
 		},
+		WatchModePoll,
 		false,
 		func() bool { return true },
 	)
@@ -289,3 +294,93 @@ func TestTailerDeleteFileInstant(t *testing.T) {
 		t.Fatal("tailer deadlocked")
 	}
 }
+
+func TestTailerInotify(t *testing.T) {
+	l := util.TestLogger(t)
+	ch1 := loki.NewLogsReceiver()
+	tempDir := t.TempDir()
+	logFile, err := os.CreateTemp(tempDir, "example")
+	require.NoError(t, err)
+	positionsFile, err := positions.New(l, positions.Config{
+		SyncPeriod:        50 * time.Millisecond,
+		PositionsFile:     filepath.Join(tempDir, "positions.yaml"),
+		IgnoreInvalidYaml: false,
+		ReadOnly:          false,
+	})
+	require.NoError(t, err)
+	labels := model.LabelSet{
+		"filename": model.LabelValue(logFile.Name()),
+		"foo":      "bar",
+	}
+	metrics := newMetrics(prometheus.NewRegistry())
+	tailer, err := newTailer(
+		metrics,
+		l,
+		ch1,
+		positionsFile,
+		logFile.Name(),
+		labels,
+		"",
+		watch.PollingFileWatcherOptions{
+			MinPollFrequency: 25 * time.Millisecond,
+			MaxPollFrequency: 25 * time.Millisecond,
+		},
+		WatchModeInotify,
+		false,
+		func() bool { return true },
+	)
+	require.NoError(t, err)
+	go tailer.Run()
+
+	// The tailer falls back to polling when inotify isn't supported, the lines must be read in both cases.
+	expectedMode := WatchModeInotify
+	if checkInotifySupport(logFile.Name()) != nil {
+		expectedMode = WatchModePoll
+	}
+
+	_, err = logFile.Write([]byte("writing some text\n"))
+	require.NoError(t, err)
+	select {
+	case logEntry := <-ch1.Chan():
+		require.Equal(t, "writing some text", logEntry.Line)
+	case <-time.After(1 * time.Second):
+		require.FailNow(t, "failed waiting for log line")
+	}
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.watchMode.WithLabelValues(logFile.Name(), expectedMode)) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	tailer.Stop()
+	positionsFile.Stop()
+	require.NoError(t, logFile.Close())
+}
+
+func TestPositionStallDetector(t *testing.T) {
+	var d positionStallDetector
+
+	// Everything was read.
+	require.False(t, d.update(10, 10))
+	// New data was written but there was no sync period to read it yet.
+	require.False(t, d.update(10, 20))
+	// The data was read.
+	require.False(t, d.update(20, 30))
+	// The position didn't move for a whole sync period.
+	require.True(t, d.update(20, 30))
+	// The file was read until the end.
+	require.False(t, d.update(30, 30))
+	require.False(t, d.update(30, 30))
+}
+
+func TestHasCompleteLine(t *testing.T) {
+	path := createTempFileWithContent(t, []byte("first line\nunterminated"))
+
+	// Only the unterminated last line is unread.
+	complete, err := hasCompleteLine(path, 11, 23)
+	require.NoError(t, err)
+	require.False(t, complete)
+
+	// The first line wasn't read either.
+	complete, err = hasCompleteLine(path, 0, 23)
+	require.NoError(t, err)
+	require.True(t, complete)
+}
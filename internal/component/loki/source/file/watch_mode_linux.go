@@ -0,0 +1,39 @@
+//go:build linux
+
+package file
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// filesystems which don't emit inotify events for changes made by other hosts,
+// keyed by their magic number.
+var noInotifyFilesystems = map[uint32]string{
+	unix.AFS_SUPER_MAGIC:  "afs",
+	unix.CEPH_SUPER_MAGIC: "ceph",
+	unix.CIFS_SUPER_MAGIC: "cifs",
+	unix.CODA_SUPER_MAGIC: "coda",
+	unix.FUSE_SUPER_MAGIC: "fuse",
+	unix.NFS_SUPER_MAGIC:  "nfs",
+	unix.SMB_SUPER_MAGIC:  "smb",
+	unix.SMB2_SUPER_MAGIC: "smb2",
+	unix.V9FS_MAGIC:       "9p",
+}
+
+// checkInotifySupport returns an error if changes to the file at path can't
+// be watched with inotify.
+func checkInotifySupport(path string) error {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return fmt.Errorf("failed to get the filesystem type: %w", err)
+	}
+	// The type of Statfs_t.Type depends on the architecture, and is signed on
+	// some of them. Magic numbers are 32-bit values, so they're compared as
+	// such.
+	if fs, ok := noInotifyFilesystems[uint32(st.Type)]; ok {
+		return fmt.Errorf("the %s filesystem doesn't emit inotify events reliably", fs)
+	}
+	return nil
+}
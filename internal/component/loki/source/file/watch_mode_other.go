@@ -0,0 +1,11 @@
+//go:build !linux
+
+package file
+
+import "fmt"
+
+// checkInotifySupport returns an error if changes to the file at path can't
+// be watched with inotify.
+func checkInotifySupport(_ string) error {
+	return fmt.Errorf("inotify is only supported on Linux")
+}
@@ -4,10 +4,9 @@ package journal
 
 import (
 	"context"
+	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
-	"time"
 
 	"github.com/grafana/loki/v3/clients/pkg/promtail/scrapeconfig"
 	"github.com/prometheus/common/model"
@@ -44,6 +43,8 @@ type Component struct {
 	handler   chan loki.Entry
 	positions positions.Positions
 	receivers []loki.LogsReceiver
+
+	positionsBackend string
 }
 
 // New creates a new  component.
@@ -53,12 +54,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		return nil, err
 	}
 
-	positionsFile, err := positions.New(o.Logger, positions.Config{
-		SyncPeriod:        10 * time.Second,
-		PositionsFile:     filepath.Join(o.DataPath, "positions.yml"),
-		IgnoreInvalidYaml: false,
-		ReadOnly:          false,
-	})
+	positionsFile, err := positions.NewForComponent(o.Logger, o.DataPath, args.PositionsBackend)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +65,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		handler:   make(chan loki.Entry),
 		positions: positionsFile,
 		receivers: args.Receivers,
+
+		positionsBackend: args.PositionsBackend,
 	}
 	err = c.Update(args)
 	return c, err
@@ -107,6 +105,9 @@ func (c *Component) Update(args component.Arguments) error {
 	newArgs := args.(Arguments)
 	c.mut.Lock()
 	defer c.mut.Unlock()
+	if newArgs.PositionsBackend != c.positionsBackend {
+		return fmt.Errorf("positions_backend can't be changed without restarting Alloy")
+	}
 	if c.t != nil {
 		err := c.t.Stop()
 		if err != nil {
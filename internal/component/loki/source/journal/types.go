@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 )
 
@@ -16,6 +17,8 @@ type Arguments struct {
 	Matches      string              `alloy:"matches,attr,optional"`
 	Receivers    []loki.LogsReceiver `alloy:"forward_to,attr"`
 	Labels       map[string]string   `alloy:"labels,attr,optional"`
+
+	PositionsBackend string `alloy:"positions_backend,attr,optional"`
 }
 
 func defaultArgs() Arguments {
@@ -23,6 +26,8 @@ func defaultArgs() Arguments {
 		FormatAsJson: false,
 		MaxAge:       7 * time.Hour,
 		Path:         "",
+
+		PositionsBackend: positions.BackendYAML,
 	}
 }
 
@@ -30,3 +35,8 @@ func defaultArgs() Arguments {
 func (r *Arguments) SetToDefault() {
 	*r = defaultArgs()
 }
+
+// Validate implements syntax.Validator.
+func (r *Arguments) Validate() error {
+	return positions.ValidateBackend(r.PositionsBackend)
+}
@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"reflect"
 	"sync"
 	"time"
@@ -46,11 +45,14 @@ type Arguments struct {
 	Client commonk8s.ClientArguments `alloy:"client,block,optional"`
 
 	Clustering cluster.ComponentBlock `alloy:"clustering,block,optional"`
+
+	PositionsBackend string `alloy:"positions_backend,attr,optional"`
 }
 
 // DefaultArguments holds default settings for loki.source.kubernetes.
 var DefaultArguments = Arguments{
-	Client: commonk8s.DefaultClientArguments,
+	Client:           commonk8s.DefaultClientArguments,
+	PositionsBackend: positions.BackendYAML,
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -58,6 +60,11 @@ func (args *Arguments) SetToDefault() {
 	*args = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	return positions.ValidateBackend(args.PositionsBackend)
+}
+
 // Component implements the loki.source.kubernetes component.
 type Component struct {
 	log       log.Logger
@@ -65,6 +72,8 @@ type Component struct {
 	positions positions.Positions
 	cluster   cluster.Cluster
 
+	positionsBackend string
+
 	mut         sync.Mutex
 	args        Arguments
 	tailer      *kubetail.Manager
@@ -88,10 +97,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
-		SyncPeriod:    10 * time.Second,
-		PositionsFile: filepath.Join(o.DataPath, "positions.yml"),
-	})
+	positionsFile, err := positions.NewForComponent(o.Logger, o.DataPath, args.PositionsBackend)
 
 	if err != nil {
 		return nil, err
@@ -108,6 +114,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		opts:      o,
 		handler:   loki.NewLogsReceiver(),
 		positions: positionsFile,
+
+		positionsBackend: args.PositionsBackend,
 	}
 	if err := c.Update(args); err != nil {
 		return nil, err
@@ -157,6 +165,9 @@ func (c *Component) Update(args component.Arguments) error {
 
 	c.mut.Lock()
 	defer c.mut.Unlock()
+	if newArgs.PositionsBackend != c.positionsBackend {
+		return fmt.Errorf("positions_backend can't be changed without restarting Alloy")
+	}
 
 	managerOpts, err := c.getTailerOptions(newArgs)
 	if err != nil {
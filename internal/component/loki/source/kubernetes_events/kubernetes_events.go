@@ -6,7 +6,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"reflect"
 	"sync"
 	"time"
@@ -49,6 +48,8 @@ type Arguments struct {
 
 	// Client settings to connect to Kubernetes.
 	Client kubernetes.ClientArguments `alloy:"client,block,optional"`
+
+	PositionsBackend string `alloy:"positions_backend,attr,optional"`
 }
 
 // DefaultArguments holds default settings for loki.source.kubernetes_events.
@@ -57,6 +58,8 @@ var DefaultArguments = Arguments{
 	LogFormat: logFormatFmt,
 
 	Client: kubernetes.DefaultClientArguments,
+
+	PositionsBackend: positions.BackendYAML,
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -72,7 +75,7 @@ func (args *Arguments) Validate() error {
 	if args.LogFormat != logFormatFmt && args.LogFormat != logFormatJson {
 		return fmt.Errorf("supported values of log_format are %s and %s", logFormatFmt, logFormatJson)
 	}
-	return nil
+	return positions.ValidateBackend(args.PositionsBackend)
 }
 
 // Component implements the loki.source.kubernetes_events component, which
@@ -86,6 +89,8 @@ type Component struct {
 	runner     *runner.Runner[eventControllerTask]
 	newTasksCh chan struct{}
 
+	positionsBackend string
+
 	mut        sync.Mutex
 	args       Arguments
 	restConfig *rest.Config
@@ -108,10 +113,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
-		SyncPeriod:    10 * time.Second,
-		PositionsFile: filepath.Join(o.DataPath, "positions.yml"),
-	})
+	positionsFile, err := positions.NewForComponent(o.Logger, o.DataPath, args.PositionsBackend)
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +127,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 			return newEventController(t)
 		}),
 		newTasksCh: make(chan struct{}, 1),
+
+		positionsBackend: args.PositionsBackend,
 	}
 	if err := c.Update(args); err != nil {
 		return nil, err
@@ -191,6 +195,9 @@ func (c *Component) Update(args component.Arguments) error {
 	defer c.mut.Unlock()
 
 	newArgs := args.(Arguments)
+	if newArgs.PositionsBackend != c.positionsBackend {
+		return fmt.Errorf("positions_backend can't be changed without restarting Alloy")
+	}
 
 	c.receiversMut.Lock()
 	c.receivers = newArgs.ForwardTo
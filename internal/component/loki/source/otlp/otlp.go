@@ -0,0 +1,328 @@
+// Package otlp provides a loki.source.otlp component.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/alecthomas/units"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/loki/convert"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/regexp"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.source.otlp",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the loki.source.otlp component.
+type Arguments struct {
+	GRPC *GRPCServerArguments `alloy:"grpc,block,optional"`
+	HTTP *HTTPConfigArguments `alloy:"http,block,optional"`
+
+	ForwardTo []loki.LogsReceiver `alloy:"forward_to,attr"`
+
+	// IgnoreDefaultLabels disables adding the default resource attributes to
+	// the labels.
+	IgnoreDefaultLabels bool             `alloy:"ignore_default_labels,attr,optional"`
+	ResourceAttributes  []AttributesRule `alloy:"resource_attributes,block,optional"`
+	ScopeAttributes     []AttributesRule `alloy:"scope_attributes,block,optional"`
+	LogAttributes       []AttributesRule `alloy:"log_attributes,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+// GRPCServerArguments is used to configure loki.source.otlp with
+// component-specific defaults.
+type GRPCServerArguments otelcol.GRPCServerArguments
+
+// HTTPConfigArguments configures the OTLP HTTP server.
+type HTTPConfigArguments struct {
+	HTTPServerArguments *otelcol.HTTPServerArguments `alloy:",squash"`
+
+	// The URL path to receive logs on. If omitted "/v1/logs" will be used.
+	LogsURLPath string `alloy:"logs_url_path,attr,optional"`
+}
+
+// AttributesRule maps attributes to labels or structured metadata, or drops
+// them.
+type AttributesRule struct {
+	Action     string   `alloy:"action,attr"`
+	Attributes []string `alloy:"attributes,attr,optional"`
+	Regex      string   `alloy:"regex,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{}
+	args.DebugMetrics.SetToDefault()
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *GRPCServerArguments) SetToDefault() {
+	*args = GRPCServerArguments{
+		Endpoint:  "0.0.0.0:4317",
+		Transport: "tcp",
+		Keepalive: &otelcol.KeepaliveServerArguments{
+			ServerParameters:  &otelcol.KeepaliveServerParamaters{},
+			EnforcementPolicy: &otelcol.KeepaliveEnforcementPolicy{},
+		},
+
+		ReadBufferSize: 512 * units.Kibibyte,
+	}
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *HTTPConfigArguments) SetToDefault() {
+	*args = HTTPConfigArguments{
+		HTTPServerArguments: &otelcol.HTTPServerArguments{
+			Endpoint:              "0.0.0.0:4318",
+			CompressionAlgorithms: append([]string(nil), otelcol.DefaultCompressionAlgorithms...),
+			CORS:                  &otelcol.CORSArguments{},
+		},
+		LogsURLPath: "/v1/logs",
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.GRPC == nil && args.HTTP == nil {
+		return fmt.Errorf("at least one of the grpc or http blocks must be set")
+	}
+	if args.HTTP != nil && args.HTTP.LogsURLPath == "" {
+		return fmt.Errorf("logs_url_path cannot be empty")
+	}
+	_, err := args.attributesConfig()
+	return err
+}
+
+// Validate implements syntax.Validator.
+func (r *AttributesRule) Validate() error {
+	switch r.Action {
+	case convert.ActionIndexLabel, convert.ActionStructuredMetadata, convert.ActionDrop:
+	default:
+		return fmt.Errorf("invalid action %q, must be one of %q, %q or %q", r.Action, convert.ActionIndexLabel, convert.ActionStructuredMetadata, convert.ActionDrop)
+	}
+	if len(r.Attributes) == 0 && r.Regex == "" {
+		return fmt.Errorf("at least one of attributes or regex must be set")
+	}
+	return nil
+}
+
+// attributesConfig converts the attributes rules into the converter
+// configuration.
+func (args *Arguments) attributesConfig() (*convert.AttributesConfig, error) {
+	resourceRules, err := convertRules(args.ResourceAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource_attributes block: %w", err)
+	}
+	scopeRules, err := convertRules(args.ScopeAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scope_attributes block: %w", err)
+	}
+	logRules, err := convertRules(args.LogAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log_attributes block: %w", err)
+	}
+
+	// The default labels are applied after the configured rules so that the
+	// rules can override them.
+	if !args.IgnoreDefaultLabels {
+		resourceRules = append(resourceRules, convert.AttributesRule{
+			Action:     convert.ActionIndexLabel,
+			Attributes: convert.DefaultResourceLabels,
+		})
+	}
+
+	return &convert.AttributesConfig{
+		ResourceAttributes: resourceRules,
+		ScopeAttributes:    scopeRules,
+		LogAttributes:      logRules,
+	}, nil
+}
+
+func convertRules(rules []AttributesRule) ([]convert.AttributesRule, error) {
+	res := make([]convert.AttributesRule, 0, len(rules))
+	for _, r := range rules {
+		rule := convert.AttributesRule{
+			Action:     r.Action,
+			Attributes: r.Attributes,
+		}
+		if r.Regex != "" {
+			// Anchor the regex so that it must match the whole attribute name.
+			re, err := regexp.Compile("^(?:" + r.Regex + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex %q: %w", r.Regex, err)
+			}
+			rule.Regex = re
+		}
+		res = append(res, rule)
+	}
+	return res, nil
+}
+
+// Component implements the loki.source.otlp component.
+type Component struct {
+	converter *convert.Converter
+	receiver  *receiver.Receiver
+}
+
+var (
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+	_ component.LiveDebugging   = (*Component)(nil)
+)
+
+// New creates a new loki.source.otlp component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	converter := convert.NewWithMetricsPrefix(o.Logger, o.Registerer, "loki_source_otlp", args.ForwardTo)
+	attrs, err := args.attributesConfig()
+	if err != nil {
+		return nil, err
+	}
+	converter.UpdateAttributesConfig(attrs)
+
+	// The OTLP servers are managed by the otelcol receiver shim, which sends
+	// the logs it receives to the converter.
+	r, err := receiver.New(o, otlpreceiver.NewFactory(), receiverArguments{
+		args:     args,
+		consumer: logsConsumer{converter},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Component{
+		converter: converter,
+		receiver:  r,
+	}, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	return c.receiver.Run(ctx)
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	attrs, err := newArgs.attributesConfig()
+	if err != nil {
+		return err
+	}
+	c.converter.UpdateAttributesConfig(attrs)
+	c.converter.UpdateFanout(newArgs.ForwardTo)
+
+	return c.receiver.Update(receiverArguments{
+		args:     newArgs,
+		consumer: logsConsumer{c.converter},
+	})
+}
+
+// CurrentHealth implements component.HealthComponent.
+func (c *Component) CurrentHealth() component.Health {
+	return c.receiver.CurrentHealth()
+}
+
+// LiveDebugging implements component.LiveDebugging. The received logs are
+// published by the receiver.
+func (c *Component) LiveDebugging() {}
+
+// receiverArguments adapts Arguments to the otelcol OTLP receiver.
+type receiverArguments struct {
+	args     Arguments
+	consumer otelcol.Consumer
+}
+
+var _ receiver.Arguments = receiverArguments{}
+
+// Convert implements receiver.Arguments.
+func (ra receiverArguments) Convert() (otelcomponent.Config, error) {
+	grpcProtocolArgs, err := (*otelcol.GRPCServerArguments)(ra.args.GRPC).Convert()
+	if err != nil {
+		return nil, err
+	}
+
+	var httpProtocolArgs *otlpreceiver.HTTPConfig
+	if ra.args.HTTP != nil {
+		httpServerArgs, err := ra.args.HTTP.HTTPServerArguments.Convert()
+		if err != nil {
+			return nil, err
+		}
+		httpProtocolArgs = &otlpreceiver.HTTPConfig{
+			ServerConfig: httpServerArgs,
+			LogsURLPath:  ra.args.HTTP.LogsURLPath,
+			// Only logs are received, but the receiver expects valid paths.
+			TracesURLPath:  "/v1/traces",
+			MetricsURLPath: "/v1/metrics",
+		}
+	}
+
+	return &otlpreceiver.Config{
+		Protocols: otlpreceiver.Protocols{
+			GRPC: grpcProtocolArgs,
+			HTTP: httpProtocolArgs,
+		},
+	}, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (ra receiverArguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	extensionMap := make(map[otelcomponent.ID]otelcomponent.Component)
+	if ra.args.HTTP != nil {
+		maps.Copy(extensionMap, ra.args.HTTP.HTTPServerArguments.Extensions())
+	}
+	if ra.args.GRPC != nil {
+		maps.Copy(extensionMap, (*otelcol.GRPCServerArguments)(ra.args.GRPC).Extensions())
+	}
+	return extensionMap
+}
+
+// Exporters implements receiver.Arguments.
+func (ra receiverArguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (ra receiverArguments) NextConsumers() *otelcol.ConsumerArguments {
+	return &otelcol.ConsumerArguments{
+		Logs: []otelcol.Consumer{ra.consumer},
+	}
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (ra receiverArguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return ra.args.DebugMetrics
+}
+
+// logsConsumer is an otelcol.Consumer which only accepts logs.
+type logsConsumer struct {
+	*convert.Converter
+}
+
+// ConsumeTraces implements otelcol.Consumer.
+func (logsConsumer) ConsumeTraces(context.Context, ptrace.Traces) error { return nil }
+
+// ConsumeMetrics implements otelcol.Consumer.
+func (logsConsumer) ConsumeMetrics(context.Context, pmetric.Metrics) error { return nil }
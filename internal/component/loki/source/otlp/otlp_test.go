@@ -0,0 +1,217 @@
+package otlp
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/phayes/freeport"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+const logsPayload = `{
+	"resourceLogs": [{
+		"resource": {
+			"attributes": [{
+				"key": "service.name",
+				"value": { "stringValue": "checkout" }
+			},
+			{
+				"key": "host.name",
+				"value": { "stringValue": "host-1" }
+			}]
+		},
+		"scopeLogs": [{
+			"logRecords": [{
+				"timeUnixNano": "1581452773000000111",
+				"severityText": "Info",
+				"body": { "stringValue": "order placed" },
+				"attributes": [{
+					"key": "team",
+					"value": { "stringValue": "payments" }
+				}]
+			}]
+		}]
+	}]
+}`
+
+// Test runs the loki.source.otlp component and ensures that it can receive
+// OTLP logs over HTTP and forward them as Loki entries.
+func Test(t *testing.T) {
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	httpAddr := fmt.Sprintf("localhost:%d", port)
+
+	ctx := componenttest.TestContext(t)
+	l := util.TestLogger(t)
+
+	ctrl, err := componenttest.NewControllerFromID(l, "loki.source.otlp")
+	require.NoError(t, err)
+
+	cfg := fmt.Sprintf(`
+		http {
+			endpoint = "%s"
+		}
+
+		log_attributes {
+			action     = "index_label"
+			attributes = ["team"]
+		}
+
+		forward_to = []
+	`, httpAddr)
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	receiver := loki.NewLogsReceiver()
+	args.ForwardTo = []loki.LogsReceiver{receiver}
+
+	go func() {
+		err := ctrl.Run(ctx, args)
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, ctrl.WaitRunning(time.Second))
+
+	// Send logs in the background to our receiver.
+	go func() {
+		request := func() error {
+			logsURL := fmt.Sprintf("http://%s/v1/logs", httpAddr)
+			resp, err := http.DefaultClient.Post(logsURL, "application/json", bytes.NewBufferString(logsPayload))
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+
+		bo := backoff.New(ctx, backoff.Config{
+			MinBackoff: 10 * time.Millisecond,
+			MaxBackoff: 100 * time.Millisecond,
+		})
+		for bo.Ongoing() {
+			if err := request(); err != nil {
+				level.Error(l).Log("msg", "failed to send logs", "err", err)
+				bo.Wait()
+				continue
+			}
+
+			return
+		}
+	}()
+
+	select {
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "failed waiting for log entries")
+	case entry := <-receiver.Chan():
+		require.Equal(t, model.LabelSet{"service_name": "checkout", "team": "payments"}, entry.Labels)
+		require.Equal(t, "order placed", entry.Line)
+		require.Equal(t, time.Unix(0, 1581452773000000111).UTC(), entry.Timestamp.UTC())
+		require.Contains(t, entry.StructuredMetadata, logproto.LabelAdapter{Name: "host_name", Value: "host-1"})
+	}
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			cfg: `
+				grpc {}
+				resource_attributes {
+					action     = "structured_metadata"
+					attributes = ["k8s.pod.name"]
+				}
+				log_attributes {
+					action = "drop"
+					regex  = "http\\..*"
+				}
+				forward_to = []
+			`,
+		},
+		{
+			name:        "no server",
+			cfg:         `forward_to = []`,
+			expectedErr: "at least one of the grpc or http blocks must be set",
+		},
+		{
+			name: "invalid action",
+			cfg: `
+				http {}
+				log_attributes {
+					action     = "label"
+					attributes = ["team"]
+				}
+				forward_to = []
+			`,
+			expectedErr: `invalid action "label"`,
+		},
+		{
+			name: "no attributes",
+			cfg: `
+				http {}
+				scope_attributes {
+					action = "drop"
+				}
+				forward_to = []
+			`,
+			expectedErr: "at least one of attributes or regex must be set",
+		},
+		{
+			name: "invalid regex",
+			cfg: `
+				http {}
+				resource_attributes {
+					action = "drop"
+					regex  = "("
+				}
+				forward_to = []
+			`,
+			expectedErr: "invalid resource_attributes block",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestAttributesConfig(t *testing.T) {
+	args := Arguments{
+		ResourceAttributes: []AttributesRule{
+			{Action: "structured_metadata", Attributes: []string{"k8s.pod.name"}},
+		},
+	}
+	cfg, err := args.attributesConfig()
+	require.NoError(t, err)
+	// The configured rules are applied before the default labels.
+	require.Len(t, cfg.ResourceAttributes, 2)
+	require.Equal(t, "structured_metadata", cfg.ResourceAttributes[0].Action)
+	require.Contains(t, cfg.ResourceAttributes[1].Attributes, "k8s.pod.name")
+
+	args.IgnoreDefaultLabels = true
+	cfg, err = args.attributesConfig()
+	require.NoError(t, err)
+	require.Len(t, cfg.ResourceAttributes, 1)
+}
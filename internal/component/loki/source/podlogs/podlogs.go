@@ -4,10 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"reflect"
 	"sync"
-	"time"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/component"
@@ -49,11 +47,14 @@ type Arguments struct {
 	NamespaceSelector config.LabelSelector `alloy:"namespace_selector,block,optional"`
 
 	Clustering cluster.ComponentBlock `alloy:"clustering,block,optional"`
+
+	PositionsBackend string `alloy:"positions_backend,attr,optional"`
 }
 
 // DefaultArguments holds default settings for loki.source.kubernetes.
 var DefaultArguments = Arguments{
-	Client: commonk8s.DefaultClientArguments,
+	Client:           commonk8s.DefaultClientArguments,
+	PositionsBackend: positions.BackendYAML,
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -61,6 +62,11 @@ func (args *Arguments) SetToDefault() {
 	*args = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	return positions.ValidateBackend(args.PositionsBackend)
+}
+
 // Component implements the loki.source.podlogs component.
 type Component struct {
 	log  log.Logger
@@ -73,6 +79,8 @@ type Component struct {
 	positions positions.Positions
 	handler   loki.LogsReceiver
 
+	positionsBackend string
+
 	mut         sync.RWMutex
 	args        Arguments
 	lastOptions *kubetail.Options
@@ -94,10 +102,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	if err != nil && !os.IsExist(err) {
 		return nil, err
 	}
-	positionsFile, err := positions.New(o.Logger, positions.Config{
-		SyncPeriod:    10 * time.Second,
-		PositionsFile: filepath.Join(o.DataPath, "positions.yml"),
-	})
+	positionsFile, err := positions.NewForComponent(o.Logger, o.DataPath, args.PositionsBackend)
 	if err != nil {
 		return nil, err
 	}
@@ -123,6 +128,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 
 		positions: positionsFile,
 		handler:   loki.NewLogsReceiver(),
+
+		positionsBackend: args.PositionsBackend,
 	}
 	if err := c.Update(args); err != nil {
 		return nil, err
@@ -198,6 +205,9 @@ func (c *Component) Update(args component.Arguments) error {
 
 	c.mut.Lock()
 	defer c.mut.Unlock()
+	if newArgs.PositionsBackend != c.positionsBackend {
+		return fmt.Errorf("positions_backend can't be changed without restarting Alloy")
+	}
 
 	if err := c.updateTailer(newArgs); err != nil {
 		return err
@@ -35,7 +35,7 @@ func Test_inferMetadata(t *testing.T) {
 		{
 			name: "loki.process",
 			expected: Metadata{
-				accepts: []Type{TypeLokiLogs},
+				accepts: []Type{TypeLokiLogs, TypePromMetricsReceiver},
 				exports: []Type{TypeLokiLogs},
 			},
 		},
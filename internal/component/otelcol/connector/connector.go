@@ -10,7 +10,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	otelcomponent "go.opentelemetry.io/collector/component"
 	otelconnector "go.opentelemetry.io/collector/connector"
+	otelconsumer "go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pipeline"
 	sdkprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/sdk/metric"
@@ -39,6 +42,13 @@ const (
 	ConnectorLogsToTraces
 	ConnectorLogsToMetrics
 	ConnectorLogsToLogs
+	// ConnectorAllToMetrics connectors accept traces, metrics and logs, and
+	// output metrics.
+	ConnectorAllToMetrics
+	// ConnectorRouting connectors accept traces, metrics and logs, and route
+	// them to the consumers of the pipelines returned by
+	// RouterArguments.Pipelines.
+	ConnectorRouting
 )
 
 // Arguments is an extension of component.Arguments which contains necessary
@@ -67,6 +77,16 @@ type Arguments interface {
 	DebugMetricsConfig() otelcolCfg.DebugMetricsArguments
 }
 
+// RouterArguments is an extension of Arguments for ConnectorRouting
+// connectors, which send telemetry to the consumers of several pipelines.
+type RouterArguments interface {
+	Arguments
+
+	// Pipelines returns the consumers of every pipeline referenced by the
+	// connector configuration.
+	Pipelines() map[pipeline.ID]*otelcol.ConsumerArguments
+}
+
 // Connector is an Alloy component shim which manages an OpenTelemetry
 // Collector connector component.
 type Connector struct {
@@ -196,7 +216,7 @@ func (p *Connector) Update(args component.Arguments) error {
 	var logsConnector otelconnector.Logs
 
 	switch p.args.ConnectorType() {
-	case ConnectorTracesToMetrics:
+	case ConnectorTracesToMetrics, ConnectorAllToMetrics:
 		if len(next.Traces) > 0 || len(next.Logs) > 0 {
 			return errors.New("this connector can only output metrics")
 		}
@@ -215,7 +235,43 @@ func (p *Connector) Update(args component.Arguments) error {
 			} else if tracesConnector != nil {
 				components = append(components, tracesConnector)
 			}
+
+			if p.args.ConnectorType() == ConnectorAllToMetrics {
+				metricsConnector, err = p.factory.CreateMetricsToMetrics(p.ctx, settings, connectorConfig, metricsInterceptor)
+				if err != nil && !errors.Is(err, pipeline.ErrSignalNotSupported) {
+					return err
+				} else if metricsConnector != nil {
+					components = append(components, metricsConnector)
+				}
+
+				logsConnector, err = p.factory.CreateLogsToMetrics(p.ctx, settings, connectorConfig, metricsInterceptor)
+				if err != nil && !errors.Is(err, pipeline.ErrSignalNotSupported) {
+					return err
+				} else if logsConnector != nil {
+					components = append(components, logsConnector)
+				}
+			}
+		}
+	case ConnectorRouting:
+		routerArgs, ok := p.args.(RouterArguments)
+		if !ok {
+			return errors.New("routing connectors must implement RouterArguments")
+		}
+		routes := routerArgs.Pipelines()
+
+		tracesConnector, err = p.factory.CreateTracesToTraces(p.ctx, settings, connectorConfig, p.tracesRouter(routes))
+		if err != nil {
+			return err
+		}
+		metricsConnector, err = p.factory.CreateMetricsToMetrics(p.ctx, settings, connectorConfig, p.metricsRouter(routes))
+		if err != nil {
+			return err
+		}
+		logsConnector, err = p.factory.CreateLogsToLogs(p.ctx, settings, connectorConfig, p.logsRouter(routes))
+		if err != nil {
+			return err
 		}
+		components = append(components, tracesConnector, metricsConnector, logsConnector)
 	default:
 		return errors.New("unsupported connector type")
 	}
@@ -229,6 +285,52 @@ func (p *Connector) Update(args component.Arguments) error {
 	return nil
 }
 
+// tracesRouter returns a router to the traces consumers of every route. The
+// live debugging data of a route targets its consumers only.
+func (p *Connector) tracesRouter(routes map[pipeline.ID]*otelcol.ConsumerArguments) otelconnector.TracesRouterAndConsumer {
+	consumers := make(map[pipeline.ID]otelconsumer.Traces, len(routes))
+	for id, next := range routes {
+		fanout := fanoutconsumer.Traces(next.Traces)
+		consumers[id] = interceptconsumer.Traces(fanout,
+			func(ctx context.Context, td ptrace.Traces) error {
+				livedebuggingpublisher.PublishTracesIfActive(p.debugDataPublisher, p.opts.ID, td, otelcol.GetComponentMetadata(next.Traces))
+				return fanout.ConsumeTraces(ctx, td)
+			},
+		)
+	}
+	return otelconnector.NewTracesRouter(consumers)
+}
+
+// metricsRouter returns a router to the metrics consumers of every route.
+func (p *Connector) metricsRouter(routes map[pipeline.ID]*otelcol.ConsumerArguments) otelconnector.MetricsRouterAndConsumer {
+	consumers := make(map[pipeline.ID]otelconsumer.Metrics, len(routes))
+	for id, next := range routes {
+		fanout := fanoutconsumer.Metrics(next.Metrics)
+		consumers[id] = interceptconsumer.Metrics(fanout,
+			func(ctx context.Context, md pmetric.Metrics) error {
+				livedebuggingpublisher.PublishMetricsIfActive(p.debugDataPublisher, p.opts.ID, md, otelcol.GetComponentMetadata(next.Metrics))
+				return fanout.ConsumeMetrics(ctx, md)
+			},
+		)
+	}
+	return otelconnector.NewMetricsRouter(consumers)
+}
+
+// logsRouter returns a router to the logs consumers of every route.
+func (p *Connector) logsRouter(routes map[pipeline.ID]*otelcol.ConsumerArguments) otelconnector.LogsRouterAndConsumer {
+	consumers := make(map[pipeline.ID]otelconsumer.Logs, len(routes))
+	for id, next := range routes {
+		fanout := fanoutconsumer.Logs(next.Logs)
+		consumers[id] = interceptconsumer.Logs(fanout,
+			func(ctx context.Context, ld plog.Logs) error {
+				livedebuggingpublisher.PublishLogsIfActive(p.debugDataPublisher, p.opts.ID, ld, otelcol.GetComponentMetadata(next.Logs))
+				return fanout.ConsumeLogs(ctx, ld)
+			},
+		)
+	}
+	return otelconnector.NewLogsRouter(consumers)
+}
+
 // CurrentHealth implements component.HealthComponent.
 func (p *Connector) CurrentHealth() component.Health {
 	return p.sched.CurrentHealth()
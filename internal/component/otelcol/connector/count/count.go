@@ -0,0 +1,185 @@
+// Package count provides an otelcol.connector.count component.
+package count
+
+import (
+	"fmt"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/connector"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.connector.count",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := countconnector.NewFactory()
+			return connector.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.connector.count component.
+type Arguments struct {
+	Spans      []MetricInfo `alloy:"spans,block,optional"`
+	SpanEvents []MetricInfo `alloy:"spanevents,block,optional"`
+	Metrics    []MetricInfo `alloy:"metrics,block,optional"`
+	DataPoints []MetricInfo `alloy:"datapoints,block,optional"`
+	Logs       []MetricInfo `alloy:"logs,block,optional"`
+
+	// Output configures where to send processed data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+// MetricInfo configures a count metric.
+type MetricInfo struct {
+	Name        string            `alloy:"name,attr"`
+	Description string            `alloy:"description,attr,optional"`
+	Conditions  []string          `alloy:"conditions,attr,optional"`
+	Attributes  []AttributeConfig `alloy:"attribute,block,optional"`
+}
+
+// AttributeConfig configures an attribute the count metric is grouped by.
+type AttributeConfig struct {
+	Key          string      `alloy:"key,attr"`
+	DefaultValue interface{} `alloy:"default_value,attr,optional"`
+}
+
+// DefaultArguments holds the default settings for Arguments. Every signal is
+// counted by a default metric, unless metrics are configured for the signal.
+var DefaultArguments = Arguments{
+	Spans: []MetricInfo{{
+		Name:        "trace.span.count",
+		Description: "The number of spans observed.",
+	}},
+	SpanEvents: []MetricInfo{{
+		Name:        "trace.span.event.count",
+		Description: "The number of span events observed.",
+	}},
+	Metrics: []MetricInfo{{
+		Name:        "metric.count",
+		Description: "The number of metrics observed.",
+	}},
+	DataPoints: []MetricInfo{{
+		Name:        "metric.datapoint.count",
+		Description: "The number of data points observed.",
+	}},
+	Logs: []MetricInfo{{
+		Name:        "log.record.count",
+		Description: "The number of log records observed.",
+	}},
+}
+
+var (
+	_ syntax.Validator    = (*Arguments)(nil)
+	_ syntax.Defaulter    = (*Arguments)(nil)
+	_ connector.Arguments = (*Arguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+	// Copy the default metrics so that unmarshaling doesn't modify them.
+	args.Spans = append([]MetricInfo{}, DefaultArguments.Spans...)
+	args.SpanEvents = append([]MetricInfo{}, DefaultArguments.SpanEvents...)
+	args.Metrics = append([]MetricInfo{}, DefaultArguments.Metrics...)
+	args.DataPoints = append([]MetricInfo{}, DefaultArguments.DataPoints...)
+	args.Logs = append([]MetricInfo{}, DefaultArguments.Logs...)
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	cfg, err := args.Convert()
+	if err != nil {
+		return err
+	}
+	return cfg.(*countconnector.Config).Validate()
+}
+
+// Convert implements connector.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	var (
+		cfg countconnector.Config
+		err error
+	)
+	if cfg.Spans, err = convertMetricInfos("spans", args.Spans); err != nil {
+		return nil, err
+	}
+	if cfg.SpanEvents, err = convertMetricInfos("spanevents", args.SpanEvents); err != nil {
+		return nil, err
+	}
+	if cfg.Metrics, err = convertMetricInfos("metrics", args.Metrics); err != nil {
+		return nil, err
+	}
+	if cfg.DataPoints, err = convertMetricInfos("datapoints", args.DataPoints); err != nil {
+		return nil, err
+	}
+	if cfg.Logs, err = convertMetricInfos("logs", args.Logs); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// convertMetricInfos converts the metrics of a signal to the upstream
+// configuration, which is keyed by metric name.
+func convertMetricInfos(signal string, infos []MetricInfo) (map[string]countconnector.MetricInfo, error) {
+	res := make(map[string]countconnector.MetricInfo, len(infos))
+	for _, info := range infos {
+		if _, ok := res[info.Name]; ok {
+			return nil, fmt.Errorf("%s: duplicate metric name %q", signal, info.Name)
+		}
+
+		attrs := make([]countconnector.AttributeConfig, 0, len(info.Attributes))
+		for _, attr := range info.Attributes {
+			attrs = append(attrs, countconnector.AttributeConfig{
+				Key:          attr.Key,
+				DefaultValue: attr.DefaultValue,
+			})
+		}
+		res[info.Name] = countconnector.MetricInfo{
+			Description: info.Description,
+			Conditions:  info.Conditions,
+			Attributes:  attrs,
+		}
+	}
+	return res, nil
+}
+
+// Extensions implements connector.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements connector.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements connector.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// ConnectorType() int implements connector.Arguments.
+func (Arguments) ConnectorType() int {
+	return connector.ConnectorAllToMetrics
+}
+
+// DebugMetricsConfig implements connector.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
@@ -0,0 +1,138 @@
+package count_test
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/otelcol/connector/count"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	defaultConfig := countconnector.Config{
+		Spans: map[string]countconnector.MetricInfo{
+			"trace.span.count": {Description: "The number of spans observed.", Attributes: []countconnector.AttributeConfig{}},
+		},
+		SpanEvents: map[string]countconnector.MetricInfo{
+			"trace.span.event.count": {Description: "The number of span events observed.", Attributes: []countconnector.AttributeConfig{}},
+		},
+		Metrics: map[string]countconnector.MetricInfo{
+			"metric.count": {Description: "The number of metrics observed.", Attributes: []countconnector.AttributeConfig{}},
+		},
+		DataPoints: map[string]countconnector.MetricInfo{
+			"metric.datapoint.count": {Description: "The number of data points observed.", Attributes: []countconnector.AttributeConfig{}},
+		},
+		Logs: map[string]countconnector.MetricInfo{
+			"log.record.count": {Description: "The number of log records observed.", Attributes: []countconnector.AttributeConfig{}},
+		},
+	}
+
+	customLogsConfig := defaultConfig
+	customLogsConfig.Logs = map[string]countconnector.MetricInfo{
+		"log.record.error.count": {
+			Description: "The number of error log records.",
+			Conditions:  []string{`severity_number >= SEVERITY_NUMBER_ERROR`},
+			Attributes: []countconnector.AttributeConfig{
+				{Key: "service.name", DefaultValue: "unknown"},
+				{Key: "env"},
+			},
+		},
+		"log.record.warn.count": {
+			Conditions: []string{`severity_number >= SEVERITY_NUMBER_WARN`, `severity_number < SEVERITY_NUMBER_ERROR`},
+			Attributes: []countconnector.AttributeConfig{},
+		},
+	}
+
+	tests := []struct {
+		testName string
+		cfg      string
+		expected countconnector.Config
+		errorMsg string
+	}{
+		{
+			testName: "defaultConfig",
+			cfg: `
+			output {}
+			`,
+			expected: defaultConfig,
+		},
+		{
+			testName: "customLogs",
+			cfg: `
+			logs {
+				name        = "log.record.error.count"
+				description = "The number of error log records."
+				conditions  = ["severity_number >= SEVERITY_NUMBER_ERROR"]
+
+				attribute {
+					key           = "service.name"
+					default_value = "unknown"
+				}
+
+				attribute {
+					key = "env"
+				}
+			}
+
+			logs {
+				name       = "log.record.warn.count"
+				conditions = [
+					"severity_number >= SEVERITY_NUMBER_WARN",
+					"severity_number < SEVERITY_NUMBER_ERROR",
+				]
+			}
+
+			output {}
+			`,
+			expected: customLogsConfig,
+		},
+		{
+			testName: "duplicateName",
+			cfg: `
+			spans {
+				name = "span.count"
+			}
+
+			spans {
+				name = "span.count"
+			}
+
+			output {}
+			`,
+			errorMsg: `spans: duplicate metric name "span.count"`,
+		},
+		{
+			testName: "invalidCondition",
+			cfg: `
+			logs {
+				name       = "log.record.count"
+				conditions = ["not a condition"]
+			}
+
+			output {}
+			`,
+			errorMsg: `log.record.count`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args count.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+
+			actualPtr, err := args.Convert()
+			require.NoError(t, err)
+
+			actual := actualPtr.(*countconnector.Config)
+			require.NoError(t, actual.Validate())
+			require.Equal(t, tc.expected, *actual)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Package routing provides an otelcol.connector.routing component.
+package routing
+
+import (
+	"strconv"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/connector"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.connector.routing",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := routingconnector.NewFactory()
+			return connector.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.connector.routing component.
+type Arguments struct {
+	// ErrorMode determines how the connector reacts to errors that occur while
+	// evaluating a route.
+	ErrorMode ottl.ErrorMode `alloy:"error_mode,attr,optional"`
+
+	// Routes is the routing table, evaluated in order.
+	Routes []Route `alloy:"route,block"`
+
+	// DefaultRoute configures where to send the telemetry which doesn't match
+	// any route.
+	DefaultRoute *otelcol.ConsumerArguments `alloy:"default_route,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+// Route configures a route of the routing table.
+type Route struct {
+	// Context is the OTTL context the condition or statement is evaluated in.
+	Context string `alloy:"context,attr,optional"`
+
+	// Condition is the OTTL condition the telemetry must match. Exactly one of
+	// Condition and Statement must be set.
+	Condition string `alloy:"condition,attr,optional"`
+
+	// Statement is the OTTL route() statement the telemetry must match.
+	Statement string `alloy:"statement,attr,optional"`
+
+	// Output configures where to send the matching telemetry.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// DefaultArguments holds default settings for Arguments.
+var DefaultArguments = Arguments{
+	ErrorMode: ottl.PropagateError,
+}
+
+var (
+	_ syntax.Validator          = (*Arguments)(nil)
+	_ syntax.Defaulter          = (*Arguments)(nil)
+	_ connector.RouterArguments = (*Arguments)(nil)
+)
+
+// The upstream connector routes telemetry to pipeline IDs. Every route of an
+// Alloy component has a single output block for all signals, so the same
+// pipeline IDs are used for traces, metrics and logs.
+var defaultRouteID = pipeline.MustNewIDWithName("route", "default")
+
+// routeID returns the pipeline ID of the route at index i of the routing
+// table.
+func routeID(i int) pipeline.ID {
+	return pipeline.MustNewIDWithName("route", strconv.Itoa(i))
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	cfg, err := args.Convert()
+	if err != nil {
+		return err
+	}
+	return cfg.(*routingconnector.Config).Validate()
+}
+
+// Convert implements connector.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	var cfg routingconnector.Config
+	cfg.ErrorMode = args.ErrorMode
+	if args.DefaultRoute != nil {
+		cfg.DefaultPipelines = []pipeline.ID{defaultRouteID}
+	}
+	for i, r := range args.Routes {
+		cfg.Table = append(cfg.Table, routingconnector.RoutingTableItem{
+			Context:   r.Context,
+			Condition: r.Condition,
+			Statement: r.Statement,
+			Pipelines: []pipeline.ID{routeID(i)},
+		})
+	}
+	return &cfg, nil
+}
+
+// Pipelines implements connector.RouterArguments.
+func (args Arguments) Pipelines() map[pipeline.ID]*otelcol.ConsumerArguments {
+	res := make(map[pipeline.ID]*otelcol.ConsumerArguments, len(args.Routes)+1)
+	for i, r := range args.Routes {
+		res[routeID(i)] = r.Output
+	}
+	if args.DefaultRoute != nil {
+		res[defaultRouteID] = args.DefaultRoute
+	}
+	return res
+}
+
+// Extensions implements connector.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements connector.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements connector.Arguments. The consumers of the
+// connector are configured by route, see Pipelines.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return nil
+}
+
+// ConnectorType() int implements connector.Arguments.
+func (Arguments) ConnectorType() int {
+	return connector.ConnectorRouting
+}
+
+// DebugMetricsConfig implements connector.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
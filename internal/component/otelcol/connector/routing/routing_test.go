@@ -0,0 +1,137 @@
+package routing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/connector/routing"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const testRoutingConfig = `
+	route {
+		condition = "attributes[\"tenant\"] == \"acme\""
+		output {}
+	}
+
+	route {
+		condition = "attributes[\"tenant\"] != nil"
+		output {}
+	}
+
+	default_route {}
+`
+
+func TestRouting(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	ctrl, err := componenttest.NewControllerFromID(util.TestLogger(t), "otelcol.connector.routing")
+	require.NoError(t, err)
+
+	var args routing.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(testRoutingConfig), &args))
+
+	// Override the outputs so the routed tenants are recorded.
+	received := make([][]string, 3)
+	recordTenants := func(i int) *otelcol.ConsumerArguments {
+		return &otelcol.ConsumerArguments{
+			Logs: []otelcol.Consumer{&fakeconsumer.Consumer{
+				ConsumeLogsFunc: func(_ context.Context, ld plog.Logs) error {
+					for j := 0; j < ld.ResourceLogs().Len(); j++ {
+						var tenant string
+						if v, ok := ld.ResourceLogs().At(j).Resource().Attributes().Get("tenant"); ok {
+							tenant = v.Str()
+						}
+						received[i] = append(received[i], tenant)
+					}
+					return nil
+				},
+			}},
+		}
+	}
+	args.Routes[0].Output = recordTenants(0)
+	args.Routes[1].Output = recordTenants(1)
+	args.DefaultRoute = recordTenants(2)
+
+	go func() {
+		require.NoError(t, ctrl.Run(ctx, args))
+	}()
+	require.NoError(t, ctrl.WaitRunning(time.Second), "component never started")
+	require.NoError(t, ctrl.WaitExports(time.Second), "component never exported anything")
+
+	exports := ctrl.Exports().(otelcol.ConsumerExports)
+	require.NoError(t, exports.Input.ConsumeLogs(ctx, testLogs("acme", "globex", "")))
+
+	// The telemetry is sent to the first matching route only, and to the
+	// default route when no route matches.
+	require.Equal(t, [][]string{{"acme"}, {"globex"}, {""}}, received)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		testName    string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			testName: "no condition or statement",
+			cfg: `
+				route {
+					output {}
+				}
+			`,
+			expectedErr: "invalid route: no condition or statement provided",
+		},
+		{
+			testName: "condition and statement",
+			cfg: `
+				route {
+					condition = "attributes[\"tenant\"] == \"acme\""
+					statement = "route() where attributes[\"tenant\"] == \"acme\""
+					output {}
+				}
+			`,
+			expectedErr: "invalid route: both condition and statement provided",
+		},
+		{
+			testName: "invalid context",
+			cfg: `
+				route {
+					context   = "scope"
+					condition = "attributes[\"tenant\"] == \"acme\""
+					output {}
+				}
+			`,
+			expectedErr: "invalid context: scope",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args routing.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+// testLogs returns logs with a resource for every tenant. The resource of an
+// empty tenant has no tenant attribute.
+func testLogs(tenants ...string) plog.Logs {
+	ld := plog.NewLogs()
+	for _, tenant := range tenants {
+		rl := ld.ResourceLogs().AppendEmpty()
+		if tenant != "" {
+			rl.Resource().Attributes().PutStr("tenant", tenant)
+		}
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("log line")
+	}
+	return ld
+}
@@ -0,0 +1,205 @@
+// Package file provides an otelcol.exporter.file component.
+package file
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.exporter.file",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := fileexporter.NewFactory()
+			return exporter.New(opts, fact, args.(Arguments), exporter.TypeSignalConstFunc(exporter.TypeAll))
+		},
+	})
+}
+
+// Supported values of the format and compression arguments.
+const (
+	FormatJSON  = "json"
+	FormatProto = "proto"
+
+	CompressionZstd = "zstd"
+)
+
+// Arguments configures the otelcol.exporter.file component.
+type Arguments struct {
+	Path          string             `alloy:"path,attr"`
+	Append        bool               `alloy:"append,attr,optional"`
+	Format        string             `alloy:"format,attr,optional"`
+	Compression   string             `alloy:"compression,attr,optional"`
+	FlushInterval time.Duration      `alloy:"flush_interval,attr,optional"`
+	Rotation      *RotationArguments `alloy:"rotation,block,optional"`
+	GroupBy       GroupByArguments   `alloy:"group_by,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var (
+	_ exporter.Arguments = Arguments{}
+	_ syntax.Defaulter   = (*Arguments)(nil)
+	_ syntax.Validator   = (*Arguments)(nil)
+)
+
+// DefaultArguments holds default settings for Arguments.
+var DefaultArguments = Arguments{
+	Format:        FormatJSON,
+	FlushInterval: time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+	args.GroupBy.SetToDefault()
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Path == "" {
+		return errors.New("path must be non-empty")
+	}
+
+	switch args.Format {
+	case FormatJSON, FormatProto:
+	default:
+		return fmt.Errorf("unknown format %q, allowed values are %q and %q", args.Format, FormatJSON, FormatProto)
+	}
+
+	switch args.Compression {
+	case "", CompressionZstd:
+	default:
+		return fmt.Errorf("unknown compression %q, the only allowed value is %q", args.Compression, CompressionZstd)
+	}
+
+	if args.Append && args.Compression != "" {
+		return errors.New("append and compression can't be enabled at the same time")
+	}
+
+	if args.FlushInterval < 0 {
+		return errors.New("flush_interval must not be negative")
+	}
+
+	if args.GroupBy.Enabled {
+		parts := strings.Split(args.Path, "*")
+		if len(parts) != 2 {
+			return errors.New("path must contain exactly one * when group_by is enabled")
+		}
+		if parts[0] == "" {
+			return errors.New("path must not start with * when group_by is enabled")
+		}
+		if args.GroupBy.ResourceAttribute == "" {
+			return errors.New("group_by resource_attribute must be non-empty")
+		}
+		if args.GroupBy.MaxOpenFiles <= 0 {
+			return errors.New("group_by max_open_files must be greater than zero")
+		}
+	}
+	return nil
+}
+
+// Convert implements exporter.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	return &fileexporter.Config{
+		Path:          args.Path,
+		Append:        args.Append,
+		Rotation:      args.Rotation.Convert(),
+		FormatType:    args.Format,
+		Compression:   args.Compression,
+		FlushInterval: args.FlushInterval,
+		GroupBy:       args.GroupBy.Convert(),
+	}, nil
+}
+
+// Extensions implements exporter.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements exporter.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// DebugMetricsConfig implements exporter.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
+
+// RotationArguments configures the rotation of the file.
+type RotationArguments struct {
+	MaxMegabytes int  `alloy:"max_megabytes,attr,optional"`
+	MaxDays      int  `alloy:"max_days,attr,optional"`
+	MaxBackups   int  `alloy:"max_backups,attr,optional"`
+	LocalTime    bool `alloy:"localtime,attr,optional"`
+}
+
+var _ syntax.Defaulter = (*RotationArguments)(nil)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *RotationArguments) SetToDefault() {
+	*args = RotationArguments{
+		MaxMegabytes: 100,
+		MaxBackups:   100,
+	}
+}
+
+// Convert converts args into the upstream type.
+func (args *RotationArguments) Convert() *fileexporter.Rotation {
+	if args == nil {
+		return nil
+	}
+
+	return &fileexporter.Rotation{
+		MaxMegabytes: args.MaxMegabytes,
+		MaxDays:      args.MaxDays,
+		MaxBackups:   args.MaxBackups,
+		LocalTime:    args.LocalTime,
+	}
+}
+
+// GroupByArguments configures writing the telemetry of every resource to a
+// different file.
+type GroupByArguments struct {
+	Enabled           bool   `alloy:"enabled,attr,optional"`
+	ResourceAttribute string `alloy:"resource_attribute,attr,optional"`
+	MaxOpenFiles      int    `alloy:"max_open_files,attr,optional"`
+}
+
+var _ syntax.Defaulter = (*GroupByArguments)(nil)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *GroupByArguments) SetToDefault() {
+	*args = GroupByArguments{
+		ResourceAttribute: "fileexporter.path_segment",
+		MaxOpenFiles:      100,
+	}
+}
+
+// Convert converts args into the upstream type.
+func (args GroupByArguments) Convert() *fileexporter.GroupBy {
+	return &fileexporter.GroupBy{
+		Enabled:           args.Enabled,
+		ResourceAttribute: args.ResourceAttribute,
+		MaxOpenFiles:      args.MaxOpenFiles,
+	}
+}
@@ -0,0 +1,140 @@
+package file_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/file"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		expected *fileexporter.Config
+		errorMsg string
+	}{
+		{
+			testName: "Defaults",
+			cfg: `
+				path = "/tmp/capture.json"
+			`,
+			expected: &fileexporter.Config{
+				Path:          "/tmp/capture.json",
+				FormatType:    "json",
+				FlushInterval: time.Second,
+				GroupBy: &fileexporter.GroupBy{
+					ResourceAttribute: "fileexporter.path_segment",
+					MaxOpenFiles:      100,
+				},
+			},
+		},
+		{
+			testName: "Rotation",
+			cfg: `
+				path        = "/tmp/capture.proto"
+				format      = "proto"
+				compression = "zstd"
+
+				rotation {
+					max_days = 3
+				}
+			`,
+			expected: &fileexporter.Config{
+				Path:          "/tmp/capture.proto",
+				FormatType:    "proto",
+				Compression:   "zstd",
+				FlushInterval: time.Second,
+				Rotation: &fileexporter.Rotation{
+					MaxMegabytes: 100,
+					MaxDays:      3,
+					MaxBackups:   100,
+				},
+				GroupBy: &fileexporter.GroupBy{
+					ResourceAttribute: "fileexporter.path_segment",
+					MaxOpenFiles:      100,
+				},
+			},
+		},
+		{
+			testName: "GroupBy",
+			cfg: `
+				path           = "/tmp/capture/*.json"
+				append         = true
+				flush_interval = "5s"
+
+				group_by {
+					enabled            = true
+					resource_attribute = "service.name"
+					max_open_files     = 10
+				}
+			`,
+			expected: &fileexporter.Config{
+				Path:          "/tmp/capture/*.json",
+				Append:        true,
+				FormatType:    "json",
+				FlushInterval: 5 * time.Second,
+				GroupBy: &fileexporter.GroupBy{
+					Enabled:           true,
+					ResourceAttribute: "service.name",
+					MaxOpenFiles:      10,
+				},
+			},
+		},
+		{
+			testName: "InvalidFormat",
+			cfg: `
+				path   = "/tmp/capture.txt"
+				format = "text"
+			`,
+			errorMsg: `unknown format "text"`,
+		},
+		{
+			testName: "InvalidCompression",
+			cfg: `
+				path        = "/tmp/capture.json"
+				compression = "gzip"
+			`,
+			errorMsg: `unknown compression "gzip"`,
+		},
+		{
+			testName: "AppendAndCompression",
+			cfg: `
+				path        = "/tmp/capture.json"
+				append      = true
+				compression = "zstd"
+			`,
+			errorMsg: "append and compression can't be enabled at the same time",
+		},
+		{
+			testName: "GroupByWithoutWildcard",
+			cfg: `
+				path = "/tmp/capture.json"
+
+				group_by {
+					enabled = true
+				}
+			`,
+			errorMsg: "path must contain exactly one * when group_by is enabled",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args file.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			actual, err := args.Convert()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual.(*fileexporter.Config))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package convert
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/regexp"
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// Actions which can be applied to attributes by an AttributesRule.
+const (
+	// ActionIndexLabel adds the attribute to the labels of the entry.
+	ActionIndexLabel = "index_label"
+	// ActionStructuredMetadata adds the attribute to the structured metadata
+	// of the entry.
+	ActionStructuredMetadata = "structured_metadata"
+	// ActionDrop drops the attribute.
+	ActionDrop = "drop"
+)
+
+// DefaultResourceLabels are the resource attributes which are added to the
+// labels by default. They are the same as the defaults of the native OTLP
+// ingestion of Loki.
+var DefaultResourceLabels = []string{
+	"service.name",
+	"service.namespace",
+	"service.instance.id",
+	"deployment.environment",
+	"deployment.environment.name",
+	"cloud.region",
+	"cloud.availability_zone",
+	"k8s.cluster.name",
+	"k8s.namespace.name",
+	"k8s.pod.name",
+	"k8s.container.name",
+	"container.name",
+	"k8s.replicaset.name",
+	"k8s.deployment.name",
+	"k8s.statefulset.name",
+	"k8s.daemonset.name",
+	"k8s.cronjob.name",
+	"k8s.job.name",
+}
+
+// AttributesRule applies Action to the attributes named in Attributes and to
+// the attributes matching Regex.
+type AttributesRule struct {
+	Action     string
+	Attributes []string
+	Regex      *regexp.Regexp
+}
+
+func (r AttributesRule) matches(name string) bool {
+	return slices.Contains(r.Attributes, name) || (r.Regex != nil && r.Regex.MatchString(name))
+}
+
+// AttributesConfig configures how the attributes of logs are mapped to the
+// labels and structured metadata of Loki entries, instead of relying on
+// attribute hints.
+//
+// For each attribute, the first matching rule is applied. Attributes which
+// don't match any rule are added to the structured metadata. Attribute names
+// are normalized to valid label names.
+type AttributesConfig struct {
+	ResourceAttributes []AttributesRule
+	ScopeAttributes    []AttributesRule
+	LogAttributes      []AttributesRule
+}
+
+// logToEntry converts a log record to a Loki entry. The body of the record is
+// used as the log line.
+func (cfg *AttributesConfig) logToEntry(lr plog.LogRecord, resource pcommon.Resource, scope pcommon.InstrumentationScope) loki.Entry {
+	entry := loki.Entry{
+		Labels: model.LabelSet{},
+		Entry: logproto.Entry{
+			Timestamp: timestampFromLogRecord(lr),
+			Line:      lr.Body().AsString(),
+		},
+	}
+
+	mapAttributes(&entry, cfg.ResourceAttributes, resource.Attributes())
+	mapAttributes(&entry, cfg.ScopeAttributes, scope.Attributes())
+	mapAttributes(&entry, cfg.LogAttributes, lr.Attributes())
+
+	addStructuredMetadata(&entry, "scope_name", scope.Name())
+	addStructuredMetadata(&entry, "scope_version", scope.Version())
+	addStructuredMetadata(&entry, "severity_text", lr.SeverityText())
+	if lr.SeverityNumber() != plog.SeverityNumberUnspecified {
+		addStructuredMetadata(&entry, "severity_number", strconv.Itoa(int(lr.SeverityNumber())))
+	}
+	if traceID := lr.TraceID(); !traceID.IsEmpty() {
+		addStructuredMetadata(&entry, "trace_id", traceID.String())
+	}
+	if spanID := lr.SpanID(); !spanID.IsEmpty() {
+		addStructuredMetadata(&entry, "span_id", spanID.String())
+	}
+
+	// Loki rejects streams without labels.
+	if len(entry.Labels) == 0 {
+		entry.Labels["service_name"] = "unknown_service"
+	}
+	return entry
+}
+
+func mapAttributes(entry *loki.Entry, rules []AttributesRule, attrs pcommon.Map) {
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		action := ActionStructuredMetadata
+		for _, r := range rules {
+			if r.matches(k) {
+				action = r.Action
+				break
+			}
+		}
+
+		switch action {
+		case ActionIndexLabel:
+			if value := v.AsString(); value != "" {
+				entry.Labels[model.LabelName(normalizeName(k))] = model.LabelValue(value)
+			}
+		case ActionStructuredMetadata:
+			addStructuredMetadata(entry, normalizeName(k), v.AsString())
+		}
+		return true
+	})
+}
+
+func addStructuredMetadata(entry *loki.Entry, name, value string) {
+	if value == "" {
+		return
+	}
+	entry.StructuredMetadata = append(entry.StructuredMetadata, logproto.LabelAdapter{Name: name, Value: value})
+}
+
+// normalizeName converts an attribute name to a valid label name by replacing
+// invalid characters with underscores.
+func normalizeName(name string) string {
+	if name == "" {
+		return name
+	}
+	normalized := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if normalized[0] >= '0' && normalized[0] <= '9' {
+		normalized = "key_" + normalized
+	}
+	return normalized
+}
+
+func timestampFromLogRecord(lr plog.LogRecord) time.Time {
+	if lr.Timestamp() != 0 {
+		return lr.Timestamp().AsTime()
+	}
+	if lr.ObservedTimestamp() != 0 {
+		return lr.ObservedTimestamp().AsTime()
+	}
+	return time.Now()
+}
@@ -27,8 +27,9 @@ type Converter struct {
 	log     log.Logger
 	metrics *metrics
 
-	mut  sync.RWMutex
-	next []loki.LogsReceiver // Location to write converted logs.
+	mut   sync.RWMutex
+	next  []loki.LogsReceiver // Location to write converted logs.
+	attrs *AttributesConfig   // Mapping of attributes, nil to use the loki translator.
 }
 
 var _ consumer.Logs = (*Converter)(nil)
@@ -36,10 +37,16 @@ var _ consumer.Logs = (*Converter)(nil)
 // New returns a new Converter. Converted logs are passed to the provided list
 // of LogsReceivers.
 func New(l log.Logger, r prometheus.Registerer, next []loki.LogsReceiver) *Converter {
+	return NewWithMetricsPrefix(l, r, "otelcol_exporter_loki", next)
+}
+
+// NewWithMetricsPrefix returns a new Converter whose metric names start with
+// prefix. Converted logs are passed to the provided list of LogsReceivers.
+func NewWithMetricsPrefix(l log.Logger, r prometheus.Registerer, prefix string, next []loki.LogsReceiver) *Converter {
 	if l == nil {
 		l = log.NewNopLogger()
 	}
-	m := newMetrics(r)
+	m := newMetrics(r, prefix)
 	return &Converter{log: l, metrics: m, next: next}
 }
 
@@ -54,10 +61,18 @@ func (conv *Converter) Capabilities() consumer.Capabilities {
 // into Loki-compatible entries. Each call to ConsumeLogs will forward
 // converted entries to the list of channels in the `next` field.
 // This is reusing the logic from the OpenTelemetry Collector "contrib"
-// distribution and its LogsToLokiRequests function.
+// distribution and its LogsToLokiRequests function, unless an
+// AttributesConfig is set with UpdateAttributesConfig.
 func (conv *Converter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	var entries []loki.Entry
 
+	// The fanout is read once, so the lock isn't held while sending entries,
+	// which can block until ctx is done.
+	conv.mut.RLock()
+	attrs := conv.attrs
+	next := conv.next
+	conv.mut.RUnlock()
+
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
 		ills := rls.At(i).ScopeLogs()
@@ -67,6 +82,12 @@ func (conv *Converter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 			for k := 0; k < logs.Len(); k++ {
 				conv.metrics.entriesTotal.Inc()
 
+				if attrs != nil {
+					conv.metrics.entriesProcessed.Inc()
+					entries = append(entries, attrs.logToEntry(logs.At(k), rls.At(i).Resource(), scope))
+					continue
+				}
+
 				// TODO: loki added a parameter `defaultLabelsEnabled` to this function to add the possibility to disable default labels (exporter, job, instance, level)
 				// Is this interesting for us in any ways? (@wildum)
 				// https://github.com/open-telemetry/opentelemetry-collector-contrib/pull/23863/files#diff-ef7831fcba373f6e8aa7f799b5b89f4e113b2064cd7ef1688286ce193d2256a8
@@ -87,16 +108,14 @@ func (conv *Converter) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	}
 
 	for _, entry := range entries {
-		conv.mut.RLock()
-		for _, receiver := range conv.next {
+		for _, receiver := range next {
 			select {
 			case <-ctx.Done():
-				return nil
+				return ctx.Err()
 			case receiver.Chan() <- entry:
 				// no-op, send the entry along
 			}
 		}
-		conv.mut.RUnlock()
 	}
 	return nil
 }
@@ -108,3 +127,13 @@ func (conv *Converter) UpdateFanout(fanout []loki.LogsReceiver) {
 
 	conv.next = fanout
 }
+
+// UpdateAttributesConfig sets how the converter maps the attributes of logs
+// to labels and structured metadata. If cfg is nil, the loki translator is
+// used, which relies on attribute hints to select labels.
+func (conv *Converter) UpdateAttributesConfig(cfg *AttributesConfig) {
+	conv.mut.Lock()
+	defer conv.mut.Unlock()
+
+	conv.attrs = cfg
+}
@@ -1,17 +1,19 @@
 package convert_test
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/grafana/loki/pkg/push"
+	"github.com/grafana/regexp"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
-	"github.com/grafana/alloy/internal/component/otelcol/exporter/loki/internal/convert"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/loki/convert"
 	"github.com/grafana/alloy/internal/component/otelcol/processor/processortest"
 	"github.com/grafana/alloy/internal/util"
 )
@@ -361,3 +363,158 @@ func entryToStream(entry *loki.Entry) push.Stream {
 		},
 	}
 }
+
+func TestConsumeLogsWithAttributesConfig(t *testing.T) {
+	inputLogJson := `{
+		"resourceLogs": [{
+			"resource": {
+				"attributes": [{
+					"key": "service.name",
+					"value": { "stringValue": "checkout" }
+				},
+				{
+					"key": "host.name",
+					"value": { "stringValue": "host-1" }
+				}]
+			},
+			"scopeLogs": [{
+				"scope": {
+					"name": "checkout-logger",
+					"attributes": [{
+						"key": "internal.id",
+						"value": { "stringValue": "123" }
+					}]
+				},
+				"log_records": [{
+					"timeUnixNano": "1581452773000000111",
+					"severityNumber": 9,
+					"severityText": "Info",
+					"body": { "stringValue": "order placed" },
+					"traceId": "0102030405060708090a0b0c0d0e0f10",
+					"attributes": [{
+						"key": "http.method",
+						"value": { "stringValue": "POST" }
+					},
+					{
+						"key": "team",
+						"value": { "stringValue": "payments" }
+					}]
+				}]
+			}]
+		},
+		{
+			"scopeLogs": [{
+				"log_records": [{
+					"observedTimeUnixNano": "1581452773000000222",
+					"body": { "stringValue": "no resource" }
+				}]
+			}]
+		}]
+	}`
+
+	expectedEntries := []loki.Entry{
+		{
+			Labels: model.LabelSet{
+				"service_name": "checkout",
+				"team":         "payments",
+			},
+			Entry: push.Entry{
+				Timestamp: time.Unix(0, int64(1581452773000000111)),
+				Line:      "order placed",
+				StructuredMetadata: push.LabelsAdapter{
+					{Name: "host_name", Value: "host-1"},
+					{Name: "http_method", Value: "POST"},
+					{Name: "scope_name", Value: "checkout-logger"},
+					{Name: "severity_text", Value: "Info"},
+					{Name: "severity_number", Value: "9"},
+					{Name: "trace_id", Value: "0102030405060708090a0b0c0d0e0f10"},
+				},
+			},
+		},
+		{
+			Labels: model.LabelSet{
+				"service_name": "unknown_service",
+			},
+			Entry: push.Entry{
+				Timestamp: time.Unix(0, int64(1581452773000000222)),
+				Line:      "no resource",
+			},
+		},
+	}
+
+	receiver := loki.NewLogsReceiverWithChannel(make(chan loki.Entry, len(expectedEntries)))
+	converter := convert.NewWithMetricsPrefix(util.TestAlloyLogger(t), prometheus.NewRegistry(), "loki_source_otlp", []loki.LogsReceiver{receiver})
+	converter.UpdateAttributesConfig(&convert.AttributesConfig{
+		ResourceAttributes: []convert.AttributesRule{
+			{Action: convert.ActionIndexLabel, Attributes: convert.DefaultResourceLabels},
+		},
+		ScopeAttributes: []convert.AttributesRule{
+			{Action: convert.ActionDrop, Regex: regexp.MustCompile(`internal\..*`)},
+		},
+		LogAttributes: []convert.AttributesRule{
+			{Action: convert.ActionIndexLabel, Attributes: []string{"team"}},
+		},
+	})
+
+	require.NoError(t, converter.ConsumeLogs(t.Context(), processortest.CreateTestLogs(inputLogJson)))
+	close(receiver.Chan())
+
+	var receivedEntries []loki.Entry
+	for entry := range receiver.Chan() {
+		receivedEntries = append(receivedEntries, entry)
+	}
+	require.Len(t, receivedEntries, len(expectedEntries))
+	for i := range expectedEntries {
+		compareLokiEntries(t, &expectedEntries[i], &receivedEntries[i])
+	}
+}
+
+func TestConsumeLogs_CanceledContext(t *testing.T) {
+	inputLogJson := `{
+		"resourceLogs": [{
+			"scopeLogs": [{
+				"log_records": [{
+					"timeUnixNano": "1581452773000000111",
+					"body": { "stringValue": "first" }
+				},
+				{
+					"timeUnixNano": "1581452773000000222",
+					"body": { "stringValue": "second" }
+				}]
+			}]
+		}]
+	}`
+
+	// Nothing reads from the receiver, so sending the entries blocks until the
+	// context is canceled.
+	receiver := loki.NewLogsReceiverWithChannel(make(chan loki.Entry))
+	converter := convert.New(util.TestAlloyLogger(t), prometheus.NewRegistry(), []loki.LogsReceiver{receiver})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- converter.ConsumeLogs(ctx, processortest.CreateTestLogs(inputLogJson))
+	}()
+
+	cancel()
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "ConsumeLogs didn't return after the context was canceled")
+	}
+
+	// The converter must still be updatable, which requires that ConsumeLogs
+	// released its lock.
+	updated := make(chan struct{})
+	go func() {
+		converter.UpdateFanout(nil)
+		converter.UpdateAttributesConfig(nil)
+		close(updated)
+	}()
+	select {
+	case <-updated:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the converter couldn't be updated after ConsumeLogs returned")
+	}
+}
@@ -11,19 +11,19 @@ type metrics struct {
 	entriesProcessed prometheus_client.Counter
 }
 
-func newMetrics(reg prometheus_client.Registerer) *metrics {
+func newMetrics(reg prometheus_client.Registerer, prefix string) *metrics {
 	var m metrics
 
 	m.entriesTotal = prometheus_client.NewCounter(prometheus_client.CounterOpts{
-		Name: "otelcol_exporter_loki_entries_total",
+		Name: prefix + "_entries_total",
 		Help: "Total number of log entries passed through the converter",
 	})
 	m.entriesFailed = prometheus_client.NewCounter(prometheus_client.CounterOpts{
-		Name: "otelcol_exporter_loki_entries_failed",
+		Name: prefix + "_entries_failed",
 		Help: "Total number of log entries failed to convert",
 	})
 	m.entriesProcessed = prometheus_client.NewCounter(prometheus_client.CounterOpts{
-		Name: "otelcol_exporter_loki_entries_processed",
+		Name: prefix + "_entries_processed",
 		Help: "Total number of log entries successfully converted",
 	})
 
@@ -0,0 +1,113 @@
+package livedebuggingpublisher
+
+import (
+	"maps"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// logsAttributes returns the attributes of every log record, merged with the
+// attributes of its resource and scope, so that live debugging consumers can
+// filter on them.
+func logsAttributes(ld plog.Logs) []map[string]string {
+	res := make([]map[string]string, 0, ld.LogRecordCount())
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resourceAttrs := attributesMap(nil, rl.Resource().Attributes())
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			scopeAttrs := attributesMap(resourceAttrs, sl.Scope().Attributes())
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				res = append(res, attributesMap(scopeAttrs, sl.LogRecords().At(k).Attributes()))
+			}
+		}
+	}
+	return res
+}
+
+// tracesAttributes returns the attributes of every span, merged with the
+// attributes of its resource and scope.
+func tracesAttributes(td ptrace.Traces) []map[string]string {
+	res := make([]map[string]string, 0, td.SpanCount())
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		resourceAttrs := attributesMap(nil, rs.Resource().Attributes())
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			scopeAttrs := attributesMap(resourceAttrs, ss.Scope().Attributes())
+			for k := 0; k < ss.Spans().Len(); k++ {
+				res = append(res, attributesMap(scopeAttrs, ss.Spans().At(k).Attributes()))
+			}
+		}
+	}
+	return res
+}
+
+// metricsAttributes returns the attributes of every data point, merged with
+// the attributes of its resource and scope. The name of the metric is set as
+// the __name__ attribute.
+func metricsAttributes(md pmetric.Metrics) []map[string]string {
+	res := make([]map[string]string, 0, md.DataPointCount())
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		resourceAttrs := attributesMap(nil, rm.Resource().Attributes())
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			scopeAttrs := attributesMap(resourceAttrs, sm.Scope().Attributes())
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				m := sm.Metrics().At(k)
+				metricAttrs := maps.Clone(scopeAttrs)
+				metricAttrs["__name__"] = m.Name()
+				for _, attrs := range dataPointsAttributes(m) {
+					res = append(res, attributesMap(metricAttrs, attrs))
+				}
+			}
+		}
+	}
+	return res
+}
+
+func dataPointsAttributes(m pmetric.Metric) []pcommon.Map {
+	var res []pcommon.Map
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < m.Gauge().DataPoints().Len(); i++ {
+			res = append(res, m.Gauge().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		for i := 0; i < m.Sum().DataPoints().Len(); i++ {
+			res = append(res, m.Sum().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < m.Histogram().DataPoints().Len(); i++ {
+			res = append(res, m.Histogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		for i := 0; i < m.ExponentialHistogram().DataPoints().Len(); i++ {
+			res = append(res, m.ExponentialHistogram().DataPoints().At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		for i := 0; i < m.Summary().DataPoints().Len(); i++ {
+			res = append(res, m.Summary().DataPoints().At(i).Attributes())
+		}
+	}
+	if len(res) == 0 {
+		// Keep metrics without data points so that they can be matched by name.
+		res = append(res, pcommon.NewMap())
+	}
+	return res
+}
+
+// attributesMap returns a copy of parent with attrs added to it.
+func attributesMap(parent map[string]string, attrs pcommon.Map) map[string]string {
+	res := make(map[string]string, len(parent)+attrs.Len())
+	maps.Copy(res, parent)
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		res[k] = v.AsString()
+		return true
+	})
+	return res
+}
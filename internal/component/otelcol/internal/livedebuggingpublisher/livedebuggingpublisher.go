@@ -22,6 +22,7 @@ func PublishLogsIfActive(debugDataPublisher livedebugging.DebugDataPublisher, co
 			return string(data)
 		},
 		livedebugging.WithTargetComponentIDs(extractIds(nextLogs)),
+		livedebugging.WithLabels(func() []map[string]string { return logsAttributes(ld) }),
 	))
 }
 
@@ -38,6 +39,7 @@ func PublishTracesIfActive(debugDataPublisher livedebugging.DebugDataPublisher,
 			return string(data)
 		},
 		livedebugging.WithTargetComponentIDs(extractIds(nextTraces)),
+		livedebugging.WithLabels(func() []map[string]string { return tracesAttributes(td) }),
 	))
 }
 
@@ -54,6 +56,7 @@ func PublishMetricsIfActive(debugDataPublisher livedebugging.DebugDataPublisher,
 			return string(data)
 		},
 		livedebugging.WithTargetComponentIDs(extractIds(nextMetrics)),
+		livedebugging.WithLabels(func() []map[string]string { return metricsAttributes(md) }),
 	))
 }
 
@@ -0,0 +1,165 @@
+// Package redaction provides an otelcol.processor.redaction component.
+package redaction
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/loki/secretfilter"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/processor"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.processor.redaction",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := redactionprocessor.NewFactory()
+			return processor.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.processor.redaction component.
+type Arguments struct {
+	AllowAllKeys       bool     `alloy:"allow_all_keys,attr,optional"`
+	AllowedKeys        []string `alloy:"allowed_keys,attr,optional"`
+	IgnoredKeys        []string `alloy:"ignored_keys,attr,optional"`
+	BlockedKeyPatterns []string `alloy:"blocked_key_patterns,attr,optional"`
+	BlockedValues      []string `alloy:"blocked_values,attr,optional"`
+	AllowedValues      []string `alloy:"allowed_values,attr,optional"`
+	HashFunction       string   `alloy:"hash_function,attr,optional"`
+	Summary            string   `alloy:"summary,attr,optional"`
+
+	// GitleaksRules blocks the values matching the rules of the gitleaks
+	// configuration embedded in loki.secretfilter.
+	GitleaksRules bool `alloy:"gitleaks_rules,attr,optional"`
+
+	// Output configures where to send processed data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var (
+	_ processor.Arguments = Arguments{}
+	_ syntax.Validator    = (*Arguments)(nil)
+	_ syntax.Defaulter    = (*Arguments)(nil)
+)
+
+// DefaultArguments holds default settings for Arguments.
+var DefaultArguments = Arguments{
+	Summary: "info",
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	switch args.HashFunction {
+	case "", "md5", "sha1", "sha3":
+	default:
+		return fmt.Errorf("unknown hash_function %q, allowed values are \"md5\", \"sha1\" and \"sha3\"", args.HashFunction)
+	}
+
+	switch args.Summary {
+	case "debug", "info", "silent":
+	default:
+		return fmt.Errorf("unknown summary %q, allowed values are \"debug\", \"info\" and \"silent\"", args.Summary)
+	}
+
+	for _, patterns := range [][]string{args.BlockedKeyPatterns, args.BlockedValues, args.AllowedValues} {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Convert implements processor.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	blockedValues := args.BlockedValues
+	if args.GitleaksRules {
+		rules, err := gitleaksRegexes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the gitleaks rules: %w", err)
+		}
+		blockedValues = append(append([]string{}, blockedValues...), rules...)
+	}
+
+	return &redactionprocessor.Config{
+		AllowAllKeys:       args.AllowAllKeys,
+		AllowedKeys:        args.AllowedKeys,
+		IgnoredKeys:        args.IgnoredKeys,
+		BlockedKeyPatterns: args.BlockedKeyPatterns,
+		BlockedValues:      blockedValues,
+		AllowedValues:      args.AllowedValues,
+		HashFunction:       redactionprocessor.HashFunction(args.HashFunction),
+		Summary:            args.Summary,
+	}, nil
+}
+
+// gitleaksRegexes returns the regexes of the rules of the embedded gitleaks
+// configuration. Like in loki.secretfilter, the generic-api-key rule and the
+// rules matching the empty string are excluded.
+func gitleaksRegexes() ([]string, error) {
+	cfg, err := secretfilter.EmbeddedGitleaksConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		if rule.Regex == "" || strings.ToLower(rule.ID) == "generic-api-key" {
+			continue
+		}
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+		if re.MatchString("") {
+			continue
+		}
+		res = append(res, rule.Regex)
+	}
+	return res, nil
+}
+
+// Extensions implements processor.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements processor.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements processor.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements processor.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
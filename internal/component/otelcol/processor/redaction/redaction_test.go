@@ -0,0 +1,124 @@
+package redaction_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/otelcol/processor/redaction"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		expected redactionprocessor.Config
+		errorMsg string
+	}{
+		{
+			testName: "Defaults",
+			cfg: `
+				output {}
+			`,
+			expected: redactionprocessor.Config{
+				Summary: "info",
+			},
+		},
+		{
+			testName: "ExplicitValues",
+			cfg: `
+				allow_all_keys       = true
+				ignored_keys         = ["safe_attribute"]
+				blocked_key_patterns = [".*token.*"]
+				blocked_values       = ["4[0-9]{12}(?:[0-9]{3})?"]
+				allowed_values       = [".+@example.com"]
+				hash_function        = "sha3"
+				summary              = "debug"
+				output {}
+			`,
+			expected: redactionprocessor.Config{
+				AllowAllKeys:       true,
+				IgnoredKeys:        []string{"safe_attribute"},
+				BlockedKeyPatterns: []string{".*token.*"},
+				BlockedValues:      []string{"4[0-9]{12}(?:[0-9]{3})?"},
+				AllowedValues:      []string{".+@example.com"},
+				HashFunction:       redactionprocessor.SHA3,
+				Summary:            "debug",
+			},
+		},
+		{
+			testName: "InvalidHashFunction",
+			cfg: `
+				hash_function = "sha256"
+				output {}
+			`,
+			errorMsg: `unknown hash_function "sha256"`,
+		},
+		{
+			testName: "InvalidSummary",
+			cfg: `
+				summary = "verbose"
+				output {}
+			`,
+			errorMsg: `unknown summary "verbose"`,
+		},
+		{
+			testName: "InvalidRegex",
+			cfg: `
+				blocked_values = ["("]
+				output {}
+			`,
+			errorMsg: `invalid regular expression "("`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args redaction.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+
+			actualPtr, err := args.Convert()
+			require.NoError(t, err)
+
+			actual := actualPtr.(*redactionprocessor.Config)
+			require.Equal(t, tc.expected, *actual)
+		})
+	}
+}
+
+func TestArguments_GitleaksRules(t *testing.T) {
+	cfg := `
+		allow_all_keys = true
+		blocked_values = ["4[0-9]{12}(?:[0-9]{3})?"]
+		gitleaks_rules = true
+		output {}
+	`
+	var args redaction.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	actualPtr, err := args.Convert()
+	require.NoError(t, err)
+	actual := actualPtr.(*redactionprocessor.Config)
+
+	// The configured values come first, and are not modified.
+	require.Equal(t, "4[0-9]{12}(?:[0-9]{3})?", actual.BlockedValues[0])
+	require.Equal(t, []string{"4[0-9]{12}(?:[0-9]{3})?"}, args.BlockedValues)
+
+	// A GitHub personal access token is blocked by one of the gitleaks rules.
+	token := "ghp_" + "abcdefghijklmnopqrstuvwxyz0123456789"
+	var matched bool
+	for _, v := range actual.BlockedValues[1:] {
+		if regexp.MustCompile(v).MatchString(token) {
+			matched = true
+			break
+		}
+	}
+	require.True(t, matched, "no gitleaks rule matches the token")
+}
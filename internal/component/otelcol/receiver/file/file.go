@@ -0,0 +1,131 @@
+// Package file provides an otelcol.receiver.file component.
+package file
+
+import (
+	"errors"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/file/internal/filereplay"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.file",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := filereplay.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Supported values of the format and compression arguments. They match the
+// values supported by otelcol.exporter.file.
+const (
+	FormatJSON  = filereplay.FormatJSON
+	FormatProto = filereplay.FormatProto
+
+	CompressionZstd = filereplay.CompressionZstd
+)
+
+// Arguments configures the otelcol.receiver.file component.
+type Arguments struct {
+	Include     []string `alloy:"include,attr"`
+	Exclude     []string `alloy:"exclude,attr,optional"`
+	Format      string   `alloy:"format,attr,optional"`
+	Compression string   `alloy:"compression,attr,optional"`
+	Throttle    float64  `alloy:"throttle,attr,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+var (
+	_ receiver.Arguments = Arguments{}
+	_ syntax.Defaulter   = (*Arguments)(nil)
+	_ syntax.Validator   = (*Arguments)(nil)
+)
+
+// DefaultArguments holds default settings for Arguments.
+var DefaultArguments = Arguments{
+	Format:   FormatJSON,
+	Throttle: 1,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	cfg, err := args.Convert()
+	if err != nil {
+		return err
+	}
+	if err := cfg.(*filereplay.Config).Validate(); err != nil {
+		return err
+	}
+
+	// Unlike JSON, the protobuf encoding of the telemetry doesn't tell which
+	// signal it is, so it can only be decoded for a single signal.
+	if args.Format == FormatProto && args.Output != nil && args.outputSignals() > 1 {
+		return errors.New("the proto format requires the output of a single signal")
+	}
+	return nil
+}
+
+// outputSignals returns the number of signals which have an output.
+func (args *Arguments) outputSignals() int {
+	var res int
+	for _, next := range [][]otelcol.Consumer{args.Output.Metrics, args.Output.Logs, args.Output.Traces} {
+		if len(next) > 0 {
+			res++
+		}
+	}
+	return res
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	return &filereplay.Config{
+		Include:     args.Include,
+		Exclude:     args.Exclude,
+		Format:      args.Format,
+		Compression: args.Compression,
+		Throttle:    args.Throttle,
+	}, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
@@ -0,0 +1,227 @@
+package file_test
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/file"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestReplay_JSON(t *testing.T) {
+	dir := t.TempDir()
+
+	// The files are written like otelcol.exporter.file does: one message per
+	// line. The rotated file is replayed before the current file.
+	writeJSONLines(t, filepath.Join(dir, "capture.json"), jsonLogs(t, "second"), jsonTraces(t, "span"))
+	writeJSONLines(t, filepath.Join(dir, "capture-2024-01-01T00-00-00.000.json"), jsonLogs(t, "first"))
+
+	cfg := fmt.Sprintf(`
+		include  = [%q]
+		throttle = 0
+		output {}
+	`, filepath.Join(dir, "*.json"))
+	var args file.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	logsCh := make(chan string, 10)
+	tracesCh := make(chan string, 10)
+	args.Output = &otelcol.ConsumerArguments{
+		Logs: []otelcol.Consumer{&fakeconsumer.Consumer{
+			ConsumeLogsFunc: func(_ context.Context, ld plog.Logs) error {
+				logsCh <- ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str()
+				return nil
+			},
+		}},
+		Traces: []otelcol.Consumer{&fakeconsumer.Consumer{
+			ConsumeTracesFunc: func(_ context.Context, td ptrace.Traces) error {
+				tracesCh <- td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name()
+				return nil
+			},
+		}},
+	}
+
+	runReceiver(t, args)
+
+	require.Equal(t, "first", receive(t, logsCh))
+	require.Equal(t, "second", receive(t, logsCh))
+	require.Equal(t, "span", receive(t, tracesCh))
+}
+
+func TestReplay_ProtoZstd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.proto")
+
+	// The messages are written like otelcol.exporter.file does: compressed
+	// and prefixed by their size.
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	var data []byte
+	for _, body := range []string{"first", "second"} {
+		msg, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(testTraces(body))
+		require.NoError(t, err)
+		msg = encoder.EncodeAll(msg, nil)
+		data = binary.BigEndian.AppendUint32(data, uint32(len(msg)))
+		data = append(data, msg...)
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	cfg := fmt.Sprintf(`
+		include     = [%q]
+		format      = "proto"
+		compression = "zstd"
+		throttle    = 0
+		output {}
+	`, path)
+	var args file.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	tracesCh := make(chan string, 10)
+	args.Output = &otelcol.ConsumerArguments{
+		Traces: []otelcol.Consumer{&fakeconsumer.Consumer{
+			ConsumeTracesFunc: func(_ context.Context, td ptrace.Traces) error {
+				tracesCh <- td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name()
+				return nil
+			},
+		}},
+	}
+
+	runReceiver(t, args)
+
+	require.Equal(t, "first", receive(t, tracesCh))
+	require.Equal(t, "second", receive(t, tracesCh))
+}
+
+func TestArguments_UnmarshalAlloy(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		errorMsg string
+	}{
+		{
+			testName: "Defaults",
+			cfg: `
+				include = ["/tmp/capture*.json"]
+				output {}
+			`,
+		},
+		{
+			testName: "MissingInclude",
+			cfg: `
+				include = []
+				output {}
+			`,
+			errorMsg: "include must contain at least one pattern",
+		},
+		{
+			testName: "InvalidFormat",
+			cfg: `
+				include = ["/tmp/capture*.txt"]
+				format  = "text"
+				output {}
+			`,
+			errorMsg: `unknown format "text"`,
+		},
+		{
+			testName: "InvalidCompression",
+			cfg: `
+				include     = ["/tmp/capture*.json"]
+				compression = "gzip"
+				output {}
+			`,
+			errorMsg: `unknown compression "gzip"`,
+		},
+		{
+			testName: "NegativeThrottle",
+			cfg: `
+				include  = ["/tmp/capture*.json"]
+				throttle = -1
+				output {}
+			`,
+			errorMsg: "throttle must not be negative",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args file.Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.errorMsg != "" {
+				require.ErrorContains(t, err, tc.errorMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, file.FormatJSON, args.Format)
+			require.Equal(t, 1.0, args.Throttle)
+		})
+	}
+}
+
+func runReceiver(t *testing.T, args file.Arguments) {
+	ctx := componenttest.TestContext(t)
+
+	ctrl, err := componenttest.NewControllerFromID(util.TestLogger(t), "otelcol.receiver.file")
+	require.NoError(t, err)
+
+	go func() {
+		require.NoError(t, ctrl.Run(ctx, args))
+	}()
+	require.NoError(t, ctrl.WaitRunning(time.Second), "component never started")
+}
+
+func receive(t *testing.T, ch chan string) string {
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(time.Second):
+		require.FailNow(t, "failed waiting for telemetry")
+		return ""
+	}
+}
+
+func writeJSONLines(t *testing.T, path string, messages ...[]byte) {
+	var data []byte
+	for _, msg := range messages {
+		data = append(data, msg...)
+		data = append(data, '\n')
+	}
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func jsonLogs(t *testing.T, body string) []byte {
+	ld := plog.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	lr.Body().SetStr(body)
+
+	msg, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+	require.NoError(t, err)
+	return msg
+}
+
+func testTraces(name string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	span.SetName(name)
+	return td
+}
+
+func jsonTraces(t *testing.T, name string) []byte {
+	msg, err := (&ptrace.JSONMarshaler{}).MarshalTraces(testTraces(name))
+	require.NoError(t, err)
+	return msg
+}
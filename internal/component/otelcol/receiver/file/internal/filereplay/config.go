@@ -0,0 +1,66 @@
+package filereplay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bmatcuk/doublestar"
+	"go.opentelemetry.io/collector/component"
+)
+
+// Supported values of the format and compression settings. They match the
+// values supported by otelcol.exporter.file.
+const (
+	FormatJSON  = "json"
+	FormatProto = "proto"
+
+	CompressionZstd = "zstd"
+)
+
+// Config configures the file replay receiver.
+type Config struct {
+	// Include and Exclude are the glob patterns of the files to replay and of
+	// the files not to replay.
+	Include []string `mapstructure:"include"`
+	Exclude []string `mapstructure:"exclude"`
+
+	// Format and Compression are the format and compression the files were
+	// written with by otelcol.exporter.file.
+	Format      string `mapstructure:"format"`
+	Compression string `mapstructure:"compression"`
+
+	// Throttle is the factor applied to the original time between the
+	// telemetry. Zero replays the telemetry as fast as possible.
+	Throttle float64 `mapstructure:"throttle"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the configuration is valid.
+func (cfg *Config) Validate() error {
+	if len(cfg.Include) == 0 {
+		return errors.New("include must contain at least one pattern")
+	}
+	for _, pattern := range append(append([]string{}, cfg.Include...), cfg.Exclude...) {
+		if _, err := doublestar.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	switch cfg.Format {
+	case FormatJSON, FormatProto:
+	default:
+		return fmt.Errorf("unknown format %q, allowed values are %q and %q", cfg.Format, FormatJSON, FormatProto)
+	}
+
+	switch cfg.Compression {
+	case "", CompressionZstd:
+	default:
+		return fmt.Errorf("unknown compression %q, the only allowed value is %q", cfg.Compression, CompressionZstd)
+	}
+
+	if cfg.Throttle < 0 {
+		return errors.New("throttle must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package filereplay provides an OpenTelemetry Collector receiver which
+// replays the telemetry written to files by otelcol.exporter.file.
+package filereplay
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+)
+
+const typeStr = "filereplay"
+
+// NewFactory creates a factory for the file replay receiver.
+//
+// A file can hold the telemetry of every signal, so the receivers created for
+// the signals of the same configuration share a single receiver, which reads
+// the files once.
+func NewFactory() receiver.Factory {
+	f := &factory{}
+	return receiver.NewFactory(
+		component.MustNewType(typeStr),
+		createDefaultConfig,
+		receiver.WithMetrics(f.createMetrics, component.StabilityLevelDevelopment),
+		receiver.WithLogs(f.createLogs, component.StabilityLevelDevelopment),
+		receiver.WithTraces(f.createTraces, component.StabilityLevelDevelopment),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Format:   FormatJSON,
+		Throttle: 1,
+	}
+}
+
+type factory struct {
+	mut     sync.Mutex
+	current *fileReceiver
+}
+
+// receiver returns the receiver of cfg, creating it if cfg isn't the
+// configuration of the latest receiver.
+func (f *factory) receiver(set receiver.Settings, cfg *Config) (*fileReceiver, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if f.current == nil || f.current.cfg != cfg {
+		r, err := newFileReceiver(set, cfg)
+		if err != nil {
+			return nil, err
+		}
+		f.current = r
+	}
+	return f.current, nil
+}
+
+func (f *factory) createMetrics(_ context.Context, set receiver.Settings, cfg component.Config, next consumer.Metrics) (receiver.Metrics, error) {
+	r, err := f.receiver(set, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.metrics = next
+	return r, nil
+}
+
+func (f *factory) createLogs(_ context.Context, set receiver.Settings, cfg component.Config, next consumer.Logs) (receiver.Logs, error) {
+	r, err := f.receiver(set, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.logs = next
+	return r, nil
+}
+
+func (f *factory) createTraces(_ context.Context, set receiver.Settings, cfg component.Config, next consumer.Traces) (receiver.Traces, error) {
+	r, err := f.receiver(set, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.traces = next
+	return r, nil
+}
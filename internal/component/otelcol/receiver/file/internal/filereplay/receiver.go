@@ -0,0 +1,222 @@
+package filereplay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bmatcuk/doublestar"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+)
+
+// transport is the transport reported by the receiver metrics.
+const transport = "file"
+
+// fileReceiver replays the files matching its configuration once, when it
+// starts.
+type fileReceiver struct {
+	cfg     *Config
+	logger  *zap.Logger
+	obsrecv *receiverhelper.ObsReport
+
+	// The consumers of the signals the receiver was created for. They are
+	// set by the factory before the receiver starts.
+	metrics consumer.Metrics
+	logs    consumer.Logs
+	traces  consumer.Traces
+
+	mut     sync.Mutex
+	cancel  context.CancelFunc // Set once the receiver started.
+	stopped bool
+	done    chan struct{}
+}
+
+var (
+	_ receiver.Metrics = (*fileReceiver)(nil)
+	_ receiver.Logs    = (*fileReceiver)(nil)
+	_ receiver.Traces  = (*fileReceiver)(nil)
+)
+
+func newFileReceiver(set receiver.Settings, cfg *Config) (*fileReceiver, error) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileReceiver{
+		cfg:     cfg,
+		logger:  set.Logger,
+		obsrecv: obsrecv,
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Start implements component.Component. The receiver is shared by the
+// signals, so only the first call starts the replay.
+func (r *fileReceiver) Start(_ context.Context, _ component.Host) error {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.cancel != nil || r.stopped {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	go func() {
+		defer close(r.done)
+		r.replay(ctx)
+	}()
+	return nil
+}
+
+// Shutdown implements component.Component. Only the first call stops the
+// replay.
+func (r *fileReceiver) Shutdown(ctx context.Context) error {
+	r.mut.Lock()
+	if r.stopped {
+		r.mut.Unlock()
+		return nil
+	}
+	r.stopped = true
+	cancel := r.cancel
+	r.mut.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// replay sends the telemetry of the files matching the configuration to the
+// consumers.
+func (r *fileReceiver) replay(ctx context.Context) {
+	paths, err := r.files()
+	if err != nil {
+		r.logger.Error("failed to list the files to replay", zap.Error(err))
+		return
+	}
+	if len(paths) == 0 {
+		r.logger.Warn("no file to replay")
+		return
+	}
+
+	rp, err := newReplayer(r)
+	if err != nil {
+		r.logger.Error("failed to create the replayer", zap.Error(err))
+		return
+	}
+	defer rp.close()
+
+	for _, path := range paths {
+		r.logger.Info("replaying file", zap.String("path", path))
+		if err := rp.replayFile(ctx, path); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Error("failed to replay file", zap.String("path", path), zap.Error(err))
+		}
+	}
+	r.logger.Info("finished replaying files", zap.Int("count", len(paths)))
+}
+
+// files returns the paths of the files to replay, sorted lexically.
+//
+// otelcol.exporter.file rotates files by renaming them to
+// NAME-TIMESTAMP.EXT, where TIMESTAMP is formatted as
+// 2006-01-02T15-04-05.000, while it keeps writing to NAME.EXT. The lexical
+// order replays the rotated files from the oldest to the newest, and then the
+// current file, because '-' sorts before '.'. Files which don't follow this
+// naming are replayed in lexical order too.
+func (r *fileReceiver) files() ([]string, error) {
+	seen := make(map[string]struct{})
+	var res []string
+	for _, include := range r.cfg.Include {
+		matches, err := doublestar.Glob(include)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", include, err)
+		}
+
+	outer:
+		for _, m := range matches {
+			for _, exclude := range r.cfg.Exclude {
+				if match, _ := doublestar.PathMatch(exclude, m); match {
+					continue outer
+				}
+			}
+			if _, ok := seen[m]; ok {
+				continue
+			}
+			seen[m] = struct{}{}
+			res = append(res, m)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// signals returns the signals the receiver has a consumer for.
+func (r *fileReceiver) signals() []signal {
+	var res []signal
+	if r.metrics != nil {
+		res = append(res, signalMetrics)
+	}
+	if r.logs != nil {
+		res = append(res, signalLogs)
+	}
+	if r.traces != nil {
+		res = append(res, signalTraces)
+	}
+	return res
+}
+
+// The consume methods drop the telemetry of signals without a consumer.
+
+func (r *fileReceiver) consumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if r.metrics == nil {
+		return nil
+	}
+	ctx = r.obsrecv.StartMetricsOp(ctx)
+	err := r.metrics.ConsumeMetrics(ctx, md)
+	r.obsrecv.EndMetricsOp(ctx, r.cfg.Format, md.DataPointCount(), err)
+	return err
+}
+
+func (r *fileReceiver) consumeLogs(ctx context.Context, ld plog.Logs) error {
+	if r.logs == nil {
+		return nil
+	}
+	ctx = r.obsrecv.StartLogsOp(ctx)
+	err := r.logs.ConsumeLogs(ctx, ld)
+	r.obsrecv.EndLogsOp(ctx, r.cfg.Format, ld.LogRecordCount(), err)
+	return err
+}
+
+func (r *fileReceiver) consumeTraces(ctx context.Context, td ptrace.Traces) error {
+	if r.traces == nil {
+		return nil
+	}
+	ctx = r.obsrecv.StartTracesOp(ctx)
+	err := r.traces.ConsumeTraces(ctx, td)
+	r.obsrecv.EndTracesOp(ctx, r.cfg.Format, td.SpanCount(), err)
+	return err
+}
@@ -0,0 +1,273 @@
+package filereplay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+type signal int
+
+const (
+	signalMetrics signal = iota
+	signalLogs
+	signalTraces
+)
+
+// maxMessageSize is the maximum size of a length-prefixed message. Larger
+// sizes usually mean that the file isn't in the configured format.
+const maxMessageSize = 256 << 20
+
+// replayer reads the files written by otelcol.exporter.file, and sends their
+// telemetry to the consumers of the receiver.
+type replayer struct {
+	cfg     *Config
+	recv    *fileReceiver
+	decoder *zstd.Decoder
+
+	// prevTimestamp is the latest timestamp of the replayed telemetry, used
+	// to throttle the replay.
+	prevTimestamp pcommon.Timestamp
+}
+
+func newReplayer(recv *fileReceiver) (*replayer, error) {
+	r := &replayer{
+		cfg:  recv.cfg,
+		recv: recv,
+	}
+	if r.cfg.Compression == CompressionZstd {
+		decoder, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		r.decoder = decoder
+	}
+	return r, nil
+}
+
+func (r *replayer) close() {
+	if r.decoder != nil {
+		r.decoder.Close()
+	}
+}
+
+// replayFile replays the messages of the file at path, in order.
+func (r *replayer) replayFile(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	for {
+		msg, err := r.readMessage(br)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := r.replayMessage(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// readMessage reads the next message from br. It returns io.EOF when there
+// are no more messages.
+func (r *replayer) readMessage(br *bufio.Reader) ([]byte, error) {
+	// Uncompressed JSON is written as one message per line, and everything
+	// else as messages prefixed by their size.
+	if r.cfg.Format == FormatJSON && r.cfg.Compression == "" {
+		for {
+			line, err := br.ReadBytes('\n')
+			line = bytes.TrimSpace(line)
+			if len(line) > 0 {
+				return line, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(br, size[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errors.New("truncated message size")
+		}
+		return nil, err
+	}
+
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxMessageSize {
+		return nil, fmt.Errorf("message size %d is too large, check the format and the compression", n)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(br, msg); err != nil {
+		return nil, errors.New("truncated message")
+	}
+
+	if r.decoder != nil {
+		return r.decoder.DecodeAll(msg, nil)
+	}
+	return msg, nil
+}
+
+// replayMessage decodes msg and sends it to the consumers. When the replay is
+// throttled, the telemetry of msg is sent in batches of the same timestamp,
+// each one when it's time to replay it. Failures to send the telemetry are
+// only logged, so that the rest of the file is still replayed.
+func (r *replayer) replayMessage(ctx context.Context, msg []byte) error {
+	sig, err := r.signal(msg)
+	if err != nil {
+		return err
+	}
+
+	switch sig {
+	case signalMetrics:
+		md, err := r.metricsUnmarshaler().UnmarshalMetrics(msg)
+		if err != nil {
+			return fmt.Errorf("failed to decode metrics: %w", err)
+		}
+		if r.cfg.Throttle == 0 {
+			r.logConsumeError("metrics", r.recv.consumeMetrics(ctx, md))
+			return nil
+		}
+		for _, b := range splitMetrics(md) {
+			if err := r.wait(ctx, b.timestamp); err != nil {
+				return err
+			}
+			r.logConsumeError("metrics", r.recv.consumeMetrics(ctx, b.data))
+		}
+
+	case signalLogs:
+		ld, err := r.logsUnmarshaler().UnmarshalLogs(msg)
+		if err != nil {
+			return fmt.Errorf("failed to decode logs: %w", err)
+		}
+		if r.cfg.Throttle == 0 {
+			r.logConsumeError("logs", r.recv.consumeLogs(ctx, ld))
+			return nil
+		}
+		for _, b := range splitLogs(ld) {
+			if err := r.wait(ctx, b.timestamp); err != nil {
+				return err
+			}
+			r.logConsumeError("logs", r.recv.consumeLogs(ctx, b.data))
+		}
+
+	case signalTraces:
+		td, err := r.tracesUnmarshaler().UnmarshalTraces(msg)
+		if err != nil {
+			return fmt.Errorf("failed to decode traces: %w", err)
+		}
+		if r.cfg.Throttle == 0 {
+			r.logConsumeError("traces", r.recv.consumeTraces(ctx, td))
+			return nil
+		}
+		for _, b := range splitTraces(td) {
+			if err := r.wait(ctx, b.timestamp); err != nil {
+				return err
+			}
+			r.logConsumeError("traces", r.recv.consumeTraces(ctx, b.data))
+		}
+	}
+	return nil
+}
+
+func (r *replayer) logConsumeError(signal string, err error) {
+	if err != nil {
+		r.recv.logger.Error("failed to send replayed "+signal, zap.Error(err))
+	}
+}
+
+// signal returns the signal of msg. The signal of a JSON message is given by
+// its top-level field, and the signal of a protobuf message is the only
+// signal with a consumer.
+func (r *replayer) signal(msg []byte) (signal, error) {
+	if r.cfg.Format == FormatProto {
+		signals := r.recv.signals()
+		if len(signals) == 0 {
+			return 0, errors.New("no consumer is configured")
+		}
+		return signals[0], nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return 0, fmt.Errorf("failed to decode message: %w", err)
+	}
+	for field := range fields {
+		switch field {
+		case "resourceMetrics", "resource_metrics":
+			return signalMetrics, nil
+		case "resourceLogs", "resource_logs":
+			return signalLogs, nil
+		case "resourceSpans", "resource_spans":
+			return signalTraces, nil
+		}
+	}
+	return 0, errors.New("message contains no telemetry")
+}
+
+func (r *replayer) metricsUnmarshaler() pmetric.Unmarshaler {
+	if r.cfg.Format == FormatProto {
+		return &pmetric.ProtoUnmarshaler{}
+	}
+	return &pmetric.JSONUnmarshaler{}
+}
+
+func (r *replayer) logsUnmarshaler() plog.Unmarshaler {
+	if r.cfg.Format == FormatProto {
+		return &plog.ProtoUnmarshaler{}
+	}
+	return &plog.JSONUnmarshaler{}
+}
+
+func (r *replayer) tracesUnmarshaler() ptrace.Unmarshaler {
+	if r.cfg.Format == FormatProto {
+		return &ptrace.ProtoUnmarshaler{}
+	}
+	return &ptrace.JSONUnmarshaler{}
+}
+
+// wait waits for the time elapsed between the latest replayed telemetry and
+// the telemetry at ts, scaled by the throttle. Telemetry without timestamp,
+// or older than the latest replayed telemetry, is replayed immediately.
+func (r *replayer) wait(ctx context.Context, ts pcommon.Timestamp) error {
+	if r.cfg.Throttle == 0 || ts <= r.prevTimestamp {
+		return nil
+	}
+
+	prev := r.prevTimestamp
+	r.prevTimestamp = ts
+	if prev == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(time.Duration(float64(ts-prev) * r.cfg.Throttle))
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,238 @@
+package filereplay
+
+import (
+	"cmp"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// batch is the telemetry of a message with the same timestamp.
+type batch[T any] struct {
+	timestamp pcommon.Timestamp
+	data      T
+}
+
+// sortBatches returns the batches sorted by timestamp. Telemetry without
+// timestamp comes first.
+func sortBatches[T any](batches map[pcommon.Timestamp]T) []batch[T] {
+	res := make([]batch[T], 0, len(batches))
+	for ts, data := range batches {
+		res = append(res, batch[T]{timestamp: ts, data: data})
+	}
+	slices.SortFunc(res, func(a, b batch[T]) int {
+		return cmp.Compare(a.timestamp, b.timestamp)
+	})
+	return res
+}
+
+// splitMetrics splits md into batches of the data points with the same
+// timestamp, sorted by timestamp. The resource, scope and metric of each data
+// point are kept.
+func splitMetrics(md pmetric.Metrics) []batch[pmetric.Metrics] {
+	var (
+		batches   = make(map[pcommon.Timestamp]pmetric.Metrics)
+		resources = make(map[pcommon.Timestamp]map[int]pmetric.ResourceMetrics)
+		scopes    = make(map[pcommon.Timestamp]map[[2]int]pmetric.ScopeMetrics)
+		metrics   = make(map[pcommon.Timestamp]map[[3]int]pmetric.Metric)
+	)
+
+	// metric returns the copy of the metric at i, j, k in the batch of ts,
+	// creating it with the resource and scope of the metric if needed.
+	metric := func(ts pcommon.Timestamp, i, j, k int) pmetric.Metric {
+		if m, ok := metrics[ts][[3]int{i, j, k}]; ok {
+			return m
+		}
+		if _, ok := batches[ts]; !ok {
+			batches[ts] = pmetric.NewMetrics()
+			resources[ts] = make(map[int]pmetric.ResourceMetrics)
+			scopes[ts] = make(map[[2]int]pmetric.ScopeMetrics)
+			metrics[ts] = make(map[[3]int]pmetric.Metric)
+		}
+
+		srcRM := md.ResourceMetrics().At(i)
+		rm, ok := resources[ts][i]
+		if !ok {
+			rm = batches[ts].ResourceMetrics().AppendEmpty()
+			srcRM.Resource().CopyTo(rm.Resource())
+			rm.SetSchemaUrl(srcRM.SchemaUrl())
+			resources[ts][i] = rm
+		}
+
+		srcSM := srcRM.ScopeMetrics().At(j)
+		sm, ok := scopes[ts][[2]int{i, j}]
+		if !ok {
+			sm = rm.ScopeMetrics().AppendEmpty()
+			srcSM.Scope().CopyTo(sm.Scope())
+			sm.SetSchemaUrl(srcSM.SchemaUrl())
+			scopes[ts][[2]int{i, j}] = sm
+		}
+
+		m := sm.Metrics().AppendEmpty()
+		copyMetricWithoutDataPoints(srcSM.Metrics().At(k), m)
+		metrics[ts][[3]int{i, j, k}] = m
+		return m
+	}
+
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		sms := md.ResourceMetrics().At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			ms := sms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				switch m := ms.At(k); m.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := m.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(metric(dp.Timestamp(), i, j, k).Gauge().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSum:
+					dps := m.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(metric(dp.Timestamp(), i, j, k).Sum().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := m.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(metric(dp.Timestamp(), i, j, k).Histogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := m.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(metric(dp.Timestamp(), i, j, k).ExponentialHistogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSummary:
+					dps := m.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(metric(dp.Timestamp(), i, j, k).Summary().DataPoints().AppendEmpty())
+					}
+				}
+			}
+		}
+	}
+	return sortBatches(batches)
+}
+
+// copyMetricWithoutDataPoints copies everything but the data points of src to
+// dst.
+func copyMetricWithoutDataPoints(src, dst pmetric.Metric) {
+	dst.SetName(src.Name())
+	dst.SetDescription(src.Description())
+	dst.SetUnit(src.Unit())
+	src.Metadata().CopyTo(dst.Metadata())
+
+	switch src.Type() {
+	case pmetric.MetricTypeGauge:
+		dst.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := dst.SetEmptySum()
+		sum.SetAggregationTemporality(src.Sum().AggregationTemporality())
+		sum.SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricTypeHistogram:
+		dst.SetEmptyHistogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		dst.SetEmptyExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricTypeSummary:
+		dst.SetEmptySummary()
+	}
+}
+
+// splitLogs splits ld into batches of the log records with the same
+// timestamp, sorted by timestamp. Log records without timestamp use their
+// observed timestamp. The resource and scope of each log record are kept.
+func splitLogs(ld plog.Logs) []batch[plog.Logs] {
+	var (
+		batches   = make(map[pcommon.Timestamp]plog.Logs)
+		resources = make(map[pcommon.Timestamp]map[int]plog.ResourceLogs)
+		scopes    = make(map[pcommon.Timestamp]map[[2]int]plog.LogRecordSlice)
+	)
+
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		srcRL := ld.ResourceLogs().At(i)
+		for j := 0; j < srcRL.ScopeLogs().Len(); j++ {
+			srcSL := srcRL.ScopeLogs().At(j)
+			for k := 0; k < srcSL.LogRecords().Len(); k++ {
+				lr := srcSL.LogRecords().At(k)
+				ts := lr.Timestamp()
+				if ts == 0 {
+					ts = lr.ObservedTimestamp()
+				}
+
+				if _, ok := batches[ts]; !ok {
+					batches[ts] = plog.NewLogs()
+					resources[ts] = make(map[int]plog.ResourceLogs)
+					scopes[ts] = make(map[[2]int]plog.LogRecordSlice)
+				}
+				rl, ok := resources[ts][i]
+				if !ok {
+					rl = batches[ts].ResourceLogs().AppendEmpty()
+					srcRL.Resource().CopyTo(rl.Resource())
+					rl.SetSchemaUrl(srcRL.SchemaUrl())
+					resources[ts][i] = rl
+				}
+				lrs, ok := scopes[ts][[2]int{i, j}]
+				if !ok {
+					sl := rl.ScopeLogs().AppendEmpty()
+					srcSL.Scope().CopyTo(sl.Scope())
+					sl.SetSchemaUrl(srcSL.SchemaUrl())
+					lrs = sl.LogRecords()
+					scopes[ts][[2]int{i, j}] = lrs
+				}
+				lr.CopyTo(lrs.AppendEmpty())
+			}
+		}
+	}
+	return sortBatches(batches)
+}
+
+// splitTraces splits td into batches of the spans with the same start
+// timestamp, sorted by timestamp. The resource and scope of each span are
+// kept.
+func splitTraces(td ptrace.Traces) []batch[ptrace.Traces] {
+	var (
+		batches   = make(map[pcommon.Timestamp]ptrace.Traces)
+		resources = make(map[pcommon.Timestamp]map[int]ptrace.ResourceSpans)
+		scopes    = make(map[pcommon.Timestamp]map[[2]int]ptrace.SpanSlice)
+	)
+
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		srcRS := td.ResourceSpans().At(i)
+		for j := 0; j < srcRS.ScopeSpans().Len(); j++ {
+			srcSS := srcRS.ScopeSpans().At(j)
+			for k := 0; k < srcSS.Spans().Len(); k++ {
+				span := srcSS.Spans().At(k)
+				ts := span.StartTimestamp()
+
+				if _, ok := batches[ts]; !ok {
+					batches[ts] = ptrace.NewTraces()
+					resources[ts] = make(map[int]ptrace.ResourceSpans)
+					scopes[ts] = make(map[[2]int]ptrace.SpanSlice)
+				}
+				rs, ok := resources[ts][i]
+				if !ok {
+					rs = batches[ts].ResourceSpans().AppendEmpty()
+					srcRS.Resource().CopyTo(rs.Resource())
+					rs.SetSchemaUrl(srcRS.SchemaUrl())
+					resources[ts][i] = rs
+				}
+				spans, ok := scopes[ts][[2]int{i, j}]
+				if !ok {
+					ss := rs.ScopeSpans().AppendEmpty()
+					srcSS.Scope().CopyTo(ss.Scope())
+					ss.SetSchemaUrl(srcSS.SchemaUrl())
+					spans = ss.Spans()
+					scopes[ts][[2]int{i, j}] = spans
+				}
+				span.CopyTo(spans.AppendEmpty())
+			}
+		}
+	}
+	return sortBatches(batches)
+}
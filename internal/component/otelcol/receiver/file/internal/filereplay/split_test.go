@@ -0,0 +1,86 @@
+package filereplay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestSplitLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "api")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope")
+	for _, r := range []struct {
+		body           string
+		ts, observedTs pcommon.Timestamp
+	}{
+		{body: "third", ts: 30},
+		{body: "first", ts: 10},
+		{body: "observed", observedTs: 20},
+		{body: "also first", ts: 10},
+	} {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Body().SetStr(r.body)
+		lr.SetTimestamp(r.ts)
+		lr.SetObservedTimestamp(r.observedTs)
+	}
+
+	var (
+		timestamps []pcommon.Timestamp
+		bodies     [][]string
+	)
+	for _, b := range splitLogs(ld) {
+		timestamps = append(timestamps, b.timestamp)
+
+		require.Equal(t, 1, b.data.ResourceLogs().Len())
+		rl := b.data.ResourceLogs().At(0)
+		require.Equal(t, map[string]any{"service.name": "api"}, rl.Resource().Attributes().AsRaw())
+		require.Equal(t, 1, rl.ScopeLogs().Len())
+		require.Equal(t, "scope", rl.ScopeLogs().At(0).Scope().Name())
+
+		var batchBodies []string
+		lrs := rl.ScopeLogs().At(0).LogRecords()
+		for i := 0; i < lrs.Len(); i++ {
+			batchBodies = append(batchBodies, lrs.At(i).Body().Str())
+		}
+		bodies = append(bodies, batchBodies)
+	}
+
+	require.Equal(t, []pcommon.Timestamp{10, 20, 30}, timestamps)
+	require.Equal(t, [][]string{{"first", "also first"}, {"observed"}, {"third"}}, bodies)
+}
+
+func TestSplitMetrics(t *testing.T) {
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("requests_total")
+	m.SetUnit("1")
+	sum := m.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	sum.SetIsMonotonic(true)
+	for _, ts := range []pcommon.Timestamp{20, 10} {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetTimestamp(ts)
+		dp.SetIntValue(int64(ts))
+	}
+
+	batches := splitMetrics(md)
+	require.Len(t, batches, 2)
+	for i, ts := range []pcommon.Timestamp{10, 20} {
+		require.Equal(t, ts, batches[i].timestamp)
+
+		require.Equal(t, 1, batches[i].data.MetricCount())
+		m := batches[i].data.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, "requests_total", m.Name())
+		require.Equal(t, "1", m.Unit())
+		require.Equal(t, pmetric.AggregationTemporalityCumulative, m.Sum().AggregationTemporality())
+		require.True(t, m.Sum().IsMonotonic())
+		require.Equal(t, 1, m.Sum().DataPoints().Len())
+		require.Equal(t, int64(ts), m.Sum().DataPoints().At(0).IntValue())
+	}
+}
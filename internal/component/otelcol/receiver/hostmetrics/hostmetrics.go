@@ -0,0 +1,315 @@
+// Package hostmetrics provides an otelcol.receiver.hostmetrics component.
+package hostmetrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.hostmetrics",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := hostmetricsreceiver.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.receiver.hostmetrics component.
+type Arguments struct {
+	Controller otelcol.ControllerArguments `alloy:",squash"`
+
+	// RootPath is the root directory of the host, when Alloy runs in a
+	// container with the host filesystem mounted.
+	RootPath string `alloy:"root_path,attr,optional"`
+
+	CPU        *CPUScraperArguments        `alloy:"cpu,block,optional"`
+	Disk       *DiskScraperArguments       `alloy:"disk,block,optional"`
+	Filesystem *FilesystemScraperArguments `alloy:"filesystem,block,optional"`
+	Load       *LoadScraperArguments       `alloy:"load,block,optional"`
+	Memory     *MemoryScraperArguments     `alloy:"memory,block,optional"`
+	Network    *NetworkScraperArguments    `alloy:"network,block,optional"`
+	Process    *ProcessScraperArguments    `alloy:"process,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+var (
+	_ receiver.Arguments = Arguments{}
+	_ syntax.Defaulter   = (*Arguments)(nil)
+	_ syntax.Validator   = (*Arguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{}
+	args.Controller.SetToDefault()
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if len(args.scrapers()) == 0 {
+		return fmt.Errorf("at least one scraper must be configured")
+	}
+	return nil
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	// The configurations of the scrapers are in internal packages, so the
+	// upstream configuration is unmarshaled from a map like it would be from
+	// YAML.
+	cfg := hostmetricsreceiver.NewFactory().CreateDefaultConfig().(*hostmetricsreceiver.Config)
+
+	controller := args.Controller.Convert()
+	in := map[string]any{
+		"collection_interval": controller.CollectionInterval,
+		"initial_delay":       controller.InitialDelay,
+		"timeout":             controller.Timeout,
+		"root_path":           args.RootPath,
+		"scrapers":            args.scrapers(),
+	}
+	if err := cfg.Unmarshal(confmap.NewFromStringMap(in)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// scrapers returns the configuration of the enabled scrapers, keyed by the
+// name of the upstream scraper.
+func (args *Arguments) scrapers() map[string]any {
+	res := make(map[string]any)
+	if args.CPU != nil {
+		res["cpu"] = map[string]any{}
+	}
+	if args.Disk != nil {
+		res["disk"] = args.Disk.toMap()
+	}
+	if args.Filesystem != nil {
+		res["filesystem"] = args.Filesystem.toMap()
+	}
+	if args.Load != nil {
+		res["load"] = args.Load.toMap()
+	}
+	if args.Memory != nil {
+		res["memory"] = map[string]any{}
+	}
+	if args.Network != nil {
+		res["network"] = args.Network.toMap()
+	}
+	if args.Process != nil {
+		res["process"] = args.Process.toMap()
+	}
+	return res
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
+
+// CPUScraperArguments configures the cpu scraper.
+type CPUScraperArguments struct{}
+
+// MemoryScraperArguments configures the memory scraper.
+type MemoryScraperArguments struct{}
+
+// LoadScraperArguments configures the load scraper.
+type LoadScraperArguments struct {
+	CPUAverage bool `alloy:"cpu_average,attr,optional"`
+}
+
+func (args *LoadScraperArguments) toMap() map[string]any {
+	return map[string]any{
+		"cpu_average": args.CPUAverage,
+	}
+}
+
+// DiskScraperArguments configures the disk scraper.
+type DiskScraperArguments struct {
+	Include *DevicesMatchArguments `alloy:"include,block,optional"`
+	Exclude *DevicesMatchArguments `alloy:"exclude,block,optional"`
+}
+
+func (args *DiskScraperArguments) toMap() map[string]any {
+	res := make(map[string]any)
+	if args.Include != nil {
+		res["include"] = args.Include.toMap()
+	}
+	if args.Exclude != nil {
+		res["exclude"] = args.Exclude.toMap()
+	}
+	return res
+}
+
+// FilesystemScraperArguments configures the filesystem scraper.
+type FilesystemScraperArguments struct {
+	IncludeVirtualFilesystems bool `alloy:"include_virtual_filesystems,attr,optional"`
+
+	IncludeDevices     *DevicesMatchArguments     `alloy:"include_devices,block,optional"`
+	ExcludeDevices     *DevicesMatchArguments     `alloy:"exclude_devices,block,optional"`
+	IncludeFSTypes     *FSTypesMatchArguments     `alloy:"include_fs_types,block,optional"`
+	ExcludeFSTypes     *FSTypesMatchArguments     `alloy:"exclude_fs_types,block,optional"`
+	IncludeMountPoints *MountPointsMatchArguments `alloy:"include_mount_points,block,optional"`
+	ExcludeMountPoints *MountPointsMatchArguments `alloy:"exclude_mount_points,block,optional"`
+}
+
+func (args *FilesystemScraperArguments) toMap() map[string]any {
+	res := map[string]any{
+		"include_virtual_filesystems": args.IncludeVirtualFilesystems,
+	}
+	if args.IncludeDevices != nil {
+		res["include_devices"] = args.IncludeDevices.toMap()
+	}
+	if args.ExcludeDevices != nil {
+		res["exclude_devices"] = args.ExcludeDevices.toMap()
+	}
+	if args.IncludeFSTypes != nil {
+		res["include_fs_types"] = args.IncludeFSTypes.toMap()
+	}
+	if args.ExcludeFSTypes != nil {
+		res["exclude_fs_types"] = args.ExcludeFSTypes.toMap()
+	}
+	if args.IncludeMountPoints != nil {
+		res["include_mount_points"] = args.IncludeMountPoints.toMap()
+	}
+	if args.ExcludeMountPoints != nil {
+		res["exclude_mount_points"] = args.ExcludeMountPoints.toMap()
+	}
+	return res
+}
+
+// NetworkScraperArguments configures the network scraper.
+type NetworkScraperArguments struct {
+	Include *InterfacesMatchArguments `alloy:"include,block,optional"`
+	Exclude *InterfacesMatchArguments `alloy:"exclude,block,optional"`
+}
+
+func (args *NetworkScraperArguments) toMap() map[string]any {
+	res := make(map[string]any)
+	if args.Include != nil {
+		res["include"] = args.Include.toMap()
+	}
+	if args.Exclude != nil {
+		res["exclude"] = args.Exclude.toMap()
+	}
+	return res
+}
+
+// ProcessScraperArguments configures the process scraper.
+type ProcessScraperArguments struct {
+	Include *NamesMatchArguments `alloy:"include,block,optional"`
+	Exclude *NamesMatchArguments `alloy:"exclude,block,optional"`
+
+	MuteProcessNameError   bool          `alloy:"mute_process_name_error,attr,optional"`
+	MuteProcessExeError    bool          `alloy:"mute_process_exe_error,attr,optional"`
+	MuteProcessIOError     bool          `alloy:"mute_process_io_error,attr,optional"`
+	MuteProcessUserError   bool          `alloy:"mute_process_user_error,attr,optional"`
+	MuteProcessCgroupError bool          `alloy:"mute_process_cgroup_error,attr,optional"`
+	MuteProcessAllErrors   bool          `alloy:"mute_process_all_errors,attr,optional"`
+	ScrapeProcessDelay     time.Duration `alloy:"scrape_process_delay,attr,optional"`
+}
+
+func (args *ProcessScraperArguments) toMap() map[string]any {
+	res := map[string]any{
+		"mute_process_name_error":   args.MuteProcessNameError,
+		"mute_process_exe_error":    args.MuteProcessExeError,
+		"mute_process_io_error":     args.MuteProcessIOError,
+		"mute_process_user_error":   args.MuteProcessUserError,
+		"mute_process_cgroup_error": args.MuteProcessCgroupError,
+		"mute_process_all_errors":   args.MuteProcessAllErrors,
+		"scrape_process_delay":      args.ScrapeProcessDelay,
+	}
+	if args.Include != nil {
+		res["include"] = args.Include.toMap()
+	}
+	if args.Exclude != nil {
+		res["exclude"] = args.Exclude.toMap()
+	}
+	return res
+}
+
+// DevicesMatchArguments matches devices by name.
+type DevicesMatchArguments struct {
+	Devices   []string `alloy:"devices,attr"`
+	MatchType string   `alloy:"match_type,attr"`
+}
+
+func (args *DevicesMatchArguments) toMap() map[string]any {
+	return map[string]any{"devices": args.Devices, "match_type": args.MatchType}
+}
+
+// FSTypesMatchArguments matches filesystems by type.
+type FSTypesMatchArguments struct {
+	FSTypes   []string `alloy:"fs_types,attr"`
+	MatchType string   `alloy:"match_type,attr"`
+}
+
+func (args *FSTypesMatchArguments) toMap() map[string]any {
+	return map[string]any{"fs_types": args.FSTypes, "match_type": args.MatchType}
+}
+
+// MountPointsMatchArguments matches filesystems by mount point.
+type MountPointsMatchArguments struct {
+	MountPoints []string `alloy:"mount_points,attr"`
+	MatchType   string   `alloy:"match_type,attr"`
+}
+
+func (args *MountPointsMatchArguments) toMap() map[string]any {
+	return map[string]any{"mount_points": args.MountPoints, "match_type": args.MatchType}
+}
+
+// InterfacesMatchArguments matches network interfaces by name.
+type InterfacesMatchArguments struct {
+	Interfaces []string `alloy:"interfaces,attr"`
+	MatchType  string   `alloy:"match_type,attr"`
+}
+
+func (args *InterfacesMatchArguments) toMap() map[string]any {
+	return map[string]any{"interfaces": args.Interfaces, "match_type": args.MatchType}
+}
+
+// NamesMatchArguments matches processes by executable name.
+type NamesMatchArguments struct {
+	Names     []string `alloy:"names,attr"`
+	MatchType string   `alloy:"match_type,attr"`
+}
+
+func (args *NamesMatchArguments) toMap() map[string]any {
+	return map[string]any{"names": args.Names, "match_type": args.MatchType}
+}
@@ -0,0 +1,83 @@
+package hostmetrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/hostmetrics"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver"
+	"github.com/stretchr/testify/require"
+	otelcomponent "go.opentelemetry.io/collector/component"
+)
+
+func TestArguments(t *testing.T) {
+	in := `
+		collection_interval = "30s"
+		root_path           = "/hostfs"
+
+		cpu {}
+		memory {}
+
+		load {
+			cpu_average = true
+		}
+
+		disk {
+			exclude {
+				devices    = ["^loop\\d+$"]
+				match_type = "regexp"
+			}
+		}
+
+		filesystem {
+			exclude_fs_types {
+				fs_types   = ["tmpfs", "overlay"]
+				match_type = "strict"
+			}
+		}
+
+		network {
+			include {
+				interfaces = ["eth0"]
+				match_type = "strict"
+			}
+		}
+
+		process {
+			mute_process_name_error = true
+		}
+
+		output {
+			// no-op
+		}
+	`
+
+	var args hostmetrics.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(in), &args))
+
+	outAny, err := args.Convert()
+	require.NoError(t, err)
+	out := outAny.(*hostmetricsreceiver.Config)
+
+	// The configurations of the scrapers are in internal packages, so we only
+	// check which scrapers are configured.
+	require.Equal(t, 30*time.Second, out.CollectionInterval)
+	require.Equal(t, "/hostfs", out.RootPath)
+	require.Len(t, out.Scrapers, 7)
+	for _, name := range []string{"cpu", "disk", "filesystem", "load", "memory", "network", "process"} {
+		require.Contains(t, out.Scrapers, otelcomponent.MustNewType(name))
+	}
+}
+
+func TestArguments_NoScrapers(t *testing.T) {
+	in := `
+		output {
+			// no-op
+		}
+	`
+
+	var args hostmetrics.Arguments
+	err := syntax.Unmarshal([]byte(in), &args)
+	require.ErrorContains(t, err, "at least one scraper must be configured")
+}
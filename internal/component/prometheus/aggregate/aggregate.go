@@ -0,0 +1,328 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/storage"
+	"go.uber.org/atomic"
+)
+
+const name = "prometheus.aggregate"
+
+func init() {
+	component.Register(component.Registration{
+		Name:      name,
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the prometheus.aggregate
+// component.
+type Arguments struct {
+	// Where the aggregated metrics should be forwarded to.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// How often the aggregated metrics are written.
+	Interval time.Duration `alloy:"interval,attr,optional"`
+
+	// The labels to group by, or to remove from the series. At most one of
+	// them can be set.
+	By      []string `alloy:"by,attr,optional"`
+	Without []string `alloy:"without,attr,optional"`
+
+	// The operations to apply to each group.
+	Operations []string `alloy:"operations,attr,optional"`
+
+	// KeepMetricNames writes the aggregated series with the name of the input
+	// series instead of adding the operation as a suffix.
+	KeepMetricNames bool `alloy:"keep_metric_names,attr,optional"`
+
+	// How long an input series is kept in its group after its last sample.
+	StaleSeriesTimeout time.Duration `alloy:"stale_series_timeout,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (arg *Arguments) SetToDefault() {
+	*arg = Arguments{
+		Interval:           time.Minute,
+		Operations:         []string{OperationSum},
+		StaleSeriesTimeout: 5 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (arg *Arguments) Validate() error {
+	if arg.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if arg.StaleSeriesTimeout <= 0 {
+		return fmt.Errorf("stale_series_timeout must be greater than 0")
+	}
+	if len(arg.By) > 0 && len(arg.Without) > 0 {
+		return fmt.Errorf("at most one of by and without can be set")
+	}
+	if slices.Contains(arg.Without, labels.MetricName) {
+		return fmt.Errorf("without must not contain %q", labels.MetricName)
+	}
+
+	if len(arg.Operations) == 0 {
+		return fmt.Errorf("at least one operation must be set")
+	}
+	for i, op := range arg.Operations {
+		switch op {
+		case OperationSum, OperationCount, OperationMin, OperationMax, OperationAvg:
+		default:
+			return fmt.Errorf("unsupported operation %q, must be one of %q, %q, %q, %q or %q", op, OperationSum, OperationCount, OperationMin, OperationMax, OperationAvg)
+		}
+		if slices.Contains(arg.Operations[:i], op) {
+			return fmt.Errorf("operation %q is set more than once", op)
+		}
+	}
+	if arg.KeepMetricNames && len(arg.Operations) > 1 {
+		return fmt.Errorf("keep_metric_names can only be used with a single operation")
+	}
+	return nil
+}
+
+// aggregationChanged returns true if the aggregated series of arg differ from
+// the ones of other.
+func (arg *Arguments) aggregationChanged(other Arguments) bool {
+	return !slices.Equal(arg.By, other.By) ||
+		!slices.Equal(arg.Without, other.Without) ||
+		!slices.Equal(arg.Operations, other.Operations) ||
+		arg.KeepMetricNames != other.KeepMetricNames ||
+		arg.StaleSeriesTimeout != other.StaleSeriesTimeout
+}
+
+// Exports holds values which are exported by the prometheus.aggregate
+// component.
+type Exports struct {
+	Receiver storage.Appendable `alloy:"receiver,attr"`
+}
+
+// Component implements the prometheus.aggregate component.
+type Component struct {
+	opts     component.Options
+	receiver *prometheus.Interceptor
+	fanout   *prometheus.Fanout
+	exited   atomic.Bool
+	ls       labelstore.LabelStore
+
+	mut        sync.RWMutex
+	args       Arguments
+	aggregator *aggregator
+
+	samplesProcessed prometheus_client.Counter
+	samplesDropped   prometheus_client.Counter
+	samplesWritten   prometheus_client.Counter
+	inputSeries      prometheus_client.Gauge
+	groups           prometheus_client.Gauge
+
+	debugDataPublisher livedebugging.DebugDataPublisher
+}
+
+var (
+	_ component.Component     = (*Component)(nil)
+	_ component.LiveDebugging = (*Component)(nil)
+)
+
+// New creates a new prometheus.aggregate component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	debugDataPublisher, err := o.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := o.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	c := &Component{
+		opts:               o,
+		ls:                 data.(labelstore.LabelStore),
+		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
+	}
+	c.samplesProcessed = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "alloy_prometheus_aggregate_samples_processed",
+		Help: "Total number of samples processed",
+	})
+	c.samplesDropped = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "alloy_prometheus_aggregate_samples_dropped",
+		Help: "Total number of samples dropped because they could not be aggregated",
+	})
+	c.samplesWritten = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "alloy_prometheus_aggregate_samples_written",
+		Help: "Total number of aggregated samples written",
+	})
+	c.inputSeries = prometheus_client.NewGauge(prometheus_client.GaugeOpts{
+		Name: "alloy_prometheus_aggregate_input_series",
+		Help: "Number of input series tracked in aggregation groups",
+	})
+	c.groups = prometheus_client.NewGauge(prometheus_client.GaugeOpts{
+		Name: "alloy_prometheus_aggregate_groups",
+		Help: "Number of aggregation groups",
+	})
+
+	for _, metric := range []prometheus_client.Collector{c.samplesProcessed, c.samplesDropped, c.samplesWritten, c.inputSeries, c.groups} {
+		err = o.Registerer.Register(metric)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.fanout = prometheus.NewFanout(args.ForwardTo, o.ID, o.Registerer, c.ls)
+
+	// Samples are only recorded when received. The aggregated samples are
+	// written to the fanout by Run. Exemplars and created timestamps can't be
+	// aggregated and are dropped by the interceptor, which has no next
+	// appendable.
+	c.receiver = prometheus.NewInterceptor(
+		nil,
+		c.ls,
+		prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, v float64, _ storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			c.samplesProcessed.Inc()
+			if !c.getAggregator().appendFloat(uint64(ref), l, v, time.Now()) {
+				c.samplesDropped.Inc()
+			}
+			return ref, nil
+		}),
+		prometheus.WithHistogramHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, h *histogram.Histogram, fh *histogram.FloatHistogram, _ storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			c.samplesProcessed.Inc()
+			ok, err := c.getAggregator().appendHistogram(uint64(ref), l, h, fh, time.Now())
+			if err != nil {
+				level.Debug(o.Logger).Log("msg", "failed to aggregate native histogram", "labels", l, "err", err)
+			}
+			if !ok {
+				c.samplesDropped.Inc()
+			}
+			return ref, nil
+		}),
+		prometheus.WithMetadataHook(func(ref storage.SeriesRef, l labels.Labels, m metadata.Metadata, _ storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			c.getAggregator().setMetadata(l, m.Type)
+			return ref, nil
+		}),
+	)
+
+	// Immediately export the receiver which remains the same for the component
+	// lifetime.
+	o.OnStateChange(Exports{Receiver: c.receiver})
+
+	if err = c.Update(args); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer c.exited.Store(true)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.interval()):
+			c.flush(ctx, time.Now())
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	newArgs := args.(Arguments)
+	// The state is reset when the aggregated series change, as it can't be
+	// carried over to the new groups.
+	if c.aggregator == nil || c.args.aggregationChanged(newArgs) {
+		c.aggregator = newAggregator(newArgs)
+	}
+	c.args = newArgs
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+
+	return nil
+}
+
+func (c *Component) getAggregator() *aggregator {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.aggregator
+}
+
+func (c *Component) interval() time.Duration {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.args.Interval
+}
+
+// flush writes the aggregated samples to the fanout.
+func (c *Component) flush(ctx context.Context, now time.Time) {
+	agg := c.getAggregator()
+
+	app := c.fanout.Appender(ctx)
+	written, err := agg.flush(app, now)
+	if err != nil {
+		level.Error(c.opts.Logger).Log("msg", "failed to write aggregated samples", "err", err)
+		_ = app.Rollback()
+	} else if err := app.Commit(); err != nil {
+		level.Error(c.opts.Logger).Log("msg", "failed to commit aggregated samples", "err", err)
+	}
+
+	c.samplesWritten.Add(float64(len(written)))
+	series, groups := agg.stats()
+	c.inputSeries.Set(float64(series))
+	c.groups.Set(float64(groups))
+
+	componentID := livedebugging.ComponentID(c.opts.ID)
+	for _, s := range written {
+		c.debugDataPublisher.PublishIfActive(livedebugging.NewData(
+			componentID,
+			livedebugging.PrometheusMetric,
+			1,
+			func() string {
+				if s.histogram != nil {
+					return fmt.Sprintf("ts=%d, labels=%s, histogram=%s", now.UnixMilli(), s.labels, s.histogram.String())
+				}
+				return fmt.Sprintf("ts=%d, labels=%s, value=%f", now.UnixMilli(), s.labels, s.value)
+			},
+			livedebugging.WithPrometheusLabels(s.labels),
+		))
+	}
+}
+
+// LiveDebugging implements component.LiveDebugging.
+func (c *Component) LiveDebugging() {}
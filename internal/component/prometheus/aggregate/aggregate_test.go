@@ -0,0 +1,423 @@
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "defaults",
+			cfg:  `forward_to = []`,
+		},
+		{
+			name: "by",
+			cfg: `
+				by         = ["namespace", "service"]
+				operations = ["sum", "count", "min", "max", "avg"]
+				forward_to = []
+			`,
+		},
+		{
+			name: "by and without",
+			cfg: `
+				by         = ["namespace"]
+				without    = ["pod"]
+				forward_to = []
+			`,
+			expectedErr: "at most one of by and without can be set",
+		},
+		{
+			name: "without metric name",
+			cfg: `
+				without    = ["__name__"]
+				forward_to = []
+			`,
+			expectedErr: `without must not contain "__name__"`,
+		},
+		{
+			name: "unsupported operation",
+			cfg: `
+				operations = ["stddev"]
+				forward_to = []
+			`,
+			expectedErr: `unsupported operation "stddev"`,
+		},
+		{
+			name: "duplicate operation",
+			cfg: `
+				operations = ["sum", "sum"]
+				forward_to = []
+			`,
+			expectedErr: `operation "sum" is set more than once`,
+		},
+		{
+			name: "keep metric names with multiple operations",
+			cfg: `
+				operations        = ["sum", "max"]
+				keep_metric_names = true
+				forward_to        = []
+			`,
+			expectedErr: "keep_metric_names can only be used with a single operation",
+		},
+		{
+			name: "invalid interval",
+			cfg: `
+				interval   = "0s"
+				forward_to = []
+			`,
+			expectedErr: "interval must be greater than 0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestAggregator_Operations(t *testing.T) {
+	agg := newTestAggregator(func(args *Arguments) {
+		args.By = []string{"namespace"}
+		args.Operations = []string{OperationSum, OperationCount, OperationMin, OperationMax, OperationAvg}
+	})
+
+	now := time.Now()
+	agg.appendFloat(1, labels.FromStrings("__name__", "memory_bytes", "namespace", "a", "pod", "1"), 10, now)
+	agg.appendFloat(2, labels.FromStrings("__name__", "memory_bytes", "namespace", "a", "pod", "2"), 30, now)
+	agg.appendFloat(3, labels.FromStrings("__name__", "memory_bytes", "namespace", "b", "pod", "3"), 5, now)
+
+	app := newCollectingAppender()
+	_, err := agg.flush(app, now)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]float64{
+		`{__name__="memory_bytes:sum", namespace="a"}`:   40,
+		`{__name__="memory_bytes:count", namespace="a"}`: 2,
+		`{__name__="memory_bytes:min", namespace="a"}`:   10,
+		`{__name__="memory_bytes:max", namespace="a"}`:   30,
+		`{__name__="memory_bytes:avg", namespace="a"}`:   20,
+		`{__name__="memory_bytes:sum", namespace="b"}`:   5,
+		`{__name__="memory_bytes:count", namespace="b"}`: 1,
+		`{__name__="memory_bytes:min", namespace="b"}`:   5,
+		`{__name__="memory_bytes:max", namespace="b"}`:   5,
+		`{__name__="memory_bytes:avg", namespace="b"}`:   5,
+	}, app.floats)
+}
+
+func TestAggregator_Without(t *testing.T) {
+	agg := newTestAggregator(func(args *Arguments) {
+		args.Without = []string{"pod"}
+		args.KeepMetricNames = true
+	})
+
+	now := time.Now()
+	agg.appendFloat(1, labels.FromStrings("__name__", "memory_bytes", "namespace", "a", "pod", "1"), 10, now)
+	agg.appendFloat(2, labels.FromStrings("__name__", "memory_bytes", "namespace", "a", "pod", "2"), 30, now)
+
+	app := newCollectingAppender()
+	_, err := agg.flush(app, now)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]float64{
+		`{__name__="memory_bytes", namespace="a"}`: 40,
+	}, app.floats)
+}
+
+func TestAggregator_CounterResets(t *testing.T) {
+	agg := newTestAggregator(func(args *Arguments) {
+		args.By = []string{"namespace"}
+		args.KeepMetricNames = true
+	})
+
+	var (
+		now  = time.Now()
+		pod1 = labels.FromStrings("__name__", "requests_total", "namespace", "a", "pod", "1")
+		pod2 = labels.FromStrings("__name__", "requests_total", "namespace", "a", "pod", "2")
+		out  = `{__name__="requests_total", namespace="a"}`
+	)
+
+	flush := func() float64 {
+		app := newCollectingAppender()
+		_, err := agg.flush(app, now)
+		require.NoError(t, err)
+		return app.floats[out]
+	}
+
+	agg.appendFloat(1, pod1, 10, now)
+	agg.appendFloat(2, pod2, 20, now)
+	require.Equal(t, 30.0, flush())
+
+	// pod1 increases by 5, pod2 is reset and increases by 2.
+	agg.appendFloat(1, pod1, 15, now)
+	agg.appendFloat(2, pod2, 2, now)
+	require.Equal(t, 37.0, flush())
+
+	// pod2 goes away, which must not decrease the sum.
+	agg.appendFloat(2, pod2, math.Float64frombits(value.StaleNaN), now)
+	agg.appendFloat(1, pod1, 16, now)
+	require.Equal(t, 38.0, flush())
+}
+
+func TestAggregator_CounterResumedAfterStaleness(t *testing.T) {
+	agg := newTestAggregator(func(args *Arguments) {
+		args.By = []string{"namespace"}
+		args.KeepMetricNames = true
+		args.StaleSeriesTimeout = time.Minute
+	})
+
+	var (
+		now  = time.Now()
+		pod1 = labels.FromStrings("__name__", "requests_total", "namespace", "a", "pod", "1")
+		pod2 = labels.FromStrings("__name__", "requests_total", "namespace", "a", "pod", "2")
+		out  = `{__name__="requests_total", namespace="a"}`
+	)
+
+	flush := func(now time.Time) float64 {
+		app := newCollectingAppender()
+		_, err := agg.flush(app, now)
+		require.NoError(t, err)
+		return app.floats[out]
+	}
+
+	agg.appendFloat(1, pod1, 10, now)
+	agg.appendFloat(2, pod2, 100, now)
+	require.Equal(t, 110.0, flush(now))
+
+	// A failed scrape of pod2 writes a staleness marker, and the next scrape
+	// resumes the series. Only the increase since the resumed sample counts.
+	agg.appendFloat(2, pod2, math.Float64frombits(value.StaleNaN), now)
+	require.Equal(t, 110.0, flush(now))
+	agg.appendFloat(2, pod2, 105, now)
+	require.Equal(t, 110.0, flush(now))
+	agg.appendFloat(2, pod2, 107, now)
+	require.Equal(t, 112.0, flush(now))
+
+	// pod1 is evicted after the series timeout, and then resumes.
+	later := now.Add(2 * time.Minute)
+	agg.appendFloat(2, pod2, 108, later)
+	require.Equal(t, 113.0, flush(later))
+	agg.appendFloat(1, pod1, 20, later)
+	agg.appendFloat(1, pod1, 21, later)
+	require.Equal(t, 114.0, flush(later))
+}
+
+func TestAggregator_CounterFromMetadata(t *testing.T) {
+	agg := newTestAggregator(func(args *Arguments) {
+		args.By = []string{"namespace"}
+		args.KeepMetricNames = true
+	})
+
+	var (
+		now  = time.Now()
+		pod1 = labels.FromStrings("__name__", "requests", "namespace", "a", "pod", "1")
+		out  = `{__name__="requests", namespace="a"}`
+	)
+	agg.setMetadata(pod1, model.MetricTypeCounter)
+
+	agg.appendFloat(1, pod1, 10, now)
+	agg.appendFloat(1, pod1, 4, now)
+
+	app := newCollectingAppender()
+	_, err := agg.flush(app, now)
+	require.NoError(t, err)
+	require.Equal(t, 14.0, app.floats[out])
+}
+
+func TestAggregator_StaleGroups(t *testing.T) {
+	agg := newTestAggregator(func(args *Arguments) {
+		args.By = []string{"namespace"}
+		args.StaleSeriesTimeout = time.Minute
+	})
+
+	var (
+		now = time.Now()
+		out = `{__name__="memory_bytes:sum", namespace="a"}`
+	)
+	agg.appendFloat(1, labels.FromStrings("__name__", "memory_bytes", "namespace", "a", "pod", "1"), 10, now)
+
+	app := newCollectingAppender()
+	_, err := agg.flush(app, now)
+	require.NoError(t, err)
+	require.Equal(t, 10.0, app.floats[out])
+
+	// The input series times out, so the group is marked as stale and removed.
+	app = newCollectingAppender()
+	_, err = agg.flush(app, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	require.True(t, value.IsStaleNaN(app.floats[out]))
+
+	series, groups := agg.stats()
+	require.Zero(t, series)
+	require.Zero(t, groups)
+}
+
+func TestAggregator_NativeHistograms(t *testing.T) {
+	agg := newTestAggregator(func(args *Arguments) {
+		args.By = []string{"namespace"}
+		args.KeepMetricNames = true
+	})
+
+	var (
+		now  = time.Now()
+		pod1 = labels.FromStrings("__name__", "latency_seconds", "namespace", "a", "pod", "1")
+		pod2 = labels.FromStrings("__name__", "latency_seconds", "namespace", "a", "pod", "2")
+		out  = `{__name__="latency_seconds", namespace="a"}`
+	)
+
+	ok, err := agg.appendHistogram(1, pod1, testHistogram(10, 5), nil, now)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = agg.appendHistogram(2, pod2, testHistogram(4, 2), nil, now)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	app := newCollectingAppender()
+	_, err = agg.flush(app, now)
+	require.NoError(t, err)
+	require.Equal(t, 14.0, app.histograms[out].Count)
+	require.Equal(t, 7.0, app.histograms[out].Sum)
+
+	// pod2 is reset, so its whole count is an increase.
+	_, err = agg.appendHistogram(1, pod1, testHistogram(12, 6), nil, now)
+	require.NoError(t, err)
+	_, err = agg.appendHistogram(2, pod2, testHistogram(1, 1), nil, now)
+	require.NoError(t, err)
+
+	app = newCollectingAppender()
+	_, err = agg.flush(app, now)
+	require.NoError(t, err)
+	require.Equal(t, 17.0, app.histograms[out].Count)
+	require.Equal(t, 9.0, app.histograms[out].Sum)
+
+	// Float samples can't be added to a native histogram group.
+	require.False(t, agg.appendFloat(3, pod1, 1, now))
+}
+
+func TestComponent(t *testing.T) {
+	ls := labelstore.New(nil, prom.DefaultRegisterer)
+	app := newCollectingAppender()
+	output := prometheus.NewInterceptor(nil, ls,
+		prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, t int64, v float64, _ storage.Appender) (storage.SeriesRef, error) {
+			return app.Append(ref, l, t, v)
+		}),
+	)
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		by         = ["namespace"]
+		forward_to = []
+	`), &args))
+	args.ForwardTo = []storage.Appendable{output}
+
+	var exports Exports
+	c, err := New(component.Options{
+		ID:             "prometheus.aggregate.test",
+		Logger:         util.TestAlloyLogger(t),
+		OnStateChange:  func(e component.Exports) { exports = e.(Exports) },
+		Registerer:     prom.NewRegistry(),
+		GetServiceData: getServiceData,
+	}, args)
+	require.NoError(t, err)
+
+	in := exports.Receiver.Appender(t.Context())
+	_, err = in.Append(0, labels.FromStrings("__name__", "memory_bytes", "namespace", "a", "pod", "1"), 0, 10)
+	require.NoError(t, err)
+	_, err = in.Append(0, labels.FromStrings("__name__", "memory_bytes", "namespace", "a", "pod", "2"), 0, 5)
+	require.NoError(t, err)
+	require.NoError(t, in.Commit())
+
+	c.flush(t.Context(), time.Now())
+	require.Equal(t, map[string]float64{
+		`{__name__="memory_bytes:sum", namespace="a"}`: 15,
+	}, app.floats)
+
+	// Changing the grouping resets the aggregation state.
+	args.By = []string{"pod"}
+	require.NoError(t, c.Update(args))
+	series, groups := c.getAggregator().stats()
+	require.Zero(t, series)
+	require.Zero(t, groups)
+}
+
+func newTestAggregator(f func(args *Arguments)) *aggregator {
+	var args Arguments
+	args.SetToDefault()
+	f(&args)
+	return newAggregator(args)
+}
+
+func testHistogram(count, sum float64) *histogram.Histogram {
+	return &histogram.Histogram{
+		Count:           uint64(count),
+		Sum:             sum,
+		Schema:          0,
+		ZeroThreshold:   0.001,
+		PositiveSpans:   []histogram.Span{{Offset: 0, Length: 1}},
+		PositiveBuckets: []int64{int64(count)},
+	}
+}
+
+// collectingAppender records the latest value written for each series.
+type collectingAppender struct {
+	storage.Appender
+
+	floats     map[string]float64
+	histograms map[string]*histogram.FloatHistogram
+}
+
+func newCollectingAppender() *collectingAppender {
+	return &collectingAppender{
+		floats:     make(map[string]float64),
+		histograms: make(map[string]*histogram.FloatHistogram),
+	}
+}
+
+func (c *collectingAppender) Append(ref storage.SeriesRef, l labels.Labels, _ int64, v float64) (storage.SeriesRef, error) {
+	c.floats[l.String()] = v
+	return ref, nil
+}
+
+func (c *collectingAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, _ int64, _ *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	c.histograms[l.String()] = fh
+	return ref, nil
+}
+
+func getServiceData(name string) (interface{}, error) {
+	switch name {
+	case labelstore.ServiceName:
+		return labelstore.New(nil, prom.DefaultRegisterer), nil
+	case livedebugging.ServiceName:
+		return livedebugging.NewLiveDebugging(), nil
+	default:
+		return nil, fmt.Errorf("service not found %s", name)
+	}
+}
@@ -0,0 +1,402 @@
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// Supported aggregation operations.
+const (
+	OperationSum   = "sum"
+	OperationCount = "count"
+	OperationMin   = "min"
+	OperationMax   = "max"
+	OperationAvg   = "avg"
+)
+
+// aggregator keeps the state of every aggregated group between flushes.
+type aggregator struct {
+	by              []string
+	without         []string
+	operations      []string
+	keepMetricNames bool
+	seriesTimeout   time.Duration
+
+	mut sync.Mutex
+	// metricTypes holds the type of each metric family, as reported through
+	// metadata. It's used to detect counters.
+	metricTypes map[string]model.MetricType
+	groups      map[uint64]*group
+}
+
+// group is the state of a single output series set.
+type group struct {
+	labels    labels.Labels
+	histogram bool
+	counter   bool
+
+	// series holds the latest value of every input series in the group, keyed
+	// by their global ref ID.
+	series map[uint64]*inputSeries
+
+	// counterTotal and histogramTotal are the sums of the increases of all the
+	// input series, which don't decrease when an input series is reset or
+	// removed.
+	counterTotal   float64
+	histogramTotal *histogram.FloatHistogram
+
+	// emitted is true if samples have been written for this group, and must be
+	// marked as stale once the group is removed.
+	emitted bool
+}
+
+type inputSeries struct {
+	value     float64
+	histogram *histogram.FloatHistogram
+	lastSeen  time.Time
+}
+
+func newAggregator(args Arguments) *aggregator {
+	return &aggregator{
+		by:              args.By,
+		without:         args.Without,
+		operations:      args.Operations,
+		keepMetricNames: args.KeepMetricNames,
+		seriesTimeout:   args.StaleSeriesTimeout,
+
+		metricTypes: make(map[string]model.MetricType),
+		groups:      make(map[uint64]*group),
+	}
+}
+
+// groupLabels returns the labels of the group an input series belongs to.
+// The metric name is always kept so that different metrics are never
+// aggregated together.
+func (a *aggregator) groupLabels(l labels.Labels) labels.Labels {
+	if len(a.by) > 0 {
+		b := labels.NewBuilder(labels.EmptyLabels())
+		b.Set(labels.MetricName, l.Get(labels.MetricName))
+		for _, name := range a.by {
+			b.Set(name, l.Get(name))
+		}
+		return b.Labels()
+	}
+
+	b := labels.NewBuilder(l)
+	b.Del(a.without...)
+	return b.Labels()
+}
+
+// getGroup returns the group for the input series l, creating it if needed.
+// It returns nil if the group already exists with a different sample type.
+func (a *aggregator) getGroup(l labels.Labels, isHistogram bool, isCounter func() bool) *group {
+	gl := a.groupLabels(l)
+	hash := gl.Hash()
+
+	g, ok := a.groups[hash]
+	if !ok {
+		g = &group{
+			labels:    gl,
+			histogram: isHistogram,
+			counter:   isCounter(),
+			series:    make(map[uint64]*inputSeries),
+		}
+		a.groups[hash] = g
+	}
+	if g.histogram != isHistogram {
+		return nil
+	}
+	return g
+}
+
+// setMetadata records the type of the metric family of l.
+func (a *aggregator) setMetadata(l labels.Labels, t model.MetricType) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	a.metricTypes[l.Get(labels.MetricName)] = t
+}
+
+// isCounter returns true if the samples of the metric name are cumulative.
+// Metadata is used when available, falling back to the naming conventions.
+func (a *aggregator) isCounter(name string) bool {
+	cumulativeSuffix := strings.HasSuffix(name, "_bucket") ||
+		strings.HasSuffix(name, "_count") ||
+		strings.HasSuffix(name, "_sum")
+
+	switch a.metricTypes[name] {
+	case model.MetricTypeCounter:
+		return true
+	case model.MetricTypeHistogram, model.MetricTypeSummary:
+		return cumulativeSuffix
+	case model.MetricTypeUnknown, "":
+		return strings.HasSuffix(name, "_total") || cumulativeSuffix
+	default:
+		return false
+	}
+}
+
+// appendFloat records a float sample of the input series ref. It returns
+// false if the sample was dropped.
+func (a *aggregator) appendFloat(ref uint64, l labels.Labels, v float64, now time.Time) bool {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	g := a.getGroup(l, false, func() bool { return a.isCounter(l.Get(labels.MetricName)) })
+	if g == nil {
+		return false
+	}
+
+	if value.IsStaleNaN(v) {
+		delete(g.series, ref)
+		return true
+	}
+
+	s, ok := g.series[ref]
+	if !ok {
+		s = &inputSeries{}
+		g.series[ref] = s
+	}
+	if g.counter {
+		switch {
+		case !ok && g.emitted:
+			// The series joined a group which was already written, possibly
+			// after being removed because it was stale. Its first sample is
+			// only used as a baseline, otherwise its whole cumulative value
+			// would be added again.
+		case ok && v >= s.value:
+			g.counterTotal += v - s.value
+		default:
+			// A decrease means the counter was reset, in which case the whole
+			// value is an increase.
+			g.counterTotal += v
+		}
+	}
+	s.value = v
+	s.lastSeen = now
+	return true
+}
+
+// appendHistogram records a native histogram sample of the input series ref.
+// It returns false if the sample was dropped.
+func (a *aggregator) appendHistogram(ref uint64, l labels.Labels, h *histogram.Histogram, fh *histogram.FloatHistogram, now time.Time) (bool, error) {
+	switch {
+	case h != nil:
+		fh = h.ToFloat(nil)
+	case fh != nil:
+		fh = fh.Copy()
+	default:
+		return false, nil
+	}
+
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	g := a.getGroup(l, true, func() bool { return fh.CounterResetHint != histogram.GaugeType })
+	if g == nil {
+		return false, nil
+	}
+
+	if value.IsStaleNaN(fh.Sum) {
+		delete(g.series, ref)
+		return true, nil
+	}
+
+	s, ok := g.series[ref]
+	if g.counter && (ok || !g.emitted) {
+		// As with float counters, the first sample of a series joining a
+		// group which was already written is only used as a baseline.
+		increase := fh
+		if ok && !fh.DetectReset(s.histogram) {
+			var err error
+			increase, err = fh.Copy().Sub(s.histogram)
+			if err != nil {
+				return false, err
+			}
+		}
+		if g.histogramTotal == nil {
+			g.histogramTotal = increase.Copy()
+		} else if _, err := g.histogramTotal.Add(increase); err != nil {
+			return false, err
+		}
+	}
+
+	if !ok {
+		s = &inputSeries{}
+		g.series[ref] = s
+	}
+	s.histogram = fh
+	s.lastSeen = now
+	return true, nil
+}
+
+// outputLabels returns the labels of the series written for an operation of
+// the group.
+func (a *aggregator) outputLabels(g *group, operation string) labels.Labels {
+	if a.keepMetricNames {
+		return g.labels
+	}
+	b := labels.NewBuilder(g.labels)
+	b.Set(labels.MetricName, g.labels.Get(labels.MetricName)+":"+operation)
+	return b.Labels()
+}
+
+// outputSample is a sample written by flush.
+type outputSample struct {
+	labels    labels.Labels
+	value     float64
+	histogram *histogram.FloatHistogram
+}
+
+// flush writes the aggregated samples of every group to app with the
+// timestamp now. Input series which have not been updated within the series
+// timeout are removed, and groups without input series are marked as stale.
+func (a *aggregator) flush(app storage.Appender, now time.Time) ([]outputSample, error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	var (
+		ts      = now.UnixMilli()
+		written []outputSample
+	)
+	for hash, g := range a.groups {
+		for ref, s := range g.series {
+			if now.Sub(s.lastSeen) > a.seriesTimeout {
+				delete(g.series, ref)
+			}
+		}
+
+		samples := a.groupSamples(g)
+		if len(g.series) == 0 {
+			delete(a.groups, hash)
+			if !g.emitted {
+				continue
+			}
+			// Mark the previously written series as stale.
+			for i := range samples {
+				samples[i].value = math.Float64frombits(value.StaleNaN)
+				if samples[i].histogram != nil {
+					samples[i].histogram = &histogram.FloatHistogram{Sum: math.Float64frombits(value.StaleNaN)}
+				}
+			}
+		}
+
+		for _, s := range samples {
+			var err error
+			if s.histogram != nil {
+				_, err = app.AppendHistogram(0, s.labels, ts, nil, s.histogram)
+			} else {
+				_, err = app.Append(0, s.labels, ts, s.value)
+			}
+			if err != nil {
+				return written, fmt.Errorf("failed to write aggregated series %s: %w", s.labels, err)
+			}
+			written = append(written, s)
+		}
+		g.emitted = true
+	}
+	return written, nil
+}
+
+// groupSamples returns the samples of every operation of the group.
+func (a *aggregator) groupSamples(g *group) []outputSample {
+	res := make([]outputSample, 0, len(a.operations))
+	for _, op := range a.operations {
+		switch {
+		case op == OperationCount:
+			res = append(res, outputSample{labels: a.outputLabels(g, op), value: float64(len(g.series))})
+		case g.histogram && op == OperationSum:
+			res = append(res, outputSample{labels: a.outputLabels(g, op), histogram: a.histogramSum(g)})
+		case g.histogram:
+			// Only sum and count are supported for native histograms.
+			continue
+		default:
+			res = append(res, outputSample{labels: a.outputLabels(g, op), value: a.floatValue(g, op)})
+		}
+	}
+	return res
+}
+
+// floatValue returns the result of an operation over the float samples of the
+// group.
+func (a *aggregator) floatValue(g *group, op string) float64 {
+	if op == OperationSum && g.counter {
+		return g.counterTotal
+	}
+
+	var sum, minV, maxV float64
+	first := true
+	for _, s := range g.series {
+		sum += s.value
+		if first || s.value < minV {
+			minV = s.value
+		}
+		if first || s.value > maxV {
+			maxV = s.value
+		}
+		first = false
+	}
+
+	switch op {
+	case OperationSum:
+		return sum
+	case OperationMin:
+		return minV
+	case OperationMax:
+		return maxV
+	case OperationAvg:
+		if len(g.series) == 0 {
+			return 0
+		}
+		return sum / float64(len(g.series))
+	default:
+		return math.NaN()
+	}
+}
+
+// histogramSum returns the merged native histogram of the group.
+func (a *aggregator) histogramSum(g *group) *histogram.FloatHistogram {
+	if g.counter {
+		if g.histogramTotal == nil {
+			return &histogram.FloatHistogram{}
+		}
+		res := g.histogramTotal.Copy()
+		res.CounterResetHint = histogram.UnknownCounterReset
+		return res
+	}
+
+	var res *histogram.FloatHistogram
+	for _, s := range g.series {
+		if res == nil {
+			res = s.histogram.Copy()
+			continue
+		}
+		// Histograms with incompatible schemas are skipped.
+		_, _ = res.Add(s.histogram)
+	}
+	if res == nil {
+		res = &histogram.FloatHistogram{}
+	}
+	res.CounterResetHint = histogram.GaugeType
+	return res
+}
+
+// stats returns the number of input series and groups.
+func (a *aggregator) stats() (series, groups int) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	for _, g := range a.groups {
+		series += len(g.series)
+	}
+	return series, len(a.groups)
+}
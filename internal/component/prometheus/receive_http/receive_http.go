@@ -67,8 +67,9 @@ func New(opts component.Options, args Arguments) (*Component, error) {
 	uncheckedCollector := util.NewUncheckedCollector(nil)
 	opts.Registerer.MustRegister(uncheckedCollector)
 
-	//TODO: Make this configurable in the future?
-	supportedRemoteWriteProtoMsgs := config.RemoteWriteProtoMsgs{config.RemoteWriteProtoMsgV1}
+	// Both Remote Write 1.0 and 2.0 requests are accepted. The message version
+	// is negotiated using the Content-Type header of each request.
+	supportedRemoteWriteProtoMsgs := config.RemoteWriteProtoMsgs{config.RemoteWriteProtoMsgV1, config.RemoteWriteProtoMsgV2}
 
 	c := &Component{
 		opts:               opts,
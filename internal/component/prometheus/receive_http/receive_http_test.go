@@ -18,8 +18,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
+	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/stretchr/testify/assert"
@@ -79,6 +81,76 @@ func TestForwardsMetrics(t *testing.T) {
 	verifyExpectations(t, input, expected, actualSamples, args, ctx)
 }
 
+func TestForwardsMetricsRemoteWriteV2(t *testing.T) {
+	timestamp := time.Now().Add(time.Second).UnixMilli()
+
+	st := writev2.NewSymbolTable()
+	input := []writev2.TimeSeries{{
+		LabelsRefs: st.SymbolizeLabels(labels.FromStrings("__name__", "test_metric", "cluster", "local", "foo", "bar"), nil),
+		Samples: []writev2.Sample{
+			{Timestamp: timestamp, Value: 12},
+			{Timestamp: timestamp + 1, Value: 24},
+		},
+		Metadata: writev2.Metadata{
+			Type:    writev2.Metadata_METRIC_TYPE_COUNTER,
+			HelpRef: st.Symbolize("A test metric."),
+		},
+	}, {
+		LabelsRefs: st.SymbolizeLabels(labels.FromStrings("__name__", "test_metric", "cluster", "local", "fizz", "buzz"), nil),
+		Samples: []writev2.Sample{
+			{Timestamp: timestamp, Value: 191},
+		},
+	}}
+
+	expected := []testSample{
+		{ts: timestamp, val: 12, l: labels.FromStrings("__name__", "test_metric", "cluster", "local", "foo", "bar")},
+		{ts: timestamp + 1, val: 24, l: labels.FromStrings("__name__", "test_metric", "cluster", "local", "foo", "bar")},
+		{ts: timestamp, val: 191, l: labels.FromStrings("__name__", "test_metric", "cluster", "local", "fizz", "buzz")},
+	}
+
+	actualSamples := make(chan testSample, 100)
+
+	// Start the component
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	args := Arguments{
+		Server: &fnet.ServerConfig{
+			HTTP: &fnet.HTTPConfig{
+				ListenAddress: "localhost",
+				ListenPort:    port,
+			},
+			GRPC: testGRPCConfig(t),
+		},
+		ForwardTo: testAppendable(actualSamples),
+	}
+	comp, err := New(testOptions(t), args)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	go func() {
+		require.NoError(t, comp.Run(ctx))
+	}()
+
+	waitForServerToBeReady(t, args)
+
+	endpoint := fmt.Sprintf(
+		"http://%s:%d/api/v1/metrics/write",
+		args.Server.HTTP.ListenAddress,
+		args.Server.HTTP.ListenPort,
+	)
+	err = requestV2(ctx, endpoint, &writev2.Request{Symbols: st.Symbols(), Timeseries: input})
+	require.NoError(t, err)
+
+	for _, exp := range expected {
+		select {
+		case actual := <-actualSamples:
+			require.Equal(t, exp, actual)
+		case <-ctx.Done():
+			t.Fatalf("test timed out")
+		}
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	timestamp := time.Now().Add(time.Second).UnixMilli()
 	input01 := []prompb.TimeSeries{{
@@ -382,6 +454,31 @@ func request(ctx context.Context, rawRemoteWriteURL string, req *prompb.WriteReq
 	return err
 }
 
+func requestV2(ctx context.Context, rawRemoteWriteURL string, req *writev2.Request) error {
+	remoteWriteURL, err := url.Parse(rawRemoteWriteURL)
+	if err != nil {
+		return err
+	}
+
+	client, err := remote.NewWriteClient("remote-write-client", &remote.ClientConfig{
+		URL:           &config.URL{URL: remoteWriteURL},
+		Timeout:       model.Duration(30 * time.Second),
+		WriteProtoMsg: promconfig.RemoteWriteProtoMsgV2,
+	})
+	if err != nil {
+		return err
+	}
+
+	buf, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+
+	compressed := snappy.Encode(nil, buf)
+	_, err = client.Store(ctx, compressed, 0)
+	return err
+}
+
 func testOptions(t *testing.T) component.Options {
 	return component.Options{
 		ID:         "prometheus.receive_http.test",
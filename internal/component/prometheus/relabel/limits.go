@@ -0,0 +1,388 @@
+package relabel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// seriesLimitCheckInterval is how often idle series are evicted and the
+// limited label values are exported.
+const seriesLimitCheckInterval = 15 * time.Second
+
+// SeriesLimit limits the number of active series per value of a label.
+type SeriesLimit struct {
+	// The label whose values are limited.
+	Label string `alloy:"label,attr"`
+
+	// The maximum number of active series per label value, and the maximum
+	// of specific label values.
+	MaxSeries int            `alloy:"max_series,attr"`
+	Overrides map[string]int `alloy:"overrides,attr,optional"`
+
+	// How long a series without samples is still considered active.
+	IdleTimeout time.Duration `alloy:"idle_timeout,attr,optional"`
+
+	// Where the samples of series over the limit are forwarded to. They are
+	// dropped if empty.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (sl *SeriesLimit) SetToDefault() {
+	*sl = SeriesLimit{
+		IdleTimeout: 10 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (sl *SeriesLimit) Validate() error {
+	if sl.Label == "" {
+		return fmt.Errorf("series_limit label must not be empty")
+	}
+	if sl.MaxSeries <= 0 {
+		return fmt.Errorf("series_limit max_series must be greater than 0 and is %d", sl.MaxSeries)
+	}
+	for value, limit := range sl.Overrides {
+		if limit < 0 {
+			return fmt.Errorf("series_limit override of %q must not be negative and is %d", value, limit)
+		}
+	}
+	if sl.IdleTimeout <= 0 {
+		return fmt.Errorf("series_limit idle_timeout must be greater than 0")
+	}
+	return nil
+}
+
+func (sl *SeriesLimit) maxSeries(value string) int {
+	if limit, ok := sl.Overrides[value]; ok {
+		return limit
+	}
+	return sl.MaxSeries
+}
+
+// seriesLimit tracks the active series of a series_limit block.
+type seriesLimit struct {
+	args   SeriesLimit
+	fanout *prometheus.Fanout
+
+	// The last time a sample was received for each series, by label value and
+	// series hash.
+	series map[string]map[uint64]time.Time
+}
+
+// limiter is the appendable the relabelled series are sent to. It forwards the
+// series within the limits to next, and drops or reroutes the series over a
+// limit.
+type limiter struct {
+	next        storage.Appendable
+	componentID string
+	registerer  prometheus_client.Registerer
+	ls          labelstore.LabelStore
+
+	activeSeries   *prometheus_client.GaugeVec
+	limitedSamples *prometheus_client.CounterVec
+
+	mut    sync.Mutex
+	limits []*seriesLimit
+}
+
+var _ storage.Appendable = (*limiter)(nil)
+
+func newLimiter(next storage.Appendable, componentID string, reg prometheus_client.Registerer, ls labelstore.LabelStore) (*limiter, error) {
+	l := &limiter{
+		next:        next,
+		componentID: componentID,
+		registerer:  reg,
+		ls:          ls,
+		activeSeries: prometheus_client.NewGaugeVec(prometheus_client.GaugeOpts{
+			Name: "alloy_prometheus_relabel_limit_active_series",
+			Help: "Number of active series per value of a limited label",
+		}, []string{"label", "value"}),
+		limitedSamples: prometheus_client.NewCounterVec(prometheus_client.CounterOpts{
+			Name: "alloy_prometheus_relabel_limited_samples_total",
+			Help: "Total number of samples of series over a limit, which were dropped or rerouted",
+		}, []string{"label", "value"}),
+	}
+
+	for _, metric := range []prometheus_client.Collector{l.activeSeries, l.limitedSamples} {
+		if err := reg.Register(metric); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// update applies the series_limit blocks. The active series of a label are
+// kept when the label is still limited.
+func (l *limiter) update(args []SeriesLimit) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	existing := make(map[string]*seriesLimit, len(l.limits))
+	for _, lim := range l.limits {
+		existing[lim.args.Label] = lim
+	}
+
+	limits := make([]*seriesLimit, 0, len(args))
+	for _, arg := range args {
+		lim, ok := existing[arg.Label]
+		if ok {
+			delete(existing, arg.Label)
+		} else {
+			lim = &seriesLimit{series: make(map[string]map[uint64]time.Time)}
+		}
+		lim.args = arg
+
+		if len(arg.ForwardTo) == 0 {
+			lim.fanout = nil
+		} else if lim.fanout == nil {
+			lim.fanout = prometheus.NewFanout(arg.ForwardTo, l.componentID, l.registerer, l.ls)
+		} else {
+			lim.fanout.UpdateChildren(arg.ForwardTo)
+		}
+		limits = append(limits, lim)
+	}
+
+	// Remove the metrics of the labels which aren't limited anymore.
+	for label := range existing {
+		l.activeSeries.DeletePartialMatch(prometheus_client.Labels{"label": label})
+		l.limitedSamples.DeletePartialMatch(prometheus_client.Labels{"label": label})
+	}
+	l.limits = limits
+}
+
+// route returns the limit the series is over, or nil if the series is within
+// all the limits. When admit is true, a series within the limits is marked as
+// active, or removed from the active series if stale is true.
+func (l *limiter) route(lbls labels.Labels, admit, stale bool) *seriesLimit {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if len(l.limits) == 0 {
+		return nil
+	}
+
+	hash := lbls.Hash()
+	for _, lim := range l.limits {
+		value := lbls.Get(lim.args.Label)
+		if value == "" {
+			continue
+		}
+		if _, active := lim.series[value][hash]; active {
+			continue
+		}
+		if len(lim.series[value]) >= lim.args.maxSeries(value) {
+			if admit {
+				l.limitedSamples.WithLabelValues(lim.args.Label, value).Inc()
+			}
+			return lim
+		}
+	}
+	if !admit {
+		return nil
+	}
+
+	now := time.Now()
+	for _, lim := range l.limits {
+		value := lbls.Get(lim.args.Label)
+		if value == "" {
+			continue
+		}
+
+		active := lim.series[value]
+		if stale {
+			delete(active, hash)
+		} else {
+			if active == nil {
+				active = make(map[uint64]time.Time)
+				lim.series[value] = active
+			}
+			active[hash] = now
+		}
+		l.setActiveSeries(lim, value)
+	}
+	return nil
+}
+
+// setActiveSeries updates the active series metric of a label value, and
+// forgets the label value when it has no active series.
+func (l *limiter) setActiveSeries(lim *seriesLimit, value string) {
+	if count := len(lim.series[value]); count > 0 {
+		l.activeSeries.WithLabelValues(lim.args.Label, value).Set(float64(count))
+		return
+	}
+	delete(lim.series, value)
+	l.activeSeries.DeleteLabelValues(lim.args.Label, value)
+}
+
+// evictIdle removes the series which didn't receive samples since their idle
+// timeout.
+func (l *limiter) evictIdle(now time.Time) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	for _, lim := range l.limits {
+		deadline := now.Add(-lim.args.IdleTimeout)
+		for value, active := range lim.series {
+			for hash, lastSeen := range active {
+				if lastSeen.Before(deadline) {
+					delete(active, hash)
+				}
+			}
+			l.setActiveSeries(lim, value)
+		}
+	}
+}
+
+// limitedValues returns the label values which reached their limit, by label.
+func (l *limiter) limitedValues() map[string][]string {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	res := make(map[string][]string)
+	for _, lim := range l.limits {
+		values := []string{}
+		for value, active := range lim.series {
+			if len(active) >= lim.args.maxSeries(value) {
+				values = append(values, value)
+			}
+		}
+		// Label values whose limit is 0 reach their limit without any active
+		// series.
+		for value, limit := range lim.args.Overrides {
+			if limit == 0 {
+				values = append(values, value)
+			}
+		}
+		sort.Strings(values)
+		res[lim.args.Label] = slices.Compact(values)
+	}
+	return res
+}
+
+// Appender implements storage.Appendable.
+func (l *limiter) Appender(ctx context.Context) storage.Appender {
+	next := l.next.Appender(ctx)
+
+	l.mut.Lock()
+	hasLimits := len(l.limits) > 0
+	l.mut.Unlock()
+	if !hasLimits {
+		return next
+	}
+
+	return &limitAppender{
+		ctx:      ctx,
+		limiter:  l,
+		next:     next,
+		rerouted: make(map[*seriesLimit]storage.Appender),
+	}
+}
+
+// limitAppender sends the series within the limits to the next appender, and
+// the series over a limit to the appender of that limit.
+type limitAppender struct {
+	ctx      context.Context
+	limiter  *limiter
+	next     storage.Appender
+	rerouted map[*seriesLimit]storage.Appender
+}
+
+var _ storage.Appender = (*limitAppender)(nil)
+
+// appender returns the appender a series is sent to, or nil if it's dropped.
+func (a *limitAppender) appender(l labels.Labels, admit, stale bool) storage.Appender {
+	lim := a.limiter.route(l, admit, stale)
+	switch {
+	case lim == nil:
+		return a.next
+	case lim.fanout == nil:
+		return nil
+	}
+
+	app, ok := a.rerouted[lim]
+	if !ok {
+		app = lim.fanout.Appender(a.ctx)
+		a.rerouted[lim] = app
+	}
+	return app
+}
+
+// Append implements storage.Appender.
+func (a *limitAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	app := a.appender(l, true, value.IsStaleNaN(v))
+	if app == nil {
+		return 0, nil
+	}
+	return app.Append(ref, l, t, v)
+}
+
+// AppendHistogram implements storage.Appender.
+func (a *limitAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	stale := (h != nil && value.IsStaleNaN(h.Sum)) || (fh != nil && value.IsStaleNaN(fh.Sum))
+	app := a.appender(l, true, stale)
+	if app == nil {
+		return 0, nil
+	}
+	return app.AppendHistogram(ref, l, t, h, fh)
+}
+
+// AppendExemplar implements storage.Appender.
+func (a *limitAppender) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
+	app := a.appender(l, false, false)
+	if app == nil {
+		return 0, nil
+	}
+	return app.AppendExemplar(ref, l, e)
+}
+
+// UpdateMetadata implements storage.Appender.
+func (a *limitAppender) UpdateMetadata(ref storage.SeriesRef, l labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
+	app := a.appender(l, false, false)
+	if app == nil {
+		return 0, nil
+	}
+	return app.UpdateMetadata(ref, l, m)
+}
+
+// AppendCTZeroSample implements storage.Appender.
+func (a *limitAppender) AppendCTZeroSample(ref storage.SeriesRef, l labels.Labels, t, ct int64) (storage.SeriesRef, error) {
+	app := a.appender(l, false, false)
+	if app == nil {
+		return 0, nil
+	}
+	return app.AppendCTZeroSample(ref, l, t, ct)
+}
+
+// Commit implements storage.Appender.
+func (a *limitAppender) Commit() error {
+	err := a.next.Commit()
+	for _, app := range a.rerouted {
+		err = errors.Join(err, app.Commit())
+	}
+	return err
+}
+
+// Rollback implements storage.Appender.
+func (a *limitAppender) Rollback() error {
+	err := a.next.Rollback()
+	for _, app := range a.rerouted {
+		err = errors.Join(err, app.Rollback())
+	}
+	return err
+}
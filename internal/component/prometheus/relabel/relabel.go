@@ -3,7 +3,9 @@ package relabel
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
@@ -49,6 +51,10 @@ type Arguments struct {
 
 	// Cache size to use for LRU cache.
 	CacheSize int `alloy:"max_cache_size,attr,optional"`
+
+	// Limits of active series per label value, applied to the relabelled
+	// series.
+	SeriesLimits []SeriesLimit `alloy:"series_limit,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -63,13 +69,21 @@ func (arg *Arguments) Validate() error {
 	if arg.CacheSize <= 0 {
 		return fmt.Errorf("max_cache_size must be greater than 0 and is %d", arg.CacheSize)
 	}
+	seen := make(map[string]struct{}, len(arg.SeriesLimits))
+	for _, sl := range arg.SeriesLimits {
+		if _, ok := seen[sl.Label]; ok {
+			return fmt.Errorf("series_limit label %q is limited more than once", sl.Label)
+		}
+		seen[sl.Label] = struct{}{}
+	}
 	return nil
 }
 
 // Exports holds values which are exported by the prometheus.relabel component.
 type Exports struct {
-	Receiver storage.Appendable  `alloy:"receiver,attr"`
-	Rules    alloy_relabel.Rules `alloy:"rules,attr"`
+	Receiver           storage.Appendable  `alloy:"receiver,attr"`
+	Rules              alloy_relabel.Rules `alloy:"rules,attr"`
+	LimitedLabelValues map[string][]string `alloy:"limited_label_values,attr"`
 }
 
 // Component implements the prometheus.relabel component.
@@ -85,6 +99,7 @@ type Component struct {
 	cacheSize        prometheus_client.Gauge
 	cacheDeletes     prometheus_client.Counter
 	fanout           *prometheus.Fanout
+	limiter          *limiter
 	exited           atomic.Bool
 	ls               labelstore.LabelStore
 
@@ -92,6 +107,10 @@ type Component struct {
 
 	cacheMut sync.RWMutex
 	cache    *lru.Cache[uint64, *labelAndID]
+
+	// rules and limitedValues are the last exported values, protected by mut.
+	rules         alloy_relabel.Rules
+	limitedValues map[string][]string
 }
 
 var (
@@ -154,8 +173,12 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	}
 
 	c.fanout = prometheus.NewFanout(args.ForwardTo, o.ID, o.Registerer, c.ls)
+	c.limiter, err = newLimiter(c.fanout, o.ID, o.Registerer, c.ls)
+	if err != nil {
+		return nil, err
+	}
 	c.receiver = prometheus.NewInterceptor(
-		c.fanout,
+		c.limiter,
 		c.ls,
 		prometheus.WithAppendHook(func(_ storage.SeriesRef, l labels.Labels, t int64, v float64, next storage.Appender) (storage.SeriesRef, error) {
 			if c.exited.Load() {
@@ -206,7 +229,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 
 	// Immediately export the receiver which remains the same for the component
 	// lifetime.
-	o.OnStateChange(Exports{Receiver: c.receiver, Rules: args.MetricRelabelConfigs})
+	o.OnStateChange(Exports{Receiver: c.receiver, Rules: args.MetricRelabelConfigs, LimitedLabelValues: map[string][]string{}})
 
 	// Call to Update() to set the relabelling rules once at the start.
 	if err = c.Update(args); err != nil {
@@ -220,8 +243,18 @@ func New(o component.Options, args Arguments) (*Component, error) {
 func (c *Component) Run(ctx context.Context) error {
 	defer c.exited.Store(true)
 
-	<-ctx.Done()
-	return nil
+	ticker := time.NewTicker(seriesLimitCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			c.limiter.evictIdle(now)
+			c.exportLimitedValues()
+		}
+	}
 }
 
 // Update implements component.Component.
@@ -233,12 +266,29 @@ func (c *Component) Update(args component.Arguments) error {
 	c.clearCache(newArgs.CacheSize)
 	c.mrc = alloy_relabel.ComponentToPromRelabelConfigs(newArgs.MetricRelabelConfigs)
 	c.fanout.UpdateChildren(newArgs.ForwardTo)
+	c.limiter.update(newArgs.SeriesLimits)
 
-	c.opts.OnStateChange(Exports{Receiver: c.receiver, Rules: newArgs.MetricRelabelConfigs})
+	c.rules = newArgs.MetricRelabelConfigs
+	c.limitedValues = c.limiter.limitedValues()
+	c.opts.OnStateChange(Exports{Receiver: c.receiver, Rules: c.rules, LimitedLabelValues: c.limitedValues})
 
 	return nil
 }
 
+// exportLimitedValues exports the label values which reached their limit if
+// they changed since the last export.
+func (c *Component) exportLimitedValues() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	limitedValues := c.limiter.limitedValues()
+	if reflect.DeepEqual(limitedValues, c.limitedValues) {
+		return
+	}
+	c.limitedValues = limitedValues
+	c.opts.OnStateChange(Exports{Receiver: c.receiver, Rules: c.rules, LimitedLabelValues: c.limitedValues})
+}
+
 func (c *Component) relabel(val float64, lbls labels.Labels) labels.Labels {
 	c.mut.RLock()
 	defer c.mut.RUnlock()
@@ -287,6 +337,7 @@ func (c *Component) relabel(val float64, lbls labels.Labels) labels.Labels {
 		func() string {
 			return fmt.Sprintf("%s => %s", lbls.String(), relabelled.String())
 		},
+		livedebugging.WithPrometheusLabels(lbls, relabelled),
 	))
 
 	return relabelled
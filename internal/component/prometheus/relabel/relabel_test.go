@@ -235,6 +235,134 @@ func TestRuleGetter(t *testing.T) {
 	require.Equal(t, gotUpdated[0].Regex, gotOriginal[0].Regex)
 }
 
+func TestSeriesLimitArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			cfg: `
+				forward_to = []
+
+				series_limit {
+					label      = "namespace"
+					max_series = 100
+					overrides  = {
+						"kube-system" = 1000,
+					}
+				}
+			`,
+		},
+		{
+			name: "invalid max_series",
+			cfg: `
+				forward_to = []
+
+				series_limit {
+					label      = "namespace"
+					max_series = 0
+				}
+			`,
+			expectedErr: "series_limit max_series must be greater than 0",
+		},
+		{
+			name: "duplicate label",
+			cfg: `
+				forward_to = []
+
+				series_limit {
+					label      = "namespace"
+					max_series = 100
+				}
+
+				series_limit {
+					label      = "namespace"
+					max_series = 10
+				}
+			`,
+			expectedErr: `series_limit label "namespace" is limited more than once`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSeriesLimit(t *testing.T) {
+	ls := labelstore.New(nil, prom.DefaultRegisterer)
+	var forwarded, rerouted []string
+	newOutput := func(dst *[]string) storage.Appendable {
+		return prometheus.NewInterceptor(nil, ls, prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, _ float64, _ storage.Appender) (storage.SeriesRef, error) {
+			*dst = append(*dst, l.Get("pod"))
+			return ref, nil
+		}))
+	}
+
+	var exports Exports
+	c, err := New(component.Options{
+		ID:             "1",
+		Logger:         util.TestAlloyLogger(t),
+		OnStateChange:  func(e component.Exports) { exports = e.(Exports) },
+		Registerer:     prom.NewRegistry(),
+		GetServiceData: getServiceData,
+	}, Arguments{
+		ForwardTo: []storage.Appendable{newOutput(&forwarded)},
+		CacheSize: 100_000,
+		SeriesLimits: []SeriesLimit{{
+			Label:       "namespace",
+			MaxSeries:   2,
+			Overrides:   map[string]int{"b": 1},
+			IdleTimeout: time.Minute,
+			ForwardTo:   []storage.Appendable{newOutput(&rerouted)},
+		}},
+	})
+	require.NoError(t, err)
+
+	appendSample := func(namespace, pod string, v float64) {
+		app := exports.Receiver.Appender(t.Context())
+		_, err := app.Append(0, labels.FromStrings("namespace", namespace, "pod", pod), time.Now().UnixMilli(), v)
+		require.NoError(t, err)
+		require.NoError(t, app.Commit())
+	}
+
+	appendSample("a", "a1", 1)
+	appendSample("a", "a2", 1)
+	appendSample("a", "a3", 1)
+	appendSample("a", "a1", 2) // active series are still accepted
+	appendSample("b", "b1", 1)
+	appendSample("b", "b2", 1)
+	appendSample("", "c1", 1) // series without the label aren't limited
+
+	require.Equal(t, []string{"a1", "a2", "a1", "b1", "c1"}, forwarded)
+	require.Equal(t, []string{"a3", "b2"}, rerouted)
+
+	c.exportLimitedValues()
+	require.Equal(t, map[string][]string{"namespace": {"a", "b"}}, exports.LimitedLabelValues)
+
+	// A stale marker frees the slot of its series.
+	appendSample("a", "a2", math.Float64frombits(value.StaleNaN))
+	appendSample("a", "a3", 1)
+	require.Equal(t, "a3", forwarded[len(forwarded)-1])
+
+	// Idle series are evicted.
+	c.limiter.evictIdle(time.Now().Add(2 * time.Minute))
+	c.exportLimitedValues()
+	require.Equal(t, map[string][]string{"namespace": {}}, exports.LimitedLabelValues)
+	appendSample("b", "b2", 1)
+	require.Equal(t, "b2", forwarded[len(forwarded)-1])
+}
+
 func getServiceData(name string) (interface{}, error) {
 	switch name {
 	case labelstore.ServiceName:
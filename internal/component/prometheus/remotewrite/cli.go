@@ -1,11 +1,16 @@
 package remotewrite
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
+	"time"
 
+	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/static/agentctl/waltools"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -18,6 +23,7 @@ func InstallTools(cmd *cobra.Command) {
 		samplesCmd(),
 		targetStatsCmd(),
 		walStatsCmd(),
+		replayCmd(),
 	)
 }
 
@@ -203,6 +209,118 @@ deletion but then comes back at some point).`,
 	}
 }
 
+func replayCmd() *cobra.Command {
+	var (
+		opts      = defaultReplayOptions()
+		targetURL string
+		from, to  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay [WAL directory]",
+		Short: "Send the samples of a WAL to a Remote Write endpoint",
+		Long: `replay reads a WAL directory, including its checkpoint, and sends the samples,
+exemplars, and metadata within it to a Remote Write endpoint. A label selector
+and a time range can be used to filter the series and samples that should be
+sent.
+
+The samples and exemplars are sharded by series and sent in batches, retrying
+failed requests with backoff like prometheus.remote_write does. The metadata is
+sent in separate requests after the samples. Batches which can't be sent after
+all retries are counted as failed, and the replay continues with the next ones.
+
+Examples:
+
+Send all samples in the WAL:
+
+replay --url http://localhost:9009/api/v1/push /tmp/wal
+
+
+Send the samples of the 'up' series of the last hour:
+
+replay --url http://localhost:9009/api/v1/push -s up --from 2024-01-01T10:00:00Z --to 2024-01-01T11:00:00Z /tmp/wal
+`,
+		Args: cobra.ExactArgs(1),
+
+		Run: func(_ *cobra.Command, args []string) {
+			directory := args[0]
+			if _, err := os.Stat(directory); os.IsNotExist(err) {
+				fmt.Printf("%s does not exist\n", directory)
+				os.Exit(1)
+			} else if err != nil {
+				fmt.Printf("error getting wal: %v\n", err)
+				os.Exit(1)
+			}
+
+			// Check if ./wal is a subdirectory, use that instead.
+			if _, err := os.Stat(filepath.Join(directory, "wal")); err == nil {
+				directory = filepath.Join(directory, "wal")
+			}
+
+			var err error
+			if opts.URL, err = url.Parse(targetURL); err != nil {
+				fmt.Printf("invalid url: %v\n", err)
+				os.Exit(1)
+			}
+			if opts.From, err = parseReplayTime(from); err != nil {
+				fmt.Printf("invalid --from: %v\n", err)
+				os.Exit(1)
+			}
+			if opts.To, err = parseReplayTime(to); err != nil {
+				fmt.Printf("invalid --to: %v\n", err)
+				os.Exit(1)
+			}
+
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer cancel()
+
+			logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
+			stats, err := replayWAL(ctx, logger, directory, opts)
+
+			fmt.Printf("Samples Sent:       %d\n", stats.SamplesSent)
+			fmt.Printf("Samples Failed:     %d\n", stats.SamplesFailed)
+			fmt.Printf("Exemplars Sent:     %d\n", stats.ExemplarsSent)
+			fmt.Printf("Exemplars Failed:   %d\n", stats.ExemplarsFailed)
+			fmt.Printf("Metadata Sent:      %d\n", stats.MetadataSent)
+			fmt.Printf("Metadata Failed:    %d\n", stats.MetadataFailed)
+			fmt.Printf("Requests:           %d\n", stats.Requests)
+			fmt.Printf("Invalid Refs:       %d\n", stats.InvalidRefs)
+
+			if err != nil {
+				fmt.Printf("failed to replay WAL: %v\n", err)
+				os.Exit(1)
+			}
+			if stats.failed() {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&targetURL, "url", "", "URL of the Remote Write endpoint")
+	cmd.Flags().StringVarP(&opts.Selector, "selector", "s", opts.Selector, "label selector of the series to send")
+	cmd.Flags().StringVar(&from, "from", "", "only send samples at or after this RFC3339 timestamp")
+	cmd.Flags().StringVar(&to, "to", "", "only send samples at or before this RFC3339 timestamp")
+	cmd.Flags().StringToStringVar(&opts.Headers, "header", nil, "extra header to send with requests, in the form name=value")
+	cmd.Flags().StringVar(&opts.BearerTokenFile, "bearer-token-file", "", "file containing a bearer token to authenticate with")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", opts.Timeout, "timeout of requests")
+	cmd.Flags().IntVar(&opts.Shards, "shards", opts.Shards, "number of shards sending samples concurrently")
+	cmd.Flags().IntVar(&opts.MaxSamplesPerSend, "max-samples-per-send", opts.MaxSamplesPerSend, "maximum number of samples and exemplars per request")
+	cmd.Flags().DurationVar(&opts.MinBackoff, "min-backoff", opts.MinBackoff, "initial retry delay")
+	cmd.Flags().DurationVar(&opts.MaxBackoff, "max-backoff", opts.MaxBackoff, "maximum retry delay")
+	cmd.Flags().IntVar(&opts.MaxRetries, "max-retries", opts.MaxRetries, "maximum number of retries of a request, 0 for unlimited")
+	must(cmd.MarkFlagRequired("url"))
+	return cmd
+}
+
+// parseReplayTime parses an RFC3339 timestamp. An empty string returns the
+// zero time, which doesn't bound the replay.
+func parseReplayTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
 func must(err error) {
 	if err != nil {
 		panic(err)
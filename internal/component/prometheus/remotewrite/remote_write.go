@@ -85,7 +85,9 @@ func New(o component.Options, c Arguments) (*Component, error) {
 	}
 
 	remoteLogger := log.With(o.Logger, "subcomponent", "rw")
-	remoteStore := remote.NewStorage(remoteLogger, o.Registerer, startTime, o.DataPath, remoteFlushDeadline, nil, false)
+	// The WAL always records metadata, which is required by endpoints using
+	// Remote Write 2.0 to send metadata alongside each series.
+	remoteStore := remote.NewStorage(remoteLogger, o.Registerer, startTime, o.DataPath, remoteFlushDeadline, nil, true)
 
 	walStorage.SetNotifier(remoteStore)
 
@@ -136,6 +138,7 @@ func New(o component.Options, c Arguments) (*Component, error) {
 				func() string {
 					return fmt.Sprintf("sample: ts=%d, labels=%s, value=%f", t, l, v)
 				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
@@ -164,6 +167,7 @@ func New(o component.Options, c Arguments) (*Component, error) {
 					}
 					return data
 				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
@@ -184,6 +188,7 @@ func New(o component.Options, c Arguments) (*Component, error) {
 				func() string {
 					return fmt.Sprintf("metadata: labels=%s, type=%q, unit=%q, help=%q", l, m.Type, m.Unit, m.Help)
 				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
@@ -204,6 +209,28 @@ func New(o component.Options, c Arguments) (*Component, error) {
 				func() string {
 					return fmt.Sprintf("exemplar: ts=%d, labels=%s, exemplar_labels=%s, value=%f", e.Ts, l, e.Labels, e.Value)
 				},
+				livedebugging.WithPrometheusLabels(l),
+			))
+			return globalRef, nextErr
+		}),
+		prometheus.WithCTZeroSampleHook(func(globalRef storage.SeriesRef, l labels.Labels, t, ct int64, next storage.Appender) (storage.SeriesRef, error) {
+			if res.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
+			newRef, nextErr := next.AppendCTZeroSample(storage.SeriesRef(localID), l, t, ct)
+			if localID == 0 {
+				ls.GetOrAddLink(res.opts.ID, uint64(newRef), l)
+			}
+			res.debugDataPublisher.PublishIfActive(livedebugging.NewData(
+				componentID,
+				livedebugging.PrometheusMetric,
+				1,
+				func() string {
+					return fmt.Sprintf("created_timestamp_zero_sample: ts=%d, labels=%s, created_timestamp=%d", t, l, ct)
+				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
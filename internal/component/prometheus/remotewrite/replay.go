@@ -0,0 +1,344 @@
+package remotewrite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/backoff"
+	commonconfig "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage/remote"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/static/agentctl/waltools"
+)
+
+// replayOptions configures a replay of a WAL to a remote_write endpoint.
+type replayOptions struct {
+	URL             *url.URL
+	Headers         map[string]string
+	BearerTokenFile string
+	Timeout         time.Duration
+
+	// Only the samples of the series matching Selector with a timestamp
+	// between From and To are sent.
+	Selector string
+	From     time.Time
+	To       time.Time
+
+	// Samples are sharded by series so that the samples of a series are sent
+	// in order, like the remote_write queues do.
+	Shards            int
+	MaxSamplesPerSend int
+	MinBackoff        time.Duration
+	MaxBackoff        time.Duration
+	MaxRetries        int
+}
+
+// defaultReplayOptions returns the default replayOptions, which match the
+// default queue_config of prometheus.remote_write.
+func defaultReplayOptions() replayOptions {
+	return replayOptions{
+		Timeout:           30 * time.Second,
+		Selector:          "{}",
+		Shards:            DefaultQueueOptions.MaxShards,
+		MaxSamplesPerSend: DefaultQueueOptions.MaxSamplesPerSend,
+		MinBackoff:        DefaultQueueOptions.MinBackoff,
+		MaxBackoff:        DefaultQueueOptions.MaxBackoff,
+		MaxRetries:        10,
+	}
+}
+
+// replayStats holds the results of a replay.
+type replayStats struct {
+	SamplesSent     int64
+	SamplesFailed   int64
+	ExemplarsSent   int64
+	ExemplarsFailed int64
+	MetadataSent    int64
+	MetadataFailed  int64
+	Requests        int64
+	InvalidRefs     int
+}
+
+// failed returns true if some of the data couldn't be sent.
+func (s replayStats) failed() bool {
+	return s.SamplesFailed > 0 || s.ExemplarsFailed > 0 || s.MetadataFailed > 0
+}
+
+// replaySample is a sample of a series to send.
+type replaySample struct {
+	labels labels.Labels
+	sample waltools.Sample
+}
+
+// replayWAL reads the WAL in walDir and sends the samples and exemplars
+// matching the options to the endpoint, followed by the metadata of their
+// series. Batches which can't be sent after retrying are counted as failed and
+// don't stop the replay.
+func replayWAL(ctx context.Context, logger log.Logger, walDir string, opts replayOptions) (replayStats, error) {
+	if opts.Shards <= 0 {
+		return replayStats{}, fmt.Errorf("shards must be greater than 0")
+	}
+	if opts.MaxSamplesPerSend <= 0 {
+		return replayStats{}, fmt.Errorf("max samples per send must be greater than 0")
+	}
+
+	httpConfig := commonconfig.DefaultHTTPClientConfig
+	httpConfig.BearerTokenFile = opts.BearerTokenFile
+	client, err := remote.NewWriteClient("replay", &remote.ClientConfig{
+		URL:              &commonconfig.URL{URL: opts.URL},
+		Timeout:          model.Duration(opts.Timeout),
+		HTTPClientConfig: httpConfig,
+		Headers:          opts.Headers,
+		RetryOnRateLimit: true,
+		WriteProtoMsg:    config.RemoteWriteProtoMsgV1,
+	})
+	if err != nil {
+		return replayStats{}, fmt.Errorf("failed to create remote_write client: %w", err)
+	}
+
+	mint, maxt := int64(math.MinInt64), int64(math.MaxInt64)
+	if !opts.From.IsZero() {
+		mint = opts.From.UnixMilli()
+	}
+	if !opts.To.IsZero() {
+		maxt = opts.To.UnixMilli()
+	}
+
+	var (
+		wg       sync.WaitGroup
+		stats    = &replayShardStats{}
+		shards   = make([]chan replaySample, opts.Shards)
+		metadata = make(map[string]prompb.MetricMetadata)
+	)
+	for i := range shards {
+		shards[i] = make(chan replaySample, opts.MaxSamplesPerSend)
+		s := &replayShard{
+			client: client,
+			logger: logger,
+			opts:   opts,
+			stats:  stats,
+		}
+		wg.Add(1)
+		go func(in <-chan replaySample) {
+			defer wg.Done()
+			s.run(ctx, in)
+		}(shards[i])
+	}
+
+	invalidRefs, readErr := waltools.ReadSamples(walDir, opts.Selector, mint, maxt, func(l labels.Labels, s waltools.Sample) error {
+		if s.Metadata != nil {
+			// Remote Write 1.0 sends metadata per metric name rather than per
+			// series, so only the latest metadata of every name is kept.
+			name := l.Get(model.MetricNameLabel)
+			metadata[name] = prompb.MetricMetadata{
+				MetricFamilyName: name,
+				Type:             prompb.FromMetadataType(s.Metadata.Type),
+				Unit:             s.Metadata.Unit,
+				Help:             s.Metadata.Help,
+			}
+			return nil
+		}
+
+		select {
+		case shards[l.Hash()%uint64(len(shards))] <- replaySample{labels: l, sample: s}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	for _, shard := range shards {
+		close(shard)
+	}
+	wg.Wait()
+
+	if readErr == nil {
+		// Metadata is sent in its own requests, like the metadata of
+		// prometheus.remote_write.
+		s := &replayShard{
+			client: client,
+			logger: logger,
+			opts:   opts,
+			stats:  stats,
+		}
+		s.sendMetadata(ctx, metadata)
+	}
+
+	res := replayStats{
+		SamplesSent:     stats.samplesSent.Load(),
+		SamplesFailed:   stats.samplesFailed.Load(),
+		ExemplarsSent:   stats.exemplarsSent.Load(),
+		ExemplarsFailed: stats.exemplarsFailed.Load(),
+		MetadataSent:    stats.metadataSent.Load(),
+		MetadataFailed:  stats.metadataFailed.Load(),
+		Requests:        stats.requests.Load(),
+		InvalidRefs:     invalidRefs,
+	}
+	if readErr != nil {
+		return res, fmt.Errorf("failed to read WAL: %w", readErr)
+	}
+	return res, nil
+}
+
+type replayShardStats struct {
+	samplesSent     atomic.Int64
+	samplesFailed   atomic.Int64
+	exemplarsSent   atomic.Int64
+	exemplarsFailed atomic.Int64
+	metadataSent    atomic.Int64
+	metadataFailed  atomic.Int64
+	requests        atomic.Int64
+}
+
+// replayShard batches the samples of a subset of the series and sends them.
+type replayShard struct {
+	client remote.WriteClient
+	logger log.Logger
+	opts   replayOptions
+	stats  *replayShardStats
+
+	pending          []prompb.TimeSeries
+	seriesIndex      map[uint64]int
+	pendingSamples   int
+	pendingExemplars int
+}
+
+func (s *replayShard) run(ctx context.Context, in <-chan replaySample) {
+	s.seriesIndex = make(map[uint64]int)
+
+	for sample := range in {
+		s.add(sample)
+		// Exemplars count towards the size of a batch, like in the queues of
+		// prometheus.remote_write.
+		if s.pendingSamples+s.pendingExemplars >= s.opts.MaxSamplesPerSend {
+			s.flush(ctx)
+		}
+	}
+	s.flush(ctx)
+}
+
+// add adds a sample or an exemplar to the pending batch, grouping the samples
+// and exemplars of the same series in a single time series.
+func (s *replayShard) add(rs replaySample) {
+	hash := rs.labels.Hash()
+	idx, ok := s.seriesIndex[hash]
+	if !ok {
+		idx = len(s.pending)
+		s.seriesIndex[hash] = idx
+
+		ts := prompb.TimeSeries{}
+		rs.labels.Range(func(l labels.Label) {
+			ts.Labels = append(ts.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+		})
+		s.pending = append(s.pending, ts)
+	}
+
+	ts := &s.pending[idx]
+	switch {
+	case rs.sample.Exemplar != nil:
+		ts.Exemplars = append(ts.Exemplars, prompb.Exemplar{
+			Labels:    prompb.FromLabels(rs.sample.Exemplar.Labels, nil),
+			Value:     rs.sample.Exemplar.Value,
+			Timestamp: rs.sample.Exemplar.Ts,
+		})
+		s.pendingExemplars++
+		return
+	case rs.sample.H != nil:
+		ts.Histograms = append(ts.Histograms, prompb.FromIntHistogram(rs.sample.T, rs.sample.H))
+	case rs.sample.FH != nil:
+		ts.Histograms = append(ts.Histograms, prompb.FromFloatHistogram(rs.sample.T, rs.sample.FH))
+	default:
+		ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: rs.sample.T, Value: rs.sample.V})
+	}
+	s.pendingSamples++
+}
+
+// flush sends the pending batch and resets it.
+func (s *replayShard) flush(ctx context.Context) {
+	if s.pendingSamples == 0 && s.pendingExemplars == 0 {
+		return
+	}
+	samples, exemplars := int64(s.pendingSamples), int64(s.pendingExemplars)
+	err := s.send(ctx, &prompb.WriteRequest{Timeseries: s.pending})
+
+	s.pending = s.pending[:0]
+	clear(s.seriesIndex)
+	s.pendingSamples = 0
+	s.pendingExemplars = 0
+
+	if err != nil {
+		level.Error(s.logger).Log("msg", "failed to send samples", "samples", samples, "exemplars", exemplars, "err", err)
+		s.stats.samplesFailed.Add(samples)
+		s.stats.exemplarsFailed.Add(exemplars)
+		return
+	}
+	s.stats.samplesSent.Add(samples)
+	s.stats.exemplarsSent.Add(exemplars)
+}
+
+// sendMetadata sends the metadata in batches of at most MaxSamplesPerSend
+// entries.
+func (s *replayShard) sendMetadata(ctx context.Context, metadata map[string]prompb.MetricMetadata) {
+	names := slices.Sorted(maps.Keys(metadata))
+	for len(names) > 0 {
+		n := min(len(names), s.opts.MaxSamplesPerSend)
+		batch := make([]prompb.MetricMetadata, 0, n)
+		for _, name := range names[:n] {
+			batch = append(batch, metadata[name])
+		}
+		names = names[n:]
+
+		if err := s.send(ctx, &prompb.WriteRequest{Metadata: batch}); err != nil {
+			level.Error(s.logger).Log("msg", "failed to send metadata", "count", len(batch), "err", err)
+			s.stats.metadataFailed.Add(int64(len(batch)))
+			continue
+		}
+		s.stats.metadataSent.Add(int64(len(batch)))
+	}
+}
+
+// send sends a write request, retrying recoverable errors with backoff.
+func (s *replayShard) send(ctx context.Context, req *prompb.WriteRequest) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	compressed := snappy.Encode(nil, data)
+
+	b := backoff.New(ctx, backoff.Config{
+		MinBackoff: s.opts.MinBackoff,
+		MaxBackoff: s.opts.MaxBackoff,
+		MaxRetries: s.opts.MaxRetries,
+	})
+	for b.Ongoing() {
+		s.stats.requests.Inc()
+		_, err = s.client.Store(ctx, compressed, b.NumRetries())
+		if err == nil {
+			return nil
+		}
+
+		var recoverable remote.RecoverableError
+		if !errors.As(err, &recoverable) {
+			return err
+		}
+		level.Warn(s.logger).Log("msg", "failed to send samples, retrying", "err", err)
+		b.Wait()
+	}
+	return fmt.Errorf("%w: %w", b.Err(), err)
+}
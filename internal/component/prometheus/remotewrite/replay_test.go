@@ -0,0 +1,289 @@
+package remotewrite
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/record"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestReplayWAL(t *testing.T) {
+	walDir := setupReplayWAL(t)
+
+	tests := []struct {
+		name              string
+		selector          string
+		from, to          int64
+		expectedSamples   map[string][]int64
+		expectedHists     map[string][]int64
+		expectedExemplars map[string][]int64
+		expectedMetadata  []string
+	}{
+		{
+			name:     "all samples",
+			selector: "{}",
+			expectedSamples: map[string][]int64{
+				`{__name__="metric_a", job="test"}`: {1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+				`{__name__="metric_b", job="test"}`: {1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			},
+			expectedHists: map[string][]int64{
+				`{__name__="metric_h", job="test"}`: {1, 2, 3, 4, 5},
+			},
+			expectedExemplars: map[string][]int64{
+				`{__name__="metric_a", job="test"}`: {2, 7},
+			},
+			expectedMetadata: []string{"metric_a", "metric_h"},
+		},
+		{
+			name:     "selector and time range",
+			selector: `{__name__="metric_a"}`,
+			from:     3,
+			to:       5,
+			expectedSamples: map[string][]int64{
+				`{__name__="metric_a", job="test"}`: {3, 4, 5},
+			},
+			expectedHists:     map[string][]int64{},
+			expectedExemplars: map[string][]int64{},
+			expectedMetadata:  []string{"metric_a"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newReplayServer(t, 0)
+
+			opts := defaultReplayOptions()
+			opts.URL = srv.url
+			opts.Selector = tc.selector
+			opts.Shards = 2
+			opts.MaxSamplesPerSend = 3
+			if tc.from != 0 {
+				opts.From = time.UnixMilli(tc.from)
+			}
+			if tc.to != 0 {
+				opts.To = time.UnixMilli(tc.to)
+			}
+
+			stats, err := replayWAL(t.Context(), log.NewNopLogger(), walDir, opts)
+			require.NoError(t, err)
+			require.Zero(t, stats.SamplesFailed)
+			require.Equal(t, srv.sampleCount(), int(stats.SamplesSent))
+			require.Equal(t, tc.expectedSamples, srv.samples)
+			require.Equal(t, tc.expectedHists, srv.histograms)
+			require.Equal(t, tc.expectedExemplars, srv.exemplars)
+			require.Equal(t, int64(len(tc.expectedExemplars[`{__name__="metric_a", job="test"}`])), stats.ExemplarsSent)
+			require.Equal(t, tc.expectedMetadata, srv.metadata)
+			require.Equal(t, int64(len(tc.expectedMetadata)), stats.MetadataSent)
+		})
+	}
+}
+
+func TestReplayWAL_Retry(t *testing.T) {
+	walDir := setupReplayWAL(t)
+
+	// The first requests fail with a recoverable error and are retried.
+	srv := newReplayServer(t, 2)
+
+	opts := defaultReplayOptions()
+	opts.URL = srv.url
+	opts.Shards = 1
+	opts.MinBackoff = time.Millisecond
+	opts.MaxBackoff = time.Millisecond
+
+	stats, err := replayWAL(t.Context(), log.NewNopLogger(), walDir, opts)
+	require.NoError(t, err)
+	require.Equal(t, int64(25), stats.SamplesSent)
+	require.Equal(t, int64(2), stats.ExemplarsSent)
+	require.Equal(t, int64(2), stats.MetadataSent)
+	require.False(t, stats.failed())
+	// The samples are sent after two retries, then the metadata.
+	require.Equal(t, int64(4), stats.Requests)
+}
+
+func TestReplayWAL_Failure(t *testing.T) {
+	walDir := setupReplayWAL(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	opts := defaultReplayOptions()
+	opts.URL = u
+	opts.Shards = 1
+
+	// Non-recoverable errors aren't retried, and the samples are counted as
+	// failed.
+	stats, err := replayWAL(t.Context(), log.NewNopLogger(), walDir, opts)
+	require.NoError(t, err)
+	require.Zero(t, stats.SamplesSent)
+	require.Equal(t, int64(25), stats.SamplesFailed)
+	require.Equal(t, int64(2), stats.ExemplarsFailed)
+	require.Equal(t, int64(2), stats.MetadataFailed)
+	require.True(t, stats.failed())
+	require.Equal(t, int64(2), stats.Requests)
+}
+
+// replayServer is a Remote Write endpoint which records the timestamps of the
+// received samples and exemplars, and the names of the received metadata.
+type replayServer struct {
+	url *url.URL
+
+	mut        sync.Mutex
+	samples    map[string][]int64
+	histograms map[string][]int64
+	exemplars  map[string][]int64
+	metadata   []string
+}
+
+// newReplayServer starts a replayServer which fails the first failures
+// requests with a recoverable error.
+func newReplayServer(t *testing.T, failures int64) *replayServer {
+	s := &replayServer{
+		samples:    make(map[string][]int64),
+		histograms: make(map[string][]int64),
+		exemplars:  make(map[string][]int64),
+	}
+	var remainingFailures atomic.Int64
+	remainingFailures.Store(failures)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if remainingFailures.Dec() >= 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(data, &req))
+
+		s.mut.Lock()
+		defer s.mut.Unlock()
+		for _, ts := range req.Timeseries {
+			lbls := labels.NewScratchBuilder(len(ts.Labels))
+			for _, l := range ts.Labels {
+				lbls.Add(l.Name, l.Value)
+			}
+			key := lbls.Labels().String()
+			for _, sample := range ts.Samples {
+				s.samples[key] = append(s.samples[key], sample.Timestamp)
+			}
+			for _, h := range ts.Histograms {
+				s.histograms[key] = append(s.histograms[key], h.Timestamp)
+			}
+			for _, e := range ts.Exemplars {
+				s.exemplars[key] = append(s.exemplars[key], e.Timestamp)
+			}
+		}
+		for _, m := range req.Metadata {
+			s.metadata = append(s.metadata, m.MetricFamilyName)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	s.url = u
+	return s
+}
+
+func (s *replayServer) sampleCount() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	var count int
+	for _, ts := range s.samples {
+		count += len(ts)
+	}
+	for _, ts := range s.histograms {
+		count += len(ts)
+	}
+	return count
+}
+
+// setupReplayWAL creates a WAL with two float series with samples at
+// timestamps 1 to 10, and a histogram series with samples at timestamps 1 to
+// 5. The first float series has exemplars at timestamps 2 and 7, and the
+// first float series and the histogram series have metadata. The samples of
+// the first half are in a checkpoint.
+func setupReplayWAL(t *testing.T) string {
+	walDir := filepath.Join(t.TempDir(), "wal")
+
+	w, err := wlog.NewSize(log.NewNopLogger(), prometheus.NewRegistry(), walDir, wlog.DefaultSegmentSize, wlog.CompressionSnappy)
+	require.NoError(t, err)
+
+	var enc record.Encoder
+	series := []record.RefSeries{
+		{Ref: 1, Labels: labels.FromStrings("__name__", "metric_a", "job", "test")},
+		{Ref: 2, Labels: labels.FromStrings("__name__", "metric_b", "job", "test")},
+		{Ref: 3, Labels: labels.FromStrings("__name__", "metric_h", "job", "test")},
+	}
+	require.NoError(t, w.Log(enc.Series(series, nil)))
+	require.NoError(t, w.Log(enc.Metadata([]record.RefMetadata{
+		{Ref: 1, Type: record.GetMetricType(model.MetricTypeCounter), Help: "A counter."},
+		{Ref: 3, Type: record.GetMetricType(model.MetricTypeHistogram), Help: "A histogram."},
+	}, nil)))
+
+	writeExemplar := func(ts int64) {
+		require.NoError(t, w.Log(enc.Exemplars([]record.RefExemplar{
+			{Ref: 1, T: ts, V: float64(ts), Labels: labels.FromStrings("trace_id", "abc")},
+		}, nil)))
+	}
+
+	writeSamples := func(from, to int64) {
+		var samples []record.RefSample
+		for ts := from; ts <= to; ts++ {
+			samples = append(samples,
+				record.RefSample{Ref: 1, T: ts, V: float64(ts)},
+				record.RefSample{Ref: 2, T: ts, V: float64(ts)},
+			)
+		}
+		require.NoError(t, w.Log(enc.Samples(samples, nil)))
+	}
+
+	writeSamples(1, 5)
+	writeExemplar(2)
+	var histograms []record.RefHistogramSample
+	for ts := int64(1); ts <= 5; ts++ {
+		histograms = append(histograms, record.RefHistogramSample{
+			Ref: chunks.HeadSeriesRef(3),
+			T:   ts,
+			H:   tsdbutil.GenerateTestHistogram(int(ts)),
+		})
+	}
+	require.NoError(t, w.Log(enc.HistogramSamples(histograms, nil)))
+
+	_, err = w.NextSegment()
+	require.NoError(t, err)
+	_, err = wlog.Checkpoint(log.NewNopLogger(), w, 0, 0, func(chunks.HeadSeriesRef) bool { return true }, 0)
+	require.NoError(t, err)
+	require.NoError(t, w.Truncate(1))
+
+	writeSamples(6, 10)
+	writeExemplar(7)
+	require.NoError(t, w.Close())
+
+	return walDir
+}
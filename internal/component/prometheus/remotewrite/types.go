@@ -77,6 +77,7 @@ type EndpointOptions struct {
 	Headers              map[string]string       `alloy:"headers,attr,optional"`
 	SendExemplars        bool                    `alloy:"send_exemplars,attr,optional"`
 	SendNativeHistograms bool                    `alloy:"send_native_histograms,attr,optional"`
+	ProtobufMessage      string                  `alloy:"protobuf_message,attr,optional"`
 	HTTPClientConfig     *types.HTTPClientConfig `alloy:",squash"`
 	QueueOptions         *QueueOptions           `alloy:"queue_config,block,optional"`
 	MetadataOptions      *MetadataOptions        `alloy:"metadata_config,block,optional"`
@@ -90,6 +91,7 @@ func (r *EndpointOptions) SetToDefault() {
 	*r = EndpointOptions{
 		RemoteTimeout:    30 * time.Second,
 		SendExemplars:    true,
+		ProtobufMessage:  string(config.RemoteWriteProtoMsgV1),
 		HTTPClientConfig: types.CloneDefaultHTTPClientConfig(),
 	}
 }
@@ -111,6 +113,10 @@ func (r *EndpointOptions) Validate() error {
 		}
 	}
 
+	if err := config.RemoteWriteProtoMsg(r.ProtobufMessage).Validate(); err != nil {
+		return fmt.Errorf("invalid protobuf_message: %w", err)
+	}
+
 	if r.SigV4 != nil {
 		if r.AzureAD != nil || isAuthSetInHttpClientConfig(r.HTTPClientConfig) {
 			return errTooManyAuth
@@ -243,9 +249,7 @@ func convertConfigs(cfg Arguments) (*config.Config, error) {
 			Name:                 rw.Name,
 			SendExemplars:        rw.SendExemplars,
 			SendNativeHistograms: rw.SendNativeHistograms,
-
-			//TODO: Make this configurable when we upgrade to Prometheus v3?
-			ProtobufMessage: config.RemoteWriteProtoMsgV1,
+			ProtobufMessage:      config.RemoteWriteProtoMsg(rw.ProtobufMessage),
 
 			WriteRelabelConfigs: alloy_relabel.ComponentToPromRelabelConfigs(rw.WriteRelabelConfigs),
 			HTTPClientConfig:    *rw.HTTPClientConfig.Convert(),
@@ -0,0 +1,144 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+	promrules "github.com/prometheus/prometheus/rules"
+	"gopkg.in/yaml.v3"
+)
+
+// inlineSource is the source of the rule groups defined in the component
+// arguments.
+const inlineSource = "inline"
+
+// RuleGroup is a group of recording rules evaluated together.
+type RuleGroup struct {
+	Name     string        `alloy:"name,attr"`
+	Interval time.Duration `alloy:"interval,attr,optional"`
+	Limit    int           `alloy:"limit,attr,optional"`
+	Rules    []Rule        `alloy:"rule,block"`
+}
+
+// Rule is a recording rule.
+type Rule struct {
+	Record string            `alloy:"record,attr"`
+	Expr   string            `alloy:"expr,attr"`
+	Labels map[string]string `alloy:"labels,attr,optional"`
+}
+
+// ruleGroupsFile is the rule file representation of RuleGroups, which is
+// parsed by rulefmt to validate the rule groups the same way as Prometheus.
+type ruleGroupsFile struct {
+	Groups []ruleGroupFile `yaml:"groups"`
+}
+
+type ruleGroupFile struct {
+	Name     string         `yaml:"name"`
+	Interval model.Duration `yaml:"interval,omitempty"`
+	Limit    int            `yaml:"limit,omitempty"`
+	Rules    []rulefmt.Rule `yaml:"rules"`
+}
+
+// convertRuleGroups converts the rule groups defined in the component
+// arguments to Prometheus rule groups.
+func convertRuleGroups(groups []RuleGroup) ([]rulefmt.RuleGroup, error) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	file := ruleGroupsFile{Groups: make([]ruleGroupFile, 0, len(groups))}
+	for _, g := range groups {
+		rules := make([]rulefmt.Rule, 0, len(g.Rules))
+		for _, r := range g.Rules {
+			rules = append(rules, rulefmt.Rule{
+				Record: r.Record,
+				Expr:   r.Expr,
+				Labels: r.Labels,
+			})
+		}
+		file.Groups = append(file.Groups, ruleGroupFile{
+			Name:     g.Name,
+			Interval: model.Duration(g.Interval),
+			Limit:    g.Limit,
+			Rules:    rules,
+		})
+	}
+
+	buf, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	parsed, errs := rulefmt.Parse(buf)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid rule groups: %w", errs[0])
+	}
+	return parsed.Groups, nil
+}
+
+// recordingRulesOnly returns the rule groups without their alerting rules.
+// Groups left without any rule are removed.
+func recordingRulesOnly(groups []rulefmt.RuleGroup) []rulefmt.RuleGroup {
+	res := make([]rulefmt.RuleGroup, 0, len(groups))
+	for _, g := range groups {
+		g.Rules = slices.DeleteFunc(slices.Clone(g.Rules), func(r rulefmt.RuleNode) bool {
+			return r.Alert.Value != ""
+		})
+		if len(g.Rules) > 0 {
+			res = append(res, g)
+		}
+	}
+	return res
+}
+
+// groupLoader implements the Prometheus rules GroupLoader to load the rule
+// groups from memory instead of files. The identifiers given to the rules
+// manager are the sources of the rule groups.
+type groupLoader struct {
+	mut    sync.RWMutex
+	groups map[string][]rulefmt.RuleGroup
+}
+
+var _ promrules.GroupLoader = (*groupLoader)(nil)
+
+// set replaces the rule groups of every source.
+func (l *groupLoader) set(groups map[string][]rulefmt.RuleGroup) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+	l.groups = groups
+}
+
+// sources returns the sources of the rule groups in a stable order.
+func (l *groupLoader) sources() []string {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	res := make([]string, 0, len(l.groups))
+	for source := range l.groups {
+		res = append(res, source)
+	}
+	slices.Sort(res)
+	return res
+}
+
+// Load implements promrules.GroupLoader.
+func (l *groupLoader) Load(identifier string) (*rulefmt.RuleGroups, []error) {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	groups, ok := l.groups[identifier]
+	if !ok {
+		return nil, []error{fmt.Errorf("unknown rule source %q", identifier)}
+	}
+	return &rulefmt.RuleGroups{Groups: groups}, nil
+}
+
+// Parse implements promrules.GroupLoader.
+func (l *groupLoader) Parse(query string) (parser.Expr, error) {
+	return parser.ParseExpr(query)
+}
@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/hashicorp/go-multierror"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promExternalVersions "github.com/prometheus-operator/prometheus-operator/pkg/client/informers/externalversions"
+	promListers "github.com/prometheus-operator/prometheus-operator/pkg/client/listers/monitoring/v1"
+	promVersioned "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	coreListers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml" // Used for CRD compatibility instead of gopkg.in/yaml.v2
+
+	commonK8s "github.com/grafana/alloy/internal/component/common/kubernetes"
+)
+
+// KubernetesArguments configures how PrometheusRule resources are discovered.
+type KubernetesArguments struct {
+	Client                commonK8s.ClientArguments `alloy:"client,block,optional"`
+	RuleSelector          commonK8s.LabelSelector   `alloy:"rule_selector,block,optional"`
+	RuleNamespaceSelector commonK8s.LabelSelector   `alloy:"rule_namespace_selector,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *KubernetesArguments) SetToDefault() {
+	*args = KubernetesArguments{
+		Client: commonK8s.DefaultClientArguments,
+	}
+}
+
+// crdWatcher watches the PrometheusRule resources matching the selectors.
+type crdWatcher struct {
+	cancel context.CancelFunc
+
+	namespaceLister   coreListers.NamespaceLister
+	ruleLister        promListers.PrometheusRuleLister
+	namespaceSelector labels.Selector
+	ruleSelector      labels.Selector
+}
+
+// startCRDWatcher starts the informers of the namespaces and PrometheusRule
+// resources and waits for their caches to be synced. onChange is called every
+// time one of the watched resources changes.
+func startCRDWatcher(ctx context.Context, logger log.Logger, args KubernetesArguments, onChange func()) (*crdWatcher, error) {
+	namespaceSelector, err := commonK8s.ConvertSelectorToListOptions(args.RuleNamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	ruleSelector, err := commonK8s.ConvertSelectorToListOptions(args.RuleSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := args.Client.BuildRESTConfig(logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s config: %w", err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s client: %w", err)
+	}
+	promClient, err := promVersioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus operator client: %w", err)
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(_ interface{}) { onChange() },
+		UpdateFunc: func(_, _ interface{}) { onChange() },
+		DeleteFunc: func(_ interface{}) { onChange() },
+	}
+
+	namespaceFactory := informers.NewSharedInformerFactoryWithOptions(
+		k8sClient,
+		24*time.Hour,
+		informers.WithTweakListOptions(func(lo *metav1.ListOptions) {
+			lo.LabelSelector = namespaceSelector.String()
+		}),
+	)
+	namespaces := namespaceFactory.Core().V1().Namespaces()
+	if _, err := namespaces.Informer().AddEventHandler(handler); err != nil {
+		return nil, err
+	}
+
+	ruleFactory := promExternalVersions.NewSharedInformerFactoryWithOptions(
+		promClient,
+		24*time.Hour,
+		promExternalVersions.WithTweakListOptions(func(lo *metav1.ListOptions) {
+			lo.LabelSelector = ruleSelector.String()
+		}),
+	)
+	promRules := ruleFactory.Monitoring().V1().PrometheusRules()
+	if _, err := promRules.Informer().AddEventHandler(handler); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &crdWatcher{
+		cancel:            cancel,
+		namespaceLister:   namespaces.Lister(),
+		ruleLister:        promRules.Lister(),
+		namespaceSelector: namespaceSelector,
+		ruleSelector:      ruleSelector,
+	}
+
+	namespaceFactory.Start(ctx.Done())
+	ruleFactory.Start(ctx.Done())
+	namespaceFactory.WaitForCacheSync(ctx.Done())
+	ruleFactory.WaitForCacheSync(ctx.Done())
+	if err := ctx.Err(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return w, nil
+}
+
+// stop stops the informers.
+func (w *crdWatcher) stop() {
+	w.cancel()
+}
+
+// ruleGroups returns the recording rule groups of the watched PrometheusRule
+// resources, keyed by their source. Resources which can't be parsed are
+// skipped and reported in the returned error.
+func (w *crdWatcher) ruleGroups() (map[string][]rulefmt.RuleGroup, error) {
+	namespaces, err := w.namespaceLister.List(w.namespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	var errs error
+	res := make(map[string][]rulefmt.RuleGroup)
+	for _, namespace := range namespaces {
+		crds, err := w.ruleLister.PrometheusRules(namespace.Name).List(w.ruleSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list rules: %w", err)
+		}
+
+		for _, crd := range crds {
+			groups, err := convertCRDRuleGroupToRuleGroup(crd.Spec)
+			if err != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to parse PrometheusRule %s/%s: %w", crd.Namespace, crd.Name, err))
+				continue
+			}
+			if groups = recordingRulesOnly(groups); len(groups) > 0 {
+				res[sourceForRuleCRD(crd)] = groups
+			}
+		}
+	}
+	return res, errs
+}
+
+func convertCRDRuleGroupToRuleGroup(crd promv1.PrometheusRuleSpec) ([]rulefmt.RuleGroup, error) {
+	buf, err := yaml.Marshal(crd)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, errs := rulefmt.Parse(buf)
+	if len(errs) > 0 {
+		return nil, multierror.Append(nil, errs...)
+	}
+
+	return groups.Groups, nil
+}
+
+// sourceForRuleCRD returns the source of the rule groups of a rule CRD, which
+// keeps groups with the same name in different CRDs apart.
+func sourceForRuleCRD(pr *promv1.PrometheusRule) string {
+	return fmt.Sprintf("kubernetes/%s/%s", pr.Namespace, pr.Name)
+}
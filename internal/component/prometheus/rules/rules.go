@@ -0,0 +1,372 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql"
+	promrules "github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/storage"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
+)
+
+const name = "prometheus.rules"
+
+const (
+	// truncateInterval is how often the samples older than the retention are
+	// removed.
+	truncateInterval = time.Minute
+
+	// watchRetryInterval is how long to wait before retrying to watch
+	// PrometheusRule resources.
+	watchRetryInterval = 10 * time.Second
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      name,
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the prometheus.rules
+// component.
+type Arguments struct {
+	// Where the results of the recording rules should be forwarded to.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// The default evaluation interval of the rule groups.
+	EvaluationInterval time.Duration `alloy:"evaluation_interval,attr,optional"`
+
+	// How long the received samples are kept to evaluate the rules.
+	Retention time.Duration `alloy:"retention,attr,optional"`
+
+	RuleGroups []RuleGroup          `alloy:"rule_group,block,optional"`
+	Kubernetes *KubernetesArguments `alloy:"kubernetes,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (arg *Arguments) SetToDefault() {
+	*arg = Arguments{
+		EvaluationInterval: time.Minute,
+		Retention:          15 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (arg *Arguments) Validate() error {
+	if arg.EvaluationInterval <= 0 {
+		return fmt.Errorf("evaluation_interval must be greater than 0")
+	}
+	if arg.Retention <= 0 {
+		return fmt.Errorf("retention must be greater than 0")
+	}
+	for _, g := range arg.RuleGroups {
+		if g.Interval < 0 {
+			return fmt.Errorf("interval of rule group %q must not be negative", g.Name)
+		}
+	}
+	_, err := convertRuleGroups(arg.RuleGroups)
+	return err
+}
+
+// Exports holds values which are exported by the prometheus.rules component.
+type Exports struct {
+	Receiver storage.Appendable `alloy:"receiver,attr"`
+}
+
+// Component implements the prometheus.rules component.
+type Component struct {
+	opts     component.Options
+	receiver *prometheus.Interceptor
+	fanout   *prometheus.Fanout
+	storage  *headStorage
+	loader   *groupLoader
+	manager  *promrules.Manager
+	cancel   context.CancelFunc
+	exited   atomic.Bool
+	reload   chan struct{}
+
+	mut  sync.RWMutex
+	args Arguments
+
+	// watcher and watcherArgs are only accessed from Run.
+	watcher     *crdWatcher
+	watcherArgs *KubernetesArguments
+
+	healthMut sync.RWMutex
+	health    component.Health
+}
+
+var (
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+)
+
+// New creates a new prometheus.rules component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	data, err := o.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	ls := data.(labelstore.LabelStore)
+
+	head, err := newHeadStorage(log.With(o.Logger, "subcomponent", "head"), o.Registerer, filepath.Join(o.DataPath, "head"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create head: %w", err)
+	}
+
+	c := &Component{
+		opts:    o,
+		storage: head,
+		loader:  &groupLoader{},
+		reload:  make(chan struct{}, 1),
+	}
+
+	// The received samples are stored in the head, which assigns its own ref
+	// IDs. They are treated as local IDs and translated to global IDs like
+	// remote_write does. Exemplars and metadata aren't needed to evaluate rules
+	// and are dropped.
+	c.receiver = prometheus.NewInterceptor(
+		head,
+		ls,
+		prometheus.WithAppendHook(func(globalRef storage.SeriesRef, l labels.Labels, t int64, v float64, next storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			localID := ls.GetLocalRefID(o.ID, uint64(globalRef))
+			newRef, nextErr := next.Append(storage.SeriesRef(localID), l, t, v)
+			if newRef != 0 && localID != uint64(newRef) {
+				ls.GetOrAddLink(o.ID, uint64(newRef), l)
+			}
+			return globalRef, nextErr
+		}),
+		prometheus.WithHistogramHook(func(globalRef storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram, next storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			localID := ls.GetLocalRefID(o.ID, uint64(globalRef))
+			newRef, nextErr := next.AppendHistogram(storage.SeriesRef(localID), l, t, h, fh)
+			if newRef != 0 && localID != uint64(newRef) {
+				ls.GetOrAddLink(o.ID, uint64(newRef), l)
+			}
+			return globalRef, nextErr
+		}),
+		prometheus.WithExemplarHook(func(globalRef storage.SeriesRef, _ labels.Labels, _ exemplar.Exemplar, _ storage.Appender) (storage.SeriesRef, error) {
+			return globalRef, nil
+		}),
+		prometheus.WithMetadataHook(func(globalRef storage.SeriesRef, _ labels.Labels, _ metadata.Metadata, _ storage.Appender) (storage.SeriesRef, error) {
+			return globalRef, nil
+		}),
+		prometheus.WithCTZeroSampleHook(func(globalRef storage.SeriesRef, _ labels.Labels, _, _ int64, _ storage.Appender) (storage.SeriesRef, error) {
+			return globalRef, nil
+		}),
+	)
+
+	// The results of the rules are also written to the head so that rules can
+	// use the results of other rules.
+	c.fanout = prometheus.NewFanout(nil, o.ID, o.Registerer, ls)
+
+	engine := promql.NewEngine(promql.EngineOpts{
+		Logger:               log.With(o.Logger, "subcomponent", "engine"),
+		MaxSamples:           50000000,
+		Timeout:              2 * time.Minute,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.manager = promrules.NewManager(&promrules.ManagerOptions{
+		Appendable:  c.fanout,
+		Queryable:   head,
+		QueryFunc:   promrules.EngineQueryFunc(engine, head),
+		Context:     ctx,
+		Logger:      log.With(o.Logger, "subcomponent", "rules"),
+		Registerer:  o.Registerer,
+		GroupLoader: c.loader,
+	})
+
+	// Immediately export the receiver which remains the same for the component
+	// lifetime.
+	o.OnStateChange(Exports{Receiver: c.receiver})
+
+	if err = c.Update(args); err != nil {
+		cancel()
+		_ = head.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.exited.Store(true)
+		if c.watcher != nil {
+			c.watcher.stop()
+		}
+		c.manager.Stop()
+		c.cancel()
+		if err := c.storage.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to close head", "err", err)
+		}
+	}()
+
+	go c.manager.Run()
+
+	truncateTicker := time.NewTicker(truncateInterval)
+	defer truncateTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.reload:
+			c.reloadRules(ctx)
+		case <-truncateTicker.C:
+			mint := time.Now().Add(-c.retention()).UnixMilli()
+			if err := c.storage.truncate(mint); err != nil {
+				level.Error(c.opts.Logger).Log("msg", "failed to truncate head", "err", err)
+			}
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	c.mut.Lock()
+	c.args = newArgs
+	c.mut.Unlock()
+
+	c.fanout.UpdateChildren(append([]storage.Appendable{c.receiver}, newArgs.ForwardTo...))
+	c.triggerReload()
+	return nil
+}
+
+func (c *Component) retention() time.Duration {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	return c.args.Retention
+}
+
+// triggerReload schedules the rules to be reloaded by Run.
+func (c *Component) triggerReload() {
+	select {
+	case c.reload <- struct{}{}:
+	default: // reload already scheduled
+	}
+}
+
+// reloadRules restarts the CRD watcher if its arguments changed, and applies
+// the current rule groups to the rules manager.
+func (c *Component) reloadRules(ctx context.Context) {
+	c.mut.RLock()
+	args := c.args
+	c.mut.RUnlock()
+
+	var crdErr error
+	if c.watcher == nil || !reflect.DeepEqual(args.Kubernetes, c.watcherArgs) {
+		if c.watcher != nil {
+			c.watcher.stop()
+			c.watcher = nil
+		}
+		c.watcherArgs = args.Kubernetes
+
+		if args.Kubernetes != nil {
+			watcher, err := startCRDWatcher(ctx, c.opts.Logger, *args.Kubernetes, c.triggerReload)
+			if err != nil {
+				// The inline rule groups are still applied while the watcher is
+				// retried.
+				crdErr = fmt.Errorf("failed to watch PrometheusRule resources: %w", err)
+				time.AfterFunc(watchRetryInterval, c.triggerReload)
+			}
+			c.watcher = watcher
+		}
+	}
+
+	groups := make(map[string][]rulefmt.RuleGroup)
+	inlineGroups, err := convertRuleGroups(args.RuleGroups)
+	if err != nil {
+		// The arguments were validated, so this is unexpected.
+		c.reportUnhealthy(err)
+		return
+	}
+	if len(inlineGroups) > 0 {
+		groups[inlineSource] = inlineGroups
+	}
+
+	if c.watcher != nil {
+		var crdGroups map[string][]rulefmt.RuleGroup
+		crdGroups, crdErr = c.watcher.ruleGroups()
+		for source, g := range crdGroups {
+			groups[source] = g
+		}
+	}
+
+	c.loader.set(groups)
+	err = c.manager.Update(args.EvaluationInterval, c.loader.sources(), labels.EmptyLabels(), "", nil)
+	switch {
+	case err != nil:
+		level.Error(c.opts.Logger).Log("msg", "failed to update rules", "err", err)
+		c.reportUnhealthy(fmt.Errorf("failed to update rules: %w", err))
+	case crdErr != nil:
+		level.Error(c.opts.Logger).Log("msg", "failed to load PrometheusRule resources", "err", crdErr)
+		c.reportUnhealthy(crdErr)
+	default:
+		c.reportHealthy()
+	}
+}
+
+func (c *Component) reportUnhealthy(err error) {
+	c.healthMut.Lock()
+	defer c.healthMut.Unlock()
+	c.health = component.Health{
+		Health:     component.HealthTypeUnhealthy,
+		Message:    err.Error(),
+		UpdateTime: time.Now(),
+	}
+}
+
+func (c *Component) reportHealthy() {
+	c.healthMut.Lock()
+	defer c.healthMut.Unlock()
+	c.health = component.Health{
+		Health:     component.HealthTypeHealthy,
+		UpdateTime: time.Now(),
+	}
+}
+
+// CurrentHealth implements component.HealthComponent.
+func (c *Component) CurrentHealth() component.Health {
+	c.healthMut.RLock()
+	defer c.healthMut.RUnlock()
+	return c.health
+}
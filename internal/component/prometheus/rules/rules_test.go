@@ -0,0 +1,369 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promListers "github.com/prometheus-operator/prometheus-operator/pkg/client/listers/monitoring/v1"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	coreListers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	// Alloy validates metric names with the legacy scheme unless
+	// --feature.prometheus.metric-validation-scheme says otherwise.
+	scheme := model.NameValidationScheme
+	model.NameValidationScheme = model.LegacyValidation
+	t.Cleanup(func() { model.NameValidationScheme = scheme })
+
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "inline rules",
+			cfg: `
+				forward_to = []
+
+				rule_group {
+					name     = "http"
+					interval = "30s"
+
+					rule {
+						record = "job:http_requests:rate5m"
+						expr   = "sum by (job) (rate(http_requests_total[5m]))"
+						labels = {
+							source = "alloy",
+						}
+					}
+				}
+			`,
+		},
+		{
+			name: "kubernetes",
+			cfg: `
+				forward_to = []
+
+				kubernetes {
+					rule_selector {
+						match_labels = {
+							team = "a",
+						}
+					}
+				}
+			`,
+		},
+		{
+			name: "invalid retention",
+			cfg: `
+				forward_to = []
+				retention  = "0s"
+			`,
+			expectedErr: "retention must be greater than 0",
+		},
+		{
+			name: "invalid record name",
+			cfg: `
+				forward_to = []
+
+				rule_group {
+					name = "http"
+
+					rule {
+						record = "job-http"
+						expr   = "sum(http_requests_total)"
+					}
+				}
+			`,
+			expectedErr: "invalid recording rule name",
+		},
+		{
+			name: "invalid expression",
+			cfg: `
+				forward_to = []
+
+				rule_group {
+					name = "http"
+
+					rule {
+						record = "job:http_requests:sum"
+						expr   = "sum(http_requests_total"
+					}
+				}
+			`,
+			expectedErr: "could not parse expression",
+		},
+		{
+			name: "duplicate group names",
+			cfg: `
+				forward_to = []
+
+				rule_group {
+					name = "http"
+
+					rule {
+						record = "job:http_requests:sum"
+						expr   = "sum(http_requests_total)"
+					}
+				}
+
+				rule_group {
+					name = "http"
+
+					rule {
+						record = "job:http_requests:count"
+						expr   = "count(http_requests_total)"
+					}
+				}
+			`,
+			expectedErr: "repeated in the same file",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestConvertRuleGroups(t *testing.T) {
+	groups, err := convertRuleGroups([]RuleGroup{{
+		Name:     "http",
+		Interval: 30 * time.Second,
+		Rules: []Rule{{
+			Record: "job:http_requests:sum",
+			Expr:   "sum by (job) (http_requests_total)",
+			Labels: map[string]string{"source": "alloy"},
+		}},
+	}})
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Equal(t, "http", groups[0].Name)
+	require.Equal(t, 30*time.Second, time.Duration(groups[0].Interval))
+	require.Len(t, groups[0].Rules, 1)
+	require.Equal(t, "job:http_requests:sum", groups[0].Rules[0].Record.Value)
+	require.Equal(t, "sum by (job) (http_requests_total)", groups[0].Rules[0].Expr.Value)
+	require.Equal(t, map[string]string{"source": "alloy"}, groups[0].Rules[0].Labels)
+}
+
+func TestCRDWatcher_RuleGroups(t *testing.T) {
+	nsIndexer := cache.NewIndexer(cache.DeletionHandlingMetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	ruleIndexer := cache.NewIndexer(cache.DeletionHandlingMetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+
+	require.NoError(t, nsIndexer.Add(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace"},
+	}))
+	require.NoError(t, ruleIndexer.Add(&promv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "mixed", Namespace: "namespace"},
+		Spec: promv1.PrometheusRuleSpec{
+			Groups: []promv1.RuleGroup{
+				{
+					Name: "group",
+					Rules: []promv1.Rule{
+						{Record: "job:up:sum", Expr: intstr.FromString("sum by (job) (up)")},
+						{Alert: "InstanceDown", Expr: intstr.FromString("up == 0")},
+					},
+				},
+				{
+					Name: "alerts",
+					Rules: []promv1.Rule{
+						{Alert: "InstanceDown", Expr: intstr.FromString("up == 0")},
+					},
+				},
+			},
+		},
+	}))
+	require.NoError(t, ruleIndexer.Add(&promv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "namespace"},
+		Spec: promv1.PrometheusRuleSpec{
+			Groups: []promv1.RuleGroup{{
+				Name: "alerts",
+				Rules: []promv1.Rule{
+					{Alert: "InstanceDown", Expr: intstr.FromString("up == 0")},
+				},
+			}},
+		},
+	}))
+	require.NoError(t, ruleIndexer.Add(&promv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "namespace"},
+		Spec: promv1.PrometheusRuleSpec{
+			Groups: []promv1.RuleGroup{{
+				Name: "invalid",
+				Rules: []promv1.Rule{
+					{Record: "job:up:sum", Expr: intstr.FromString("sum(")},
+				},
+			}},
+		},
+	}))
+
+	w := &crdWatcher{
+		namespaceLister:   coreListers.NewNamespaceLister(nsIndexer),
+		ruleLister:        promListers.NewPrometheusRuleLister(ruleIndexer),
+		namespaceSelector: k8slabels.Everything(),
+		ruleSelector:      k8slabels.Everything(),
+	}
+
+	groups, err := w.ruleGroups()
+	require.ErrorContains(t, err, "failed to parse PrometheusRule namespace/invalid")
+
+	// Only the recording rules are kept, and rule CRDs without recording rules
+	// are skipped.
+	require.Len(t, groups, 1)
+	mixed := groups["kubernetes/namespace/mixed"]
+	require.Len(t, mixed, 1)
+	require.Equal(t, "group", mixed[0].Name)
+	require.Len(t, mixed[0].Rules, 1)
+	require.Equal(t, "job:up:sum", mixed[0].Rules[0].Record.Value)
+}
+
+func TestGroupLoader(t *testing.T) {
+	groups, err := convertRuleGroups([]RuleGroup{{
+		Name:  "http",
+		Rules: []Rule{{Record: "job:http_requests:sum", Expr: "sum(http_requests_total)"}},
+	}})
+	require.NoError(t, err)
+
+	var l groupLoader
+	l.set(map[string][]rulefmt.RuleGroup{
+		inlineSource:                 groups,
+		"kubernetes/namespace/rules": groups,
+	})
+	require.Equal(t, []string{inlineSource, "kubernetes/namespace/rules"}, l.sources())
+
+	loaded, errs := l.Load(inlineSource)
+	require.Empty(t, errs)
+	require.Equal(t, groups, loaded.Groups)
+
+	_, errs = l.Load("unknown")
+	require.NotEmpty(t, errs)
+}
+
+func TestComponent(t *testing.T) {
+	ls := labelstore.New(nil, prom.DefaultRegisterer)
+	app := newCollectingAppender()
+	output := prometheus.NewInterceptor(nil, ls,
+		prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, t int64, v float64, _ storage.Appender) (storage.SeriesRef, error) {
+			return app.Append(ref, l, t, v)
+		}),
+	)
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		forward_to = []
+
+		rule_group {
+			name     = "http"
+			interval = "100ms"
+
+			rule {
+				record = "job:http_requests:sum"
+				expr   = "sum by (job) (http_requests_total)"
+			}
+
+			rule {
+				record = "job:http_requests:double"
+				expr   = "job:http_requests:sum * 2"
+			}
+		}
+	`), &args))
+	args.ForwardTo = []storage.Appendable{output}
+
+	var exports Exports
+	c, err := New(component.Options{
+		ID:             "prometheus.rules.test",
+		Logger:         util.TestAlloyLogger(t),
+		DataPath:       t.TempDir(),
+		OnStateChange:  func(e component.Exports) { exports = e.(Exports) },
+		Registerer:     prom.NewRegistry(),
+		GetServiceData: getServiceData,
+	}, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = c.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	ts := time.Now().Add(-time.Second).UnixMilli()
+	in := exports.Receiver.Appender(ctx)
+	_, err = in.Append(0, labels.FromStrings("__name__", "http_requests_total", "job", "api", "instance", "a"), ts, 10)
+	require.NoError(t, err)
+	_, err = in.Append(0, labels.FromStrings("__name__", "http_requests_total", "job", "api", "instance", "b"), ts, 5)
+	require.NoError(t, err)
+	require.NoError(t, in.Commit())
+
+	require.Eventually(t, func() bool {
+		sum, okSum := app.get(`{__name__="job:http_requests:sum", job="api"}`)
+		double, okDouble := app.get(`{__name__="job:http_requests:double", job="api"}`)
+		return okSum && okDouble && sum == 15 && double == 30
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+// collectingAppender records the latest value written for each series.
+type collectingAppender struct {
+	storage.Appender
+
+	mut    sync.Mutex
+	floats map[string]float64
+}
+
+func newCollectingAppender() *collectingAppender {
+	return &collectingAppender{floats: make(map[string]float64)}
+}
+
+func (c *collectingAppender) Append(ref storage.SeriesRef, l labels.Labels, _ int64, v float64) (storage.SeriesRef, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.floats[l.String()] = v
+	return ref, nil
+}
+
+func (c *collectingAppender) get(series string) (float64, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	v, ok := c.floats[series]
+	return v, ok
+}
+
+func getServiceData(name string) (interface{}, error) {
+	switch name {
+	case labelstore.ServiceName:
+		return labelstore.New(nil, prom.DefaultRegisterer), nil
+	default:
+		return nil, fmt.Errorf("service not found %s", name)
+	}
+}
@@ -0,0 +1,64 @@
+package rules
+
+import (
+	"context"
+	"math"
+	"os"
+
+	"github.com/go-kit/log"
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+// headStorage keeps the samples received by the component in a TSDB head,
+// which is used to evaluate the rules. The head has no WAL and is regularly
+// truncated so that only recent samples are kept.
+type headStorage struct {
+	head *tsdb.Head
+}
+
+var (
+	_ storage.Appendable = (*headStorage)(nil)
+	_ storage.Queryable  = (*headStorage)(nil)
+)
+
+func newHeadStorage(logger log.Logger, reg prometheus_client.Registerer, dir string) (*headStorage, error) {
+	// Chunks left over by a previous run can't be replayed without a WAL.
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+
+	opts := tsdb.DefaultHeadOptions()
+	opts.ChunkDirRoot = dir
+	opts.EnableNativeHistograms.Store(true)
+
+	head, err := tsdb.NewHead(reg, logger, nil, nil, opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := head.Init(math.MinInt64); err != nil {
+		_ = head.Close()
+		return nil, err
+	}
+	return &headStorage{head: head}, nil
+}
+
+// Appender implements storage.Appendable.
+func (s *headStorage) Appender(ctx context.Context) storage.Appender {
+	return s.head.Appender(ctx)
+}
+
+// Querier implements storage.Queryable.
+func (s *headStorage) Querier(mint, maxt int64) (storage.Querier, error) {
+	return tsdb.NewBlockQuerier(tsdb.NewRangeHead(s.head, mint, maxt), mint, maxt)
+}
+
+// truncate removes the samples older than mint.
+func (s *headStorage) truncate(mint int64) error {
+	return s.head.Truncate(mint)
+}
+
+func (s *headStorage) Close() error {
+	return s.head.Close()
+}
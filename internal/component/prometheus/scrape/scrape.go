@@ -546,6 +546,7 @@ func (c *Component) newInterceptor(ls labelstore.LabelStore) *prometheus.Interce
 				func() string {
 					return fmt.Sprintf("sample: ts=%d, labels=%s, value=%f", t, l, v)
 				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
@@ -566,6 +567,7 @@ func (c *Component) newInterceptor(ls labelstore.LabelStore) *prometheus.Interce
 					}
 					return data
 				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
@@ -578,6 +580,7 @@ func (c *Component) newInterceptor(ls labelstore.LabelStore) *prometheus.Interce
 				func() string {
 					return fmt.Sprintf("metadata: labels=%s, type=%q, unit=%q, help=%q", l, m.Type, m.Unit, m.Help)
 				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
@@ -590,6 +593,7 @@ func (c *Component) newInterceptor(ls labelstore.LabelStore) *prometheus.Interce
 				func() string {
 					return fmt.Sprintf("exemplar: ts=%d, labels=%s, exemplar_labels=%s, value=%f", e.Ts, l, e.Labels, e.Value)
 				},
+				livedebugging.WithPrometheusLabels(l),
 			))
 			return globalRef, nextErr
 		}),
@@ -0,0 +1,105 @@
+package pyroscope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/pprof/profile"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/alloy/internal/service/livedebugging"
+)
+
+// ProfileDebugString describes a profile for live debugging: its labels,
+// profile type, number of samples and size. The profile type and the number
+// of samples are only known for profiles in the pprof format.
+func ProfileDebugString(lbls labels.Labels, raw []byte) string {
+	p, err := profile.ParseData(raw)
+	if err != nil {
+		return fmt.Sprintf("profile: labels=%s, size=%d bytes", lbls, len(raw))
+	}
+
+	sampleTypes := make([]string, 0, len(p.SampleType))
+	for _, st := range p.SampleType {
+		sampleTypes = append(sampleTypes, st.Type+":"+st.Unit)
+	}
+	return fmt.Sprintf("profile: labels=%s, type=[%s], samples=%d, size=%d bytes", lbls, strings.Join(sampleTypes, ", "), len(p.Sample), len(raw))
+}
+
+// PublishProfiles publishes samples appended with lbls to live debugging.
+// Each raw sample is a separate profile.
+func PublishProfiles(publisher livedebugging.DebugDataPublisher, componentID livedebugging.ComponentID, lbls labels.Labels, samples []*RawSample) {
+	for _, sample := range samples {
+		publisher.PublishIfActive(livedebugging.NewData(
+			componentID,
+			livedebugging.Profile,
+			1,
+			func() string {
+				return ProfileDebugString(lbls, sample.RawProfile)
+			},
+			livedebugging.WithPrometheusLabels(lbls),
+		))
+	}
+}
+
+// PublishIncomingProfile publishes a profile appended with AppendIngest to
+// live debugging.
+func PublishIncomingProfile(publisher livedebugging.DebugDataPublisher, componentID livedebugging.ComponentID, p *IncomingProfile) {
+	publisher.PublishIfActive(livedebugging.NewData(
+		componentID,
+		livedebugging.Profile,
+		1,
+		func() string {
+			return ProfileDebugString(p.Labels, p.RawBody)
+		},
+		livedebugging.WithPrometheusLabels(p.Labels),
+	))
+}
+
+var _ Appendable = (*liveDebuggingAppendable)(nil)
+
+// liveDebuggingAppendable publishes every profile appended to it to live
+// debugging before forwarding it.
+type liveDebuggingAppendable struct {
+	next        Appendable
+	componentID livedebugging.ComponentID
+	publisher   livedebugging.DebugDataPublisher
+}
+
+// NewLiveDebuggingAppendable wraps next so that every profile appended to it
+// is published to live debugging under componentID.
+func NewLiveDebuggingAppendable(next Appendable, componentID livedebugging.ComponentID, publisher livedebugging.DebugDataPublisher) Appendable {
+	return &liveDebuggingAppendable{
+		next:        next,
+		componentID: componentID,
+		publisher:   publisher,
+	}
+}
+
+// Appender satisfies the Appendable interface.
+func (a *liveDebuggingAppendable) Appender() Appender {
+	return &liveDebuggingAppender{
+		next:        a.next.Appender(),
+		componentID: a.componentID,
+		publisher:   a.publisher,
+	}
+}
+
+type liveDebuggingAppender struct {
+	next        Appender
+	componentID livedebugging.ComponentID
+	publisher   livedebugging.DebugDataPublisher
+}
+
+// Append satisfies the Appender interface.
+func (a *liveDebuggingAppender) Append(ctx context.Context, lbls labels.Labels, samples []*RawSample) error {
+	PublishProfiles(a.publisher, a.componentID, lbls, samples)
+	return a.next.Append(ctx, lbls, samples)
+}
+
+// AppendIngest satisfies the Appender interface.
+func (a *liveDebuggingAppender) AppendIngest(ctx context.Context, profile *IncomingProfile) error {
+	PublishIncomingProfile(a.publisher, a.componentID, profile)
+	return a.next.AppendIngest(ctx, profile)
+}
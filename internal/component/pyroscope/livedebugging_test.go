@@ -0,0 +1,39 @@
+package pyroscope
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileDebugString(t *testing.T) {
+	lbls := labels.FromStrings(LabelName, "process_cpu", LabelServiceName, "app")
+
+	fn := &profile.Function{ID: 1, Name: "main"}
+	loc := &profile.Location{ID: 1, Line: []profile.Line{{Function: fn}}}
+	p := &profile.Profile{
+		SampleType: []*profile.ValueType{
+			{Type: "samples", Unit: "count"},
+			{Type: "cpu", Unit: "nanoseconds"},
+		},
+		Sample: []*profile.Sample{
+			{Location: []*profile.Location{loc}, Value: []int64{1, 10}},
+			{Location: []*profile.Location{loc}, Value: []int64{2, 20}},
+		},
+		Location: []*profile.Location{loc},
+		Function: []*profile.Function{fn},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+	raw := buf.Bytes()
+
+	expected := fmt.Sprintf(`profile: labels={__name__="process_cpu", service_name="app"}, type=[samples:count, cpu:nanoseconds], samples=2, size=%d bytes`, len(raw))
+	require.Equal(t, expected, ProfileDebugString(lbls, raw))
+
+	// Profiles in other formats only show the labels and size.
+	require.Equal(t, `profile: labels={__name__="process_cpu", service_name="app"}, size=3 bytes`, ProfileDebugString(lbls, []byte("jfr")))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/alloy/internal/component/pyroscope/write"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util"
 	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
@@ -66,9 +67,21 @@ type Component struct {
 	uncheckedCollector *util.UncheckedCollector
 	appendables        []pyroscope.Appendable
 	mut                sync.Mutex
+
+	debugDataPublisher livedebugging.DebugDataPublisher
 }
 
+var (
+	_ component.Component     = (*Component)(nil)
+	_ component.LiveDebugging = (*Component)(nil)
+)
+
 func New(opts component.Options, args Arguments) (*Component, error) {
+	debugDataPublisher, err := opts.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
 	uncheckedCollector := util.NewUncheckedCollector(nil)
 	opts.Registerer.MustRegister(uncheckedCollector)
 
@@ -76,6 +89,7 @@ func New(opts component.Options, args Arguments) (*Component, error) {
 		opts:               opts,
 		uncheckedCollector: uncheckedCollector,
 		appendables:        args.ForwardTo,
+		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
 	}
 
 	if err := c.Update(args); err != nil {
@@ -174,6 +188,7 @@ func (c *Component) Push(ctx context.Context, req *connect.Request[pushv1.PushRe
 ) (*connect.Response[pushv1.PushResponse], error) {
 
 	appendables := c.getAppendables()
+	c.publishPushRequest(req.Msg)
 
 	var wg sync.WaitGroup
 	var errs error
@@ -213,6 +228,31 @@ func (c *Component) Push(ctx context.Context, req *connect.Request[pushv1.PushRe
 	return connect.NewResponse(&pushv1.PushResponse{}), nil
 }
 
+// publishPushRequest publishes the profiles of a push request to live debugging.
+func (c *Component) publishPushRequest(req *pushv1.PushRequest) {
+	componentID := livedebugging.ComponentID(c.opts.ID)
+	for _, series := range req.Series {
+		seriesLabels := func() labels.Labels {
+			lb := labels.NewBuilder(nil)
+			setLabelBuilderFromAPI(lb, series.Labels)
+			return ensureServiceName(lb.Labels())
+		}
+		for _, sample := range series.Samples {
+			c.debugDataPublisher.PublishIfActive(livedebugging.NewData(
+				componentID,
+				livedebugging.Profile,
+				1,
+				func() string {
+					return pyroscope.ProfileDebugString(seriesLabels(), sample.RawProfile)
+				},
+				livedebugging.WithLabels(func() []map[string]string {
+					return []map[string]string{seriesLabels().Map()}
+				}),
+			))
+		}
+	}
+}
+
 func (c *Component) getAppendables() []pyroscope.Appendable {
 	c.mut.Lock()
 	defer c.mut.Unlock()
@@ -256,6 +296,13 @@ func (c *Component) handleIngest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	pyroscope.PublishIncomingProfile(c.debugDataPublisher, livedebugging.ComponentID(c.opts.ID), &pyroscope.IncomingProfile{
+		RawBody:     buf.Bytes(),
+		ContentType: r.Header.Values(pyroscope.HeaderContentType),
+		URL:         r.URL,
+		Labels:      lbls,
+	})
+
 	var wg sync.WaitGroup
 	var errs error
 	var errorMut sync.Mutex
@@ -316,3 +363,5 @@ func ensureServiceName(lbls labels.Labels) labels.Labels {
 
 	return builder.Labels()
 }
+
+func (c *Component) LiveDebugging() {}
@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/alloy/internal/component/pyroscope"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/prometheus/common/model"
@@ -80,10 +81,13 @@ type Component struct {
 	cache        *lru.Cache[model.Fingerprint, []cacheItem]
 	maxCacheSize int
 	exited       atomic.Bool
+
+	debugDataPublisher livedebugging.DebugDataPublisher
 }
 
 var (
-	_ component.Component = (*Component)(nil)
+	_ component.Component     = (*Component)(nil)
+	_ component.LiveDebugging = (*Component)(nil)
 )
 
 // New creates a new pyroscope.relabel component.
@@ -93,11 +97,17 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		return nil, err
 	}
 
+	debugDataPublisher, err := o.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
 	c := &Component{
-		opts:         o,
-		metrics:      newMetrics(o.Registerer),
-		cache:        cache,
-		maxCacheSize: args.MaxCacheSize,
+		opts:               o,
+		metrics:            newMetrics(o.Registerer),
+		cache:              cache,
+		maxCacheSize:       args.MaxCacheSize,
+		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
 	}
 
 	c.fanout = pyroscope.NewFanout(args.ForwardTo, o.ID, o.Registerer)
@@ -164,11 +174,13 @@ func (c *Component) Append(ctx context.Context, lbls labels.Labels, samples []*p
 	c.metrics.profilesProcessed.Inc()
 
 	if lbls.IsEmpty() {
+		c.publishSamples(lbls, lbls, true, samples)
 		c.metrics.profilesOutgoing.Inc()
 		return c.fanout.Appender().Append(ctx, lbls, samples)
 	}
 
 	newLabels, keep := c.relabel(lbls)
+	c.publishSamples(lbls, newLabels, keep, samples)
 	if !keep {
 		c.metrics.profilesDropped.Inc()
 		level.Debug(c.opts.Logger).Log("msg", "profile dropped by relabel rules", "labels", lbls.String())
@@ -190,11 +202,13 @@ func (c *Component) AppendIngest(ctx context.Context, profile *pyroscope.Incomin
 	c.metrics.profilesProcessed.Inc()
 
 	if profile.Labels.IsEmpty() {
+		c.publishDebugData(profile.Labels, profile.Labels, true, profile.RawBody)
 		c.metrics.profilesOutgoing.Inc()
 		return c.fanout.Appender().AppendIngest(ctx, profile)
 	}
 
 	newLabels, keep := c.relabel(profile.Labels)
+	c.publishDebugData(profile.Labels, newLabels, keep, profile.RawBody)
 	if !keep {
 		c.metrics.profilesDropped.Inc()
 		level.Debug(c.opts.Logger).Log("msg", "profile dropped by relabel rules")
@@ -210,6 +224,31 @@ func (c *Component) Appender() pyroscope.Appender {
 	return c
 }
 
+func (c *Component) publishSamples(original, relabeled labels.Labels, keep bool, samples []*pyroscope.RawSample) {
+	for _, sample := range samples {
+		c.publishDebugData(original, relabeled, keep, sample.RawProfile)
+	}
+}
+
+func (c *Component) publishDebugData(original, relabeled labels.Labels, keep bool, rawProfile []byte) {
+	count := uint64(1)
+	if !keep {
+		count = 0 // if the profile is dropped, the count is not incremented because the profile will be filtered out
+	}
+	componentID := livedebugging.ComponentID(c.opts.ID)
+	c.debugDataPublisher.PublishIfActive(livedebugging.NewData(
+		componentID,
+		livedebugging.Profile,
+		count,
+		func() string {
+			return fmt.Sprintf("%s => %s", original, pyroscope.ProfileDebugString(relabeled, rawProfile))
+		},
+		livedebugging.WithPrometheusLabels(original, relabeled),
+	))
+}
+
+func (c *Component) LiveDebugging() {}
+
 type cacheItem struct {
 	original  model.LabelSet
 	relabeled model.LabelSet
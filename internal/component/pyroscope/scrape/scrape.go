@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/grafana/alloy/internal/service/cluster"
 	"github.com/grafana/alloy/internal/service/http"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 
 	"github.com/grafana/alloy/internal/component"
 	component_config "github.com/grafana/alloy/internal/component/common/config"
@@ -252,7 +253,10 @@ type Component struct {
 	appendable *pyroscope.Fanout
 }
 
-var _ component.Component = (*Component)(nil)
+var (
+	_ component.Component     = (*Component)(nil)
+	_ component.LiveDebugging = (*Component)(nil)
+)
 
 // New creates a new pprof.scrape component.
 func New(o component.Options, args Arguments) (*Component, error) {
@@ -268,13 +272,23 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	}
 	clusterData := data.(cluster.Cluster)
 
+	debugDataPublisher, err := o.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
 	alloyAppendable := pyroscope.NewFanout(args.ForwardTo, o.ID, o.Registerer)
 	scrapeHttpOptions := Options{
 		HTTPClientOptions: []config_util.HTTPClientOption{
 			config_util.WithDialContextFunc(httpData.DialFunc),
 		},
 	}
-	scraper, err := NewManager(scrapeHttpOptions, args, alloyAppendable, o.Logger)
+	debugAppendable := pyroscope.NewLiveDebuggingAppendable(
+		alloyAppendable,
+		livedebugging.ComponentID(o.ID),
+		debugDataPublisher.(livedebugging.DebugDataPublisher),
+	)
+	scraper, err := NewManager(scrapeHttpOptions, args, debugAppendable, o.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scraper manager: %w", err)
 	}
@@ -398,3 +412,5 @@ func (c *Component) DebugInfo() interface{} {
 
 	return scrape.ScraperStatus{TargetStatus: res}
 }
+
+func (c *Component) LiveDebugging() {}
@@ -22,6 +22,7 @@ import (
 	"github.com/grafana/alloy/internal/component/pyroscope"
 	"github.com/grafana/alloy/internal/service/cluster"
 	http_service "github.com/grafana/alloy/internal/service/http"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
 )
@@ -79,6 +80,8 @@ func getServiceData(name string) (interface{}, error) {
 			BaseHTTPPath:     "/",
 			DialFunc:         (&net.Dialer{}).DialContext,
 		}, nil
+	case livedebugging.ServiceName:
+		return livedebugging.NewLiveDebugging(), nil
 	default:
 		return nil, fmt.Errorf("unrecognized service name %q", name)
 	}
@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/alloy/internal/component/pyroscope"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/useragent"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/dskit/backoff"
@@ -111,6 +112,8 @@ type Component struct {
 	opts    component.Options
 	cfg     Arguments
 	metrics *metrics
+
+	debugDataPublisher livedebugging.DebugDataPublisher
 }
 
 // Exports are the set of fields exposed by the pyroscope.write component.
@@ -120,8 +123,14 @@ type Exports struct {
 
 // New creates a new pyroscope.write component.
 func New(o component.Options, c Arguments) (*Component, error) {
+	data, err := o.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	debugDataPublisher := data.(livedebugging.DebugDataPublisher)
+
 	metrics := newMetrics(o.Registerer)
-	receiver, err := NewFanOut(o, c, metrics)
+	receiver, err := NewFanOut(o, c, metrics, debugDataPublisher)
 	if err != nil {
 		return nil, err
 	}
@@ -129,13 +138,15 @@ func New(o component.Options, c Arguments) (*Component, error) {
 	o.OnStateChange(Exports{Receiver: receiver})
 
 	return &Component{
-		cfg:     c,
-		opts:    o,
-		metrics: metrics,
+		cfg:                c,
+		opts:               o,
+		metrics:            metrics,
+		debugDataPublisher: debugDataPublisher,
 	}, nil
 }
 
 var _ component.Component = (*Component)(nil)
+var _ component.LiveDebugging = (*Component)(nil)
 
 // Run implements Component.
 func (c *Component) Run(ctx context.Context) error {
@@ -147,7 +158,7 @@ func (c *Component) Run(ctx context.Context) error {
 func (c *Component) Update(newConfig component.Arguments) error {
 	c.cfg = newConfig.(Arguments)
 	level.Debug(c.opts.Logger).Log("msg", "updating pyroscope.write config", "old", c.cfg, "new", newConfig)
-	receiver, err := NewFanOut(c.opts, newConfig.(Arguments), c.metrics)
+	receiver, err := NewFanOut(c.opts, newConfig.(Arguments), c.metrics, c.debugDataPublisher)
 	if err != nil {
 		return err
 	}
@@ -155,6 +166,8 @@ func (c *Component) Update(newConfig component.Arguments) error {
 	return nil
 }
 
+func (c *Component) LiveDebugging() {}
+
 type fanOutClient struct {
 	// The list of push clients to fan out to.
 	pushClients   []pushv1connect.PusherServiceClient
@@ -162,10 +175,12 @@ type fanOutClient struct {
 	config        Arguments
 	opts          component.Options
 	metrics       *metrics
+
+	debugDataPublisher livedebugging.DebugDataPublisher
 }
 
 // NewFanOut creates a new fan out client that will fan out to all endpoints.
-func NewFanOut(opts component.Options, config Arguments, metrics *metrics) (*fanOutClient, error) {
+func NewFanOut(opts component.Options, config Arguments, metrics *metrics, debugDataPublisher livedebugging.DebugDataPublisher) (*fanOutClient, error) {
 	pushClients := make([]pushv1connect.PusherServiceClient, 0, len(config.Endpoints))
 	ingestClients := make(map[*EndpointOptions]*http.Client)
 	uid := alloyseed.Get().UID
@@ -192,6 +207,8 @@ func NewFanOut(opts component.Options, config Arguments, metrics *metrics) (*fan
 		config:        config,
 		opts:          opts,
 		metrics:       metrics,
+
+		debugDataPublisher: debugDataPublisher,
 	}, nil
 }
 
@@ -323,7 +340,8 @@ func (f *fanOutClient) Append(ctx context.Context, lbs labels.Labels, samples []
 	for name, value := range f.config.ExternalLabels {
 		lbsBuilder.Set(name, value)
 	}
-	for _, l := range lbsBuilder.Labels() {
+	finalLabels := lbsBuilder.Labels()
+	for _, l := range finalLabels {
 		protoLabels = append(protoLabels, &typesv1.LabelPair{
 			Name:  l.Name,
 			Value: l.Value,
@@ -334,6 +352,8 @@ func (f *fanOutClient) Append(ctx context.Context, lbs labels.Labels, samples []
 			RawProfile: sample.RawProfile,
 		})
 	}
+	pyroscope.PublishProfiles(f.debugDataPublisher, livedebugging.ComponentID(f.opts.ID), finalLabels, samples)
+
 	// push to all clients
 	_, err := f.Push(ctx, connect.NewRequest(&pushv1.PushRequest{
 		Series: []*pushv1.RawProfileSeries{
@@ -389,6 +409,8 @@ func (f *fanOutClient) AppendIngest(ctx context.Context, profile *pyroscope.Inco
 	}
 	query.Set("name", ls.Normalized())
 
+	pyroscope.PublishIncomingProfile(f.debugDataPublisher, livedebugging.ComponentID(f.opts.ID), profile)
+
 	// Send to each endpoint concurrently
 	for endpointIdx, endpoint := range f.config.Endpoints {
 		wg.Add(1)
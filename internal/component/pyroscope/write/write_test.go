@@ -3,6 +3,7 @@ package write
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +16,7 @@ import (
 	"connectrpc.com/connect"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/pyroscope"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
 	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
@@ -88,9 +90,10 @@ func Test_Write_FanOut(t *testing.T) {
 		var wg sync.WaitGroup
 		wg.Add(1)
 		c, err := New(component.Options{
-			ID:         "1",
-			Logger:     util.TestAlloyLogger(t),
-			Registerer: prometheus.NewRegistry(),
+			ID:             "1",
+			Logger:         util.TestAlloyLogger(t),
+			Registerer:     prometheus.NewRegistry(),
+			GetServiceData: getServiceData,
 			OnStateChange: func(e component.Exports) {
 				defer wg.Done()
 				export = e.(Exports)
@@ -164,9 +167,10 @@ func Test_Write_Update(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	c, err := New(component.Options{
-		ID:         "1",
-		Logger:     util.TestAlloyLogger(t),
-		Registerer: prometheus.NewRegistry(),
+		ID:             "1",
+		Logger:         util.TestAlloyLogger(t),
+		Registerer:     prometheus.NewRegistry(),
+		GetServiceData: getServiceData,
 		OnStateChange: func(e component.Exports) {
 			defer wg.Done()
 			export = e.(Exports)
@@ -339,9 +343,10 @@ func Test_Write_AppendIngest(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	c, err := New(component.Options{
-		ID:         "test-write",
-		Logger:     util.TestAlloyLogger(t),
-		Registerer: prometheus.NewRegistry(),
+		ID:             "test-write",
+		Logger:         util.TestAlloyLogger(t),
+		Registerer:     prometheus.NewRegistry(),
+		GetServiceData: getServiceData,
 		OnStateChange: func(e component.Exports) {
 			defer wg.Done()
 			export = e.(Exports)
@@ -420,9 +425,10 @@ func TestAppendIngestLabelTransformation(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	c, err := New(component.Options{
-		ID:         "test-write",
-		Logger:     util.TestAlloyLogger(t),
-		Registerer: prometheus.NewRegistry(),
+		ID:             "test-write",
+		Logger:         util.TestAlloyLogger(t),
+		Registerer:     prometheus.NewRegistry(),
+		GetServiceData: getServiceData,
 		OnStateChange: func(e component.Exports) {
 			defer wg.Done()
 			export = e.(Exports)
@@ -466,9 +472,10 @@ func Test_Write_AppendIngest_InvalidLabels(t *testing.T) {
 	var export Exports
 	wg.Add(1)
 	c, err := New(component.Options{
-		ID:         "test-write-invalid",
-		Logger:     util.TestAlloyLogger(t),
-		Registerer: prometheus.NewRegistry(),
+		ID:             "test-write-invalid",
+		Logger:         util.TestAlloyLogger(t),
+		Registerer:     prometheus.NewRegistry(),
+		GetServiceData: getServiceData,
 		OnStateChange: func(e component.Exports) {
 			defer wg.Done()
 			export = e.(Exports)
@@ -567,9 +574,10 @@ func Test_Write_FanOut_ValidateLabels(t *testing.T) {
 	var export Exports
 	wg.Add(1)
 	c, err := New(component.Options{
-		ID:         "test-write-fanout-validate-labels",
-		Logger:     util.TestAlloyLogger(t),
-		Registerer: prometheus.NewRegistry(),
+		ID:             "test-write-fanout-validate-labels",
+		Logger:         util.TestAlloyLogger(t),
+		Registerer:     prometheus.NewRegistry(),
+		GetServiceData: getServiceData,
 		OnStateChange: func(e component.Exports) {
 			defer wg.Done()
 			export = e.(Exports)
@@ -653,3 +661,12 @@ func Test_Write_FanOut_ValidateLabels(t *testing.T) {
 		})
 	}
 }
+
+func getServiceData(name string) (interface{}, error) {
+	switch name {
+	case livedebugging.ServiceName:
+		return livedebugging.NewLiveDebugging(), nil
+	default:
+		return nil, fmt.Errorf("service not found %s", name)
+	}
+}
@@ -0,0 +1,104 @@
+package otelcolconvert
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/connector/count"
+	"github.com/grafana/alloy/internal/converter/diag"
+	"github.com/grafana/alloy/internal/converter/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/countconnector"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	converters = append(converters, countConnectorConverter{})
+}
+
+type countConnectorConverter struct{}
+
+func (countConnectorConverter) Factory() component.Factory {
+	return countconnector.NewFactory()
+}
+
+func (countConnectorConverter) InputComponentName() string {
+	return "otelcol.connector.count"
+}
+
+func (countConnectorConverter) ConvertAndAppend(state *State, id componentstatus.InstanceID, cfg component.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	label := state.AlloyComponentLabel()
+
+	args := toCountConnector(state, id, cfg.(*countconnector.Config))
+	block := common.NewBlockWithOverride([]string{"otelcol", "connector", "count"}, label, args)
+
+	diags.Add(
+		diag.SeverityLevelInfo,
+		fmt.Sprintf("Converted %s into %s", StringifyInstanceID(id), StringifyBlock(block)),
+	)
+
+	state.Body().AppendBlock(block)
+	return diags
+}
+
+func toCountConnector(state *State, id componentstatus.InstanceID, cfg *countconnector.Config) *count.Arguments {
+	if cfg == nil {
+		return nil
+	}
+	var (
+		nextMetrics = state.Next(id, pipeline.SignalMetrics)
+	)
+
+	return &count.Arguments{
+		Spans:      toCountMetricInfos(cfg.Spans, count.DefaultArguments.Spans),
+		SpanEvents: toCountMetricInfos(cfg.SpanEvents, count.DefaultArguments.SpanEvents),
+		Metrics:    toCountMetricInfos(cfg.Metrics, count.DefaultArguments.Metrics),
+		DataPoints: toCountMetricInfos(cfg.DataPoints, count.DefaultArguments.DataPoints),
+		Logs:       toCountMetricInfos(cfg.Logs, count.DefaultArguments.Logs),
+
+		Output: &otelcol.ConsumerArguments{
+			Metrics: ToTokenizedConsumers(nextMetrics),
+		},
+
+		DebugMetrics: common.DefaultValue[count.Arguments]().DebugMetrics,
+	}
+}
+
+// toCountMetricInfos converts the metrics of a signal, sorted by name. The
+// upstream configuration only has the default metric of the signal when no
+// metrics are configured for it.
+func toCountMetricInfos(cfg map[string]countconnector.MetricInfo, defaultInfos []count.MetricInfo) []count.MetricInfo {
+	if len(cfg) == 0 {
+		return defaultInfos
+	}
+
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]count.MetricInfo, 0, len(cfg))
+	for _, name := range names {
+		info := cfg[name]
+
+		var attrs []count.AttributeConfig
+		for _, attr := range info.Attributes {
+			attrs = append(attrs, count.AttributeConfig{
+				Key:          attr.Key,
+				DefaultValue: attr.DefaultValue,
+			})
+		}
+		res = append(res, count.MetricInfo{
+			Name:        name,
+			Description: info.Description,
+			Conditions:  info.Conditions,
+			Attributes:  attrs,
+		})
+	}
+	return res
+}
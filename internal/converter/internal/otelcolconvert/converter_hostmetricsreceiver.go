@@ -0,0 +1,192 @@
+package otelcolconvert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/hostmetrics"
+	"github.com/grafana/alloy/internal/converter/diag"
+	"github.com/grafana/alloy/internal/converter/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/hostmetricsreceiver"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	converters = append(converters, hostmetricsReceiverConverter{})
+}
+
+type hostmetricsReceiverConverter struct{}
+
+func (hostmetricsReceiverConverter) Factory() component.Factory {
+	return hostmetricsreceiver.NewFactory()
+}
+
+func (hostmetricsReceiverConverter) InputComponentName() string { return "" }
+
+func (hostmetricsReceiverConverter) ConvertAndAppend(state *State, id componentstatus.InstanceID, cfg component.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	label := state.AlloyComponentLabel()
+
+	args, unsupported := toHostmetricsReceiver(state, id, cfg.(*hostmetricsreceiver.Config))
+	if len(unsupported) > 0 {
+		diags.Add(
+			diag.SeverityLevelWarn,
+			fmt.Sprintf("the %s scrapers of %s are not supported", strings.Join(unsupported, ", "), StringifyInstanceID(id)),
+		)
+	}
+
+	block := common.NewBlockWithOverride([]string{"otelcol", "receiver", "hostmetrics"}, label, args)
+
+	diags.Add(
+		diag.SeverityLevelInfo,
+		fmt.Sprintf("Converted %s into %s", StringifyInstanceID(id), StringifyBlock(block)),
+	)
+
+	state.Body().AppendBlock(block)
+	return diags
+}
+
+// toHostmetricsReceiver converts cfg, and returns the names of the scrapers
+// which can't be converted.
+func toHostmetricsReceiver(state *State, id componentstatus.InstanceID, cfg *hostmetricsreceiver.Config) (*hostmetrics.Arguments, []string) {
+	var (
+		nextMetrics = state.Next(id, pipeline.SignalMetrics)
+	)
+
+	args := &hostmetrics.Arguments{
+		Controller: toScraperControllerArguments(cfg.ControllerConfig),
+		RootPath:   cfg.RootPath,
+
+		DebugMetrics: common.DefaultValue[hostmetrics.Arguments]().DebugMetrics,
+
+		Output: &otelcol.ConsumerArguments{
+			Metrics: ToTokenizedConsumers(nextMetrics),
+		},
+	}
+
+	// The configurations of the scrapers are in internal packages, so they're
+	// encoded to maps.
+	var unsupported []string
+	for typ, scraperCfg := range cfg.Scrapers {
+		m := encodeMapstruct(scraperCfg)
+
+		switch typ.String() {
+		case "cpu":
+			args.CPU = &hostmetrics.CPUScraperArguments{}
+		case "memory":
+			args.Memory = &hostmetrics.MemoryScraperArguments{}
+		case "load":
+			args.Load = &hostmetrics.LoadScraperArguments{
+				CPUAverage: hostmetricsValue[bool](m, "cpu_average"),
+			}
+		case "disk":
+			args.Disk = &hostmetrics.DiskScraperArguments{
+				Include: toHostmetricsDevicesMatch(encodeMapstruct(m["include"])),
+				Exclude: toHostmetricsDevicesMatch(encodeMapstruct(m["exclude"])),
+			}
+		case "filesystem":
+			args.Filesystem = &hostmetrics.FilesystemScraperArguments{
+				IncludeVirtualFilesystems: hostmetricsValue[bool](m, "include_virtual_filesystems"),
+
+				IncludeDevices:     toHostmetricsDevicesMatch(encodeMapstruct(m["include_devices"])),
+				ExcludeDevices:     toHostmetricsDevicesMatch(encodeMapstruct(m["exclude_devices"])),
+				IncludeFSTypes:     toHostmetricsFSTypesMatch(encodeMapstruct(m["include_fs_types"])),
+				ExcludeFSTypes:     toHostmetricsFSTypesMatch(encodeMapstruct(m["exclude_fs_types"])),
+				IncludeMountPoints: toHostmetricsMountPointsMatch(encodeMapstruct(m["include_mount_points"])),
+				ExcludeMountPoints: toHostmetricsMountPointsMatch(encodeMapstruct(m["exclude_mount_points"])),
+			}
+		case "network":
+			args.Network = &hostmetrics.NetworkScraperArguments{
+				Include: toHostmetricsInterfacesMatch(encodeMapstruct(m["include"])),
+				Exclude: toHostmetricsInterfacesMatch(encodeMapstruct(m["exclude"])),
+			}
+		case "process":
+			args.Process = &hostmetrics.ProcessScraperArguments{
+				Include: toHostmetricsNamesMatch(encodeMapstruct(m["include"])),
+				Exclude: toHostmetricsNamesMatch(encodeMapstruct(m["exclude"])),
+
+				MuteProcessNameError:   hostmetricsValue[bool](m, "mute_process_name_error"),
+				MuteProcessExeError:    hostmetricsValue[bool](m, "mute_process_exe_error"),
+				MuteProcessIOError:     hostmetricsValue[bool](m, "mute_process_io_error"),
+				MuteProcessUserError:   hostmetricsValue[bool](m, "mute_process_user_error"),
+				MuteProcessCgroupError: hostmetricsValue[bool](m, "mute_process_cgroup_error"),
+				MuteProcessAllErrors:   hostmetricsValue[bool](m, "mute_process_all_errors"),
+				ScrapeProcessDelay:     hostmetricsValue[time.Duration](m, "scrape_process_delay"),
+			}
+		default:
+			unsupported = append(unsupported, typ.String())
+		}
+	}
+	sort.Strings(unsupported)
+
+	return args, unsupported
+}
+
+// hostmetricsValue returns the value of key in a scraper configuration encoded
+// by encodeMapstruct. The zero value, which matches the default of every
+// scraper argument, is returned if the key is missing or has another type.
+func hostmetricsValue[T any](m map[string]any, key string) T {
+	v, _ := m[key].(T)
+	return v
+}
+
+func toHostmetricsDevicesMatch(cfg map[string]any) *hostmetrics.DevicesMatchArguments {
+	devices, _ := cfg["devices"].([]string)
+	if len(devices) == 0 {
+		return nil
+	}
+	return &hostmetrics.DevicesMatchArguments{
+		Devices:   devices,
+		MatchType: encodeString(cfg["match_type"]),
+	}
+}
+
+func toHostmetricsFSTypesMatch(cfg map[string]any) *hostmetrics.FSTypesMatchArguments {
+	fsTypes, _ := cfg["fs_types"].([]string)
+	if len(fsTypes) == 0 {
+		return nil
+	}
+	return &hostmetrics.FSTypesMatchArguments{
+		FSTypes:   fsTypes,
+		MatchType: encodeString(cfg["match_type"]),
+	}
+}
+
+func toHostmetricsMountPointsMatch(cfg map[string]any) *hostmetrics.MountPointsMatchArguments {
+	mountPoints, _ := cfg["mount_points"].([]string)
+	if len(mountPoints) == 0 {
+		return nil
+	}
+	return &hostmetrics.MountPointsMatchArguments{
+		MountPoints: mountPoints,
+		MatchType:   encodeString(cfg["match_type"]),
+	}
+}
+
+func toHostmetricsInterfacesMatch(cfg map[string]any) *hostmetrics.InterfacesMatchArguments {
+	interfaces, _ := cfg["interfaces"].([]string)
+	if len(interfaces) == 0 {
+		return nil
+	}
+	return &hostmetrics.InterfacesMatchArguments{
+		Interfaces: interfaces,
+		MatchType:  encodeString(cfg["match_type"]),
+	}
+}
+
+func toHostmetricsNamesMatch(cfg map[string]any) *hostmetrics.NamesMatchArguments {
+	names, _ := cfg["names"].([]string)
+	if len(names) == 0 {
+		return nil
+	}
+	return &hostmetrics.NamesMatchArguments{
+		Names:     names,
+		MatchType: encodeString(cfg["match_type"]),
+	}
+}
@@ -0,0 +1,126 @@
+package otelcolconvert
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/file"
+	"github.com/grafana/alloy/internal/converter/diag"
+	"github.com/grafana/alloy/internal/converter/internal/common"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/otlpjsonfilereceiver"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	converters = append(converters, otlpJSONFileReceiverConverter{})
+}
+
+type otlpJSONFileReceiverConverter struct{}
+
+func (otlpJSONFileReceiverConverter) Factory() component.Factory {
+	return otlpjsonfilereceiver.NewFactory()
+}
+
+func (otlpJSONFileReceiverConverter) InputComponentName() string { return "" }
+
+func (otlpJSONFileReceiverConverter) ConvertAndAppend(state *State, id componentstatus.InstanceID, cfg component.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	label := state.AlloyComponentLabel()
+
+	fileCfg := cfg.(*otlpjsonfilereceiver.Config)
+	args := toOTLPJSONFileReceiver(state, id, fileCfg)
+
+	for _, option := range droppedOTLPJSONFileOptions(fileCfg) {
+		diags.Add(
+			diag.SeverityLevelWarn,
+			fmt.Sprintf("the %s option of %s is not supported by otelcol.receiver.file and is dropped", option, StringifyInstanceID(id)),
+		)
+	}
+
+	// The upstream receiver keeps polling the files and, by default, only
+	// reads what's written after it starts, while otelcol.receiver.file
+	// replays the files from the beginning once.
+	if fileCfg.StartAt != "beginning" {
+		diags.Add(
+			diag.SeverityLevelWarn,
+			fmt.Sprintf("the start_at option of %s is %q, but otelcol.receiver.file reads the matching files from the beginning", StringifyInstanceID(id), fileCfg.StartAt),
+		)
+	}
+	diags.Add(
+		diag.SeverityLevelWarn,
+		fmt.Sprintf("the poll_interval option of %s is dropped, otelcol.receiver.file reads the matching files once instead of polling them", StringifyInstanceID(id)),
+	)
+
+	block := common.NewBlockWithOverride([]string{"otelcol", "receiver", "file"}, label, args)
+
+	diags.Add(
+		diag.SeverityLevelInfo,
+		fmt.Sprintf("Converted %s into %s", StringifyInstanceID(id), StringifyBlock(block)),
+	)
+
+	state.Body().AppendBlock(block)
+	return diags
+}
+
+func toOTLPJSONFileReceiver(state *State, id componentstatus.InstanceID, cfg *otlpjsonfilereceiver.Config) *file.Arguments {
+	var (
+		nextMetrics = state.Next(id, pipeline.SignalMetrics)
+		nextLogs    = state.Next(id, pipeline.SignalLogs)
+		nextTraces  = state.Next(id, pipeline.SignalTraces)
+	)
+
+	return &file.Arguments{
+		Include: cfg.Criteria.Include,
+		Exclude: cfg.Criteria.Exclude,
+		Format:  file.FormatJSON,
+		// The upstream receiver sends the telemetry as soon as it's read.
+		Throttle: 0,
+
+		DebugMetrics: common.DefaultValue[file.Arguments]().DebugMetrics,
+
+		Output: &otelcol.ConsumerArguments{
+			Metrics: ToTokenizedConsumers(nextMetrics),
+			Logs:    ToTokenizedConsumers(nextLogs),
+			Traces:  ToTokenizedConsumers(nextTraces),
+		},
+	}
+}
+
+// droppedOTLPJSONFileOptions returns the options of cfg which are set to a
+// value otelcol.receiver.file can't honor. start_at and poll_interval are
+// reported separately.
+func droppedOTLPJSONFileOptions(cfg *otlpjsonfilereceiver.Config) []string {
+	def := otlpjsonfilereceiver.NewFactory().CreateDefaultConfig().(*otlpjsonfilereceiver.Config)
+
+	var res []string
+	for _, option := range []struct {
+		name string
+		set  bool
+	}{
+		{"exclude_older_than", cfg.Criteria.ExcludeOlderThan != def.Criteria.ExcludeOlderThan},
+		{"ordering_criteria", !reflect.DeepEqual(cfg.Criteria.OrderingCriteria, def.Criteria.OrderingCriteria)},
+		{"max_concurrent_files", cfg.MaxConcurrentFiles != def.MaxConcurrentFiles},
+		{"max_batches", cfg.MaxBatches != def.MaxBatches},
+		{"fingerprint_size", cfg.FingerprintSize != def.FingerprintSize},
+		{"initial_buffer_size", cfg.InitialBufferSize != def.InitialBufferSize},
+		{"max_log_size", cfg.MaxLogSize != def.MaxLogSize},
+		{"encoding", cfg.Encoding != def.Encoding},
+		{"multiline", cfg.SplitConfig != def.SplitConfig},
+		{"force_flush_period", cfg.FlushPeriod != def.FlushPeriod},
+		{"header", cfg.Header != nil},
+		{"delete_after_read", cfg.DeleteAfterRead},
+		{"compression", cfg.Compression != def.Compression},
+		{"acquire_fs_lock", cfg.AcquireFSLock},
+		{"storage", cfg.StorageID != nil},
+		{"replay_file", cfg.ReplayFile},
+	} {
+		if option.set {
+			res = append(res, option.name)
+		}
+	}
+	return res
+}
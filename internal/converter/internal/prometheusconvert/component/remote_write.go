@@ -65,6 +65,11 @@ func getEndpointOptions(remoteWriteConfigs []*prom_config.RemoteWriteConfig) []*
 	endpoints := make([]*remotewrite.EndpointOptions, 0)
 
 	for _, remoteWriteConfig := range remoteWriteConfigs {
+		protobufMessage := remoteWriteConfig.ProtobufMessage
+		if protobufMessage == "" {
+			protobufMessage = prom_config.RemoteWriteProtoMsgV1
+		}
+
 		endpoint := &remotewrite.EndpointOptions{
 			Name:                 remoteWriteConfig.Name,
 			URL:                  remoteWriteConfig.URL.String(),
@@ -72,6 +77,7 @@ func getEndpointOptions(remoteWriteConfigs []*prom_config.RemoteWriteConfig) []*
 			Headers:              remoteWriteConfig.Headers,
 			SendExemplars:        remoteWriteConfig.SendExemplars,
 			SendNativeHistograms: remoteWriteConfig.SendNativeHistograms,
+			ProtobufMessage:      string(protobufMessage),
 			HTTPClientConfig:     common.ToHttpClientConfig(&remoteWriteConfig.HTTPClientConfig),
 			QueueOptions:         toQueueOptions(&remoteWriteConfig.QueueConfig),
 			MetadataOptions:      toMetadataOptions(&remoteWriteConfig.MetadataConfig),
@@ -133,10 +133,9 @@ func (s *ScrapeConfigBuilder) getOrNewProcessStageReceivers() []loki.LogsReceive
 			alloyStages[i] = fs
 		}
 	}
-	args := process.Arguments{
-		ForwardTo: s.globalCtx.WriteReceivers,
-		Stages:    alloyStages,
-	}
+	args := process.DefaultArguments
+	args.ForwardTo = s.globalCtx.WriteReceivers
+	args.Stages = alloyStages
 	compLabel := common.LabelForParts(s.globalCtx.LabelPrefix, s.cfg.JobName)
 	s.f.Body().AppendBlock(common.NewBlockWithOverride([]string{"loki", "process"}, compLabel, args))
 	s.processStageReceivers = []loki.LogsReceiver{common.ConvertLogsReceiver{
@@ -501,6 +501,7 @@ func toAlloyMetricsProcessStage(name string, pMetric promtailstages.MetricConfig
 			Action:          pCounter.Cfg.Action,
 			MatchAll:        defaultFalse(pCounter.Cfg.MatchAll),
 			CountEntryBytes: defaultFalse(pCounter.Cfg.CountBytes),
+			ExemplarLabel:   metric.DefaultExemplarLabel,
 		}
 	case promtailstages.MetricTypeGauge:
 		pGauge, err := promtailmetric.NewGauges(name, pMetric.Description, pMetric.Config, int64(maxIdle.Seconds()))
@@ -524,13 +525,14 @@ func toAlloyMetricsProcessStage(name string, pMetric promtailstages.MetricConfig
 			return stages.MetricConfig{}, false
 		}
 		fMetric.Histogram = &metric.HistogramConfig{
-			Name:        name,
-			Description: pMetric.Description,
-			Source:      defaultEmpty(pMetric.Source),
-			Prefix:      pMetric.Prefix,
-			MaxIdle:     maxIdle,
-			Value:       defaultEmpty(pHistogram.Cfg.Value),
-			Buckets:     pHistogram.Cfg.Buckets,
+			Name:          name,
+			Description:   pMetric.Description,
+			Source:        defaultEmpty(pMetric.Source),
+			Prefix:        pMetric.Prefix,
+			MaxIdle:       maxIdle,
+			Value:         defaultEmpty(pHistogram.Cfg.Value),
+			Buckets:       pHistogram.Cfg.Buckets,
+			ExemplarLabel: metric.DefaultExemplarLabel,
 		}
 	}
 	return fMetric, true
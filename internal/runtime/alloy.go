@@ -153,6 +153,9 @@ type controllerOptions struct {
 	IsModule          bool               // Whether this controller is for a module.
 	// A worker pool to evaluate components asynchronously. A default one will be created if this is nil.
 	WorkerPool worker.Pool
+
+	ValidateOnly       bool // Only decode and type-check loaded configs; see Validate.
+	SkipNetworkImports bool // Don't fetch network-backed imports when validating.
 }
 
 // newController creates a new, unstarted Alloy controller with a specific
@@ -201,6 +204,8 @@ func newController(o controllerOptions) *Runtime {
 			DataPath:             o.DataPath,
 			MinStability:         o.MinStability,
 			EnableCommunityComps: o.EnableCommunityComps,
+			ValidateOnly:         o.ValidateOnly,
+			SkipNetworkImports:   o.SkipNetworkImports,
 			OnBlockNodeUpdate: func(cn controller.BlockNode) {
 				// Changed node should be queued for reevaluation.
 				f.updateQueue.Enqueue(&controller.QueuedNode{Node: cn, LastUpdatedTime: time.Now()})
@@ -228,6 +233,8 @@ func newController(o controllerOptions) *Runtime {
 					ID:                   opts.Id,
 					ServiceMap:           serviceMap,
 					WorkerPool:           workerPool,
+					ValidateOnly:         o.ValidateOnly,
+					SkipNetworkImports:   o.SkipNetworkImports,
 				})
 			},
 			GetServiceData: func(name string) (interface{}, error) {
@@ -274,6 +281,7 @@ func (f *Runtime) Run(ctx context.Context) {
 				services   = f.loader.Services()
 				imports    = f.loader.Imports()
 				forEachs   = f.loader.ForEachs()
+				ifs        = f.loader.Ifs()
 
 				runnables = make([]controller.RunnableNode, 0, len(components)+len(services)+len(imports))
 			)
@@ -289,6 +297,10 @@ func (f *Runtime) Run(ctx context.Context) {
 				runnables = append(runnables, fe)
 			}
 
+			for _, i := range ifs {
+				runnables = append(runnables, i)
+			}
+
 			// Only the root controller should run services, since modules share the
 			// same service instance as the root.
 			if !f.opts.IsModule {
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -8,6 +9,10 @@ import (
 	"github.com/grafana/alloy/syntax/ast"
 )
 
+// errImportSkipped is returned when the definition of a custom component
+// comes from an import which was skipped while validating a config.
+var errImportSkipped = errors.New("import was skipped")
+
 // ComponentNodeManager is responsible for creating new component nodes and
 // obtaining the necessary information to run them.
 type ComponentNodeManager struct {
@@ -56,6 +61,9 @@ func (m *ComponentNodeManager) getCustomComponentConfig(namespace string, compon
 	if namespace == "" {
 		template, customComponentRegistry = findLocalDeclare(m.customComponentReg, componentName)
 	} else {
+		if isSkippedImport(m.customComponentReg, namespace) {
+			return nil, nil, errImportSkipped
+		}
 		template, customComponentRegistry = findImportedDeclare(m.customComponentReg, namespace, componentName)
 	}
 
@@ -101,6 +109,18 @@ func findImportedDeclare(reg *CustomComponentRegistry, namespace string, compone
 	return nil, nil
 }
 
+// isSkippedImport recursively searches for an import matching the provided
+// namespace and returns true if its content was skipped.
+func isSkippedImport(reg *CustomComponentRegistry, namespace string) bool {
+	if imported, ok := reg.getImport(namespace); ok {
+		return imported != nil && imported.skipped
+	}
+	if reg.parent != nil {
+		return isSkippedImport(reg.parent, namespace)
+	}
+	return false
+}
+
 func (m *ComponentNodeManager) setCustomComponentRegistry(reg *CustomComponentRegistry) {
 	m.mut.Lock()
 	defer m.mut.Unlock()
@@ -71,7 +71,10 @@ func ComponentReferences(cn dag.Node, g *dag.Graph, l log.Logger, scope *vm.Scop
 			// Both cases should be ignored at the linking level, that's the diags are ignored here.
 			// This is not super clean, but it should not create any problem since that the errors will be caught either during evaluation or while linking components
 			// inside of the foreach.
-			if _, ok := cn.(*ForeachConfigNode); !ok {
+			// The same applies to the branches of an if node.
+			switch cn.(type) {
+			case *ForeachConfigNode, *IfConfigNode:
+			default:
 				diags = append(diags, resolveDiags...)
 			}
 			continue
@@ -151,6 +154,16 @@ func (tw *traversalWalker) Visit(node ast.Node) ast.Visitor {
 			ast.Walk(tw, arg)
 		}
 		return nil
+
+	case *ast.ConditionalExpr:
+		// Each operand of a conditional is its own traversal; flush after each
+		// one so that accesses on the result (such as (a ? b : c).d) don't get
+		// appended to the traversal of the false branch.
+		for _, e := range []ast.Expr{n.Cond, n.True, n.False} {
+			ast.Walk(tw, e)
+			tw.flush()
+		}
+		return nil
 	}
 
 	return tw
@@ -18,6 +18,7 @@ type CustomComponentRegistry struct {
 	scope    *vm.Scope
 	imports  map[string]*CustomComponentRegistry // importNamespace: importScope
 	declares map[string]ast.Body                 // customComponentName: template
+	skipped  bool                                // true if the content of the import was not fetched
 }
 
 // NewCustomComponentRegistry creates a new CustomComponentRegistry with a parent.
@@ -80,6 +81,7 @@ func (s *CustomComponentRegistry) updateImportContent(importNode *ImportConfigNo
 	}
 	importScope := NewCustomComponentRegistry(nil, importNode.Scope())
 	importScope.declares = importNode.ImportedDeclares()
+	importScope.skipped = importNode.Skipped()
 	importScope.updateImportContentChildren(importNode)
 	s.imports[importNode.label] = importScope
 }
@@ -90,6 +92,7 @@ func (s *CustomComponentRegistry) updateImportContentChildren(importNode *Import
 	for _, child := range importNode.ImportConfigNodesChildren() {
 		childScope := NewCustomComponentRegistry(nil, child.Scope())
 		childScope.declares = child.ImportedDeclares()
+		childScope.skipped = child.Skipped()
 		childScope.updateImportContentChildren(child)
 		s.imports[child.label] = childScope
 	}
@@ -52,6 +52,7 @@ type Loader struct {
 	declareNodes         map[string]*DeclareNode
 	importConfigNodes    map[string]*ImportConfigNode
 	forEachNodes         map[string]*ForeachConfigNode
+	ifNodes              map[string]*IfConfigNode
 	serviceNodes         []*ServiceNode
 	cache                *valueCache
 	blocks               []*ast.BlockStmt // Most recently loaded blocks, used for writing
@@ -178,6 +179,7 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 		components   = make([]ComponentNode, 0)
 		componentIDs = make(map[string]ComponentID)
 		services     = make([]*ServiceNode, 0, len(l.services))
+		skipped      = make(map[dag.Node]struct{})
 	)
 
 	tracer := l.tracer.Tracer("")
@@ -205,6 +207,14 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 			level.Info(logger).Log("msg", "finished node evaluation", "node_id", n.NodeID(), "duration", time.Since(start))
 		}()
 
+		// When validating without network-backed imports, nodes which depend on
+		// a skipped node can't be evaluated since the values they reference
+		// are unknown.
+		if l.globals.ValidateOnly && dependsOnSkippedNode(&newGraph, n, skipped) {
+			skipped[n] = struct{}{}
+			return nil
+		}
+
 		var err error
 
 		switch n := n.(type) {
@@ -214,7 +224,16 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 
 			if err = l.evaluate(logger, n); err != nil {
 				var evalDiags diag.Diagnostics
-				if errors.As(err, &evalDiags) {
+				if l.globals.ValidateOnly && failsOnlyValidation(&newGraph, n, err) {
+					// Exports of components keep their zero values when validating, so
+					// the arguments which reference them can't be validated.
+					diags.Add(diag.Diagnostic{
+						Severity: diag.SeverityLevelWarn,
+						Message:  fmt.Sprintf("%s references exports of other components, which are only known at runtime, so its arguments could not be verified: %s", n.NodeID(), err),
+						StartPos: ast.StartPos(n.Block()).Position(),
+						EndPos:   ast.EndPos(n.Block()).Position(),
+					})
+				} else if errors.As(err, &evalDiags) {
 					diags = append(diags, evalDiags...)
 				} else {
 					diags.Add(diag.Diagnostic{
@@ -257,6 +276,18 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 			}
 		}
 
+		if sn, ok := n.(skippableNode); ok && sn.Skipped() {
+			skipped[n] = struct{}{}
+			if _, isImport := n.(*ImportConfigNode); isImport {
+				diags.Add(diag.Diagnostic{
+					Severity: diag.SeverityLevelWarn,
+					Message:  fmt.Sprintf("%s was not fetched; custom components using it were not validated", n.NodeID()),
+					StartPos: ast.StartPos(sn.Block()).Position(),
+					EndPos:   ast.EndPos(sn.Block()).Position(),
+				})
+			}
+		}
+
 		// We only use the error for updating the span status; we don't return the
 		// error because we want to evaluate as many nodes as we can.
 		if err != nil {
@@ -287,6 +318,54 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 	return diags
 }
 
+// skippableNode is implemented by nodes which may skip loading their content
+// when a config is validated without fetching network-backed imports.
+type skippableNode interface {
+	BlockNode
+	Skipped() bool
+}
+
+// dependsOnSkippedNode returns true if any direct dependency of n is in
+// skipped.
+func dependsOnSkippedNode(g *dag.Graph, n dag.Node, skipped map[dag.Node]struct{}) bool {
+	for _, dep := range g.Dependencies(n) {
+		if _, ok := skipped[dep]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// failsOnlyValidation returns true if the arguments of n, which failed to be
+// evaluated with err, reference the exports of other components and were
+// rejected by the arguments themselves rather than while decoding them.
+//
+// Exports of components keep their zero values when validating a config, so
+// errors raised by the arguments, such as those returned by their Validate
+// methods, may only be caused by these placeholder values. Decoding errors,
+// like type mismatches, are reported by the syntax as diagnostics and don't
+// depend on the values of exports.
+func failsOnlyValidation(g *dag.Graph, n ComponentNode, err error) bool {
+	if _, ok := n.(*BuiltinComponentNode); !ok {
+		return false
+	}
+
+	var (
+		d  diag.Diagnostic
+		ds diag.Diagnostics
+	)
+	if errors.As(err, &d) || errors.As(err, &ds) {
+		return false
+	}
+
+	for _, dep := range g.Dependencies(n) {
+		if _, ok := dep.(ComponentNode); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Cleanup unregisters any existing metrics and optionally stops the worker pool.
 func (l *Loader) Cleanup(stopWorkerPool bool) {
 	if stopWorkerPool {
@@ -438,6 +517,7 @@ func (l *Loader) populateServiceNodes(g *dag.Graph, serviceBlocks []*ast.BlockSt
 			node = exist.(*ServiceNode)
 		} else {
 			node = NewServiceNode(l.host, svc)
+			node.validateOnly = l.globals.ValidateOnly
 		}
 
 		node.UpdateBlock(nil) // Reset configuration to nil.
@@ -554,6 +634,7 @@ func (l *Loader) populateConfigBlockNodes(args map[string]any, g *dag.Graph, con
 
 	l.importConfigNodes = nodeMap.importMap
 	l.forEachNodes = nodeMap.foreachMap
+	l.ifNodes = nodeMap.ifMap
 
 	return diags
 }
@@ -640,6 +721,8 @@ func (l *Loader) wireGraphEdges(g *dag.Graph) diag.Diagnostics {
 			l.wireCustomComponentNode(g, n)
 		case *ForeachConfigNode:
 			l.wireForEachNode(g, n)
+		case *IfConfigNode:
+			l.wireIfNode(g, n)
 		}
 
 		// Finally, wire component references.
@@ -680,6 +763,14 @@ func (l *Loader) wireForEachNode(g *dag.Graph, fn *ForeachConfigNode) {
 	}
 }
 
+// wireIfNode add edges between an if node and declare/import nodes that are used in its branches.
+func (l *Loader) wireIfNode(g *dag.Graph, in *IfConfigNode) {
+	refs := l.findCustomComponentReferences(in.Block())
+	for ref := range refs {
+		g.AddEdge(dag.Edge{From: in, To: ref})
+	}
+}
+
 // Variables returns the Variables the Loader exposes for other components to
 // reference.
 func (l *Loader) Variables() map[string]interface{} {
@@ -714,6 +805,13 @@ func (l *Loader) ForEachs() map[string]*ForeachConfigNode {
 	return l.forEachNodes
 }
 
+// Ifs returns the current set of if nodes.
+func (l *Loader) Ifs() map[string]*IfConfigNode {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+	return l.ifNodes
+}
+
 // Graph returns a copy of the DAG managed by the Loader.
 func (l *Loader) Graph() *dag.Graph {
 	l.mut.RLock()
@@ -965,7 +1063,7 @@ func (l *Loader) collectCustomComponentReferences(stmts ast.Body, uniqueReferenc
 		)
 
 		switch {
-		case componentName == declareType || componentName == templateType:
+		case componentName == declareType || componentName == templateType || componentName == ifID || componentName == elseType:
 			l.collectCustomComponentReferences(blockStmt.Body, uniqueReferences)
 		case foundDeclare:
 			uniqueReferences[declareNode] = struct{}{}
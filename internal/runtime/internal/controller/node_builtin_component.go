@@ -84,6 +84,8 @@ type ComponentGlobals struct {
 	NewModuleController  func(opts ModuleControllerOpts) ModuleController // Func to generate a module controller.
 	GetServiceData       func(name string) (interface{}, error)           // Get data for a service.
 	EnableCommunityComps bool                                             // Enables the use of community components.
+	ValidateOnly         bool                                             // Only decode arguments: components aren't built and services aren't updated.
+	SkipNetworkImports   bool                                             // Don't fetch import.http and import.git modules. Only used with ValidateOnly.
 }
 
 // BuiltinComponentNode is a controller node which manages a builtin component.
@@ -103,6 +105,7 @@ type BuiltinComponentNode struct {
 	exportsType       reflect.Type
 	moduleController  ModuleController
 	OnBlockNodeUpdate func(cn BlockNode) // Informs controller that we need to reevaluate
+	validateOnly      bool               // Decode arguments without building the managed component
 
 	mut     sync.RWMutex
 	block   *ast.BlockStmt // Current Alloy block to derive args from
@@ -161,6 +164,7 @@ func NewBuiltinComponentNode(globals ComponentGlobals, reg component.Registratio
 		exportsType:       getExportsType(reg),
 		moduleController:  globals.NewModuleController(ModuleControllerOpts{Id: globalID}),
 		OnBlockNodeUpdate: globals.OnBlockNodeUpdate,
+		validateOnly:      globals.ValidateOnly,
 
 		block: b,
 		eval:  vm.New(b.Body),
@@ -285,6 +289,13 @@ func (cn *BuiltinComponentNode) evaluate(scope *vm.Scope) error {
 	// components expect a non-pointer.
 	argsCopyValue := reflect.ValueOf(argsPointer).Elem().Interface()
 
+	if cn.validateOnly {
+		// When validating, decoding the arguments is enough. Exports are left
+		// at their zero values so references to them can still be type-checked.
+		cn.args = argsCopyValue
+		return nil
+	}
+
 	if cn.managed == nil {
 		// We haven't built the managed component successfully yet.
 		managed, err := cn.reg.Build(cn.managedOpts, argsCopyValue)
@@ -15,11 +15,13 @@ const (
 	loggingBlockID  = "logging"
 	tracingBlockID  = "tracing"
 	foreachID       = "foreach"
+	ifID            = "if"
 )
 
 // Add config blocks that are not GA. Config blocks that are not specified here are considered GA.
 var configBlocksUnstable = map[string]featuregate.Stability{
 	foreachID: featuregate.StabilityExperimental,
+	ifID:      featuregate.StabilityExperimental,
 }
 
 // NewConfigNode creates a new ConfigNode from an initial ast.BlockStmt.
@@ -50,6 +52,8 @@ func NewConfigNode(block *ast.BlockStmt, globals ComponentGlobals, customReg *Cu
 		return NewImportConfigNode(block, globals, importsource.GetSourceType(block.GetBlockName())), nil
 	case foreachID:
 		return NewForeachConfigNode(block, globals, customReg), nil
+	case ifID:
+		return NewIfConfigNode(block, globals, customReg), nil
 	default:
 		diags.Add(diag.Diagnostic{
 			Severity: diag.SeverityLevelError,
@@ -79,6 +83,7 @@ type ConfigNodeMap struct {
 	exportMap   map[string]*ExportConfigNode
 	importMap   map[string]*ImportConfigNode
 	foreachMap  map[string]*ForeachConfigNode
+	ifMap       map[string]*IfConfigNode
 }
 
 // NewConfigNodeMap will create an initial ConfigNodeMap. Append must be called
@@ -91,6 +96,7 @@ func NewConfigNodeMap() *ConfigNodeMap {
 		exportMap:   map[string]*ExportConfigNode{},
 		importMap:   map[string]*ImportConfigNode{},
 		foreachMap:  map[string]*ForeachConfigNode{},
+		ifMap:       map[string]*IfConfigNode{},
 	}
 }
 
@@ -112,6 +118,8 @@ func (nodeMap *ConfigNodeMap) Append(configNode BlockNode) diag.Diagnostics {
 		nodeMap.importMap[n.Label()] = n
 	case *ForeachConfigNode:
 		nodeMap.foreachMap[n.Label()] = n
+	case *IfConfigNode:
+		nodeMap.ifMap[n.Label()] = n
 	default:
 		diags.Add(diag.Diagnostic{
 			Severity: diag.SeverityLevelError,
@@ -0,0 +1,389 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runner"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+const (
+	elseType = "else"
+
+	// IDs of the custom components which run the branches of an if block.
+	ifThenChildID = "then"
+	ifElseChildID = "else"
+)
+
+// The IfConfigNode runs the blocks in its body only when its condition is true.
+// If an else block is defined, its blocks are run when the condition is false instead.
+// The active branch is managed by a custom component which is created when the branch becomes active
+// and stopped when the condition changes.
+// Like for foreach, the custom component has access to the root scope.
+type IfConfigNode struct {
+	id               ComponentID
+	nodeID           string
+	label            string
+	componentName    string
+	moduleController ModuleController
+
+	logger log.Logger
+
+	// customReg is the customComponentRegistry of the current loader.
+	// We pass it so that the branches have access to modules.
+	customReg *CustomComponentRegistry
+
+	activeChildID string          // ID of the branch which is currently active, if any
+	activeChild   CustomComponent // custom component of the active branch
+
+	ifChildUpdateChan chan struct{} // used to trigger an update of the running branch
+	ifChildRunning    bool
+
+	mut   sync.RWMutex
+	block *ast.BlockStmt
+	args  IfArguments
+
+	healthMut  sync.RWMutex
+	evalHealth component.Health // Health of the last evaluate
+	runHealth  component.Health // Health of running the component
+
+	dataFlowEdgeMut  sync.RWMutex
+	dataFlowEdgeRefs []string
+}
+
+var _ ComponentNode = (*IfConfigNode)(nil)
+
+func NewIfConfigNode(block *ast.BlockStmt, globals ComponentGlobals, customReg *CustomComponentRegistry) *IfConfigNode {
+	nodeID := BlockComponentID(block).String()
+	globalID := nodeID
+	if globals.ControllerID != "" {
+		globalID = path.Join(globals.ControllerID, nodeID)
+	}
+
+	return &IfConfigNode{
+		nodeID:            nodeID,
+		label:             block.Label,
+		block:             block,
+		componentName:     block.GetBlockName(),
+		id:                BlockComponentID(block),
+		logger:            log.With(globals.Logger, "component_path", globals.ControllerID, "component_id", nodeID),
+		moduleController:  globals.NewModuleController(ModuleControllerOpts{Id: globalID}),
+		customReg:         customReg,
+		ifChildUpdateChan: make(chan struct{}, 1),
+	}
+}
+
+func (in *IfConfigNode) Label() string { return in.label }
+
+func (in *IfConfigNode) NodeID() string { return in.nodeID }
+
+func (in *IfConfigNode) Block() *ast.BlockStmt {
+	in.mut.RLock()
+	defer in.mut.RUnlock()
+	return in.block
+}
+
+func (in *IfConfigNode) Arguments() component.Arguments {
+	in.mut.RLock()
+	defer in.mut.RUnlock()
+	return in.args
+}
+
+func (in *IfConfigNode) ModuleIDs() []string {
+	return in.moduleController.ModuleIDs()
+}
+
+func (in *IfConfigNode) ComponentName() string {
+	return in.componentName
+}
+
+// Exports returns nil as `if` doesn't have the ability to export values.
+func (in *IfConfigNode) Exports() component.Exports {
+	return nil
+}
+
+func (in *IfConfigNode) ID() ComponentID {
+	return in.id
+}
+
+type IfArguments struct {
+	Condition bool `alloy:"condition,attr"`
+}
+
+func (in *IfConfigNode) Evaluate(evalScope *vm.Scope) error {
+	err := in.evaluate(evalScope)
+
+	switch err {
+	case nil:
+		in.setEvalHealth(component.HealthTypeHealthy, "if evaluated")
+	default:
+		msg := fmt.Sprintf("if evaluation failed: %s", err)
+		in.setEvalHealth(component.HealthTypeUnhealthy, msg)
+	}
+	return err
+}
+
+func (in *IfConfigNode) evaluate(scope *vm.Scope) error {
+	in.mut.Lock()
+	defer in.mut.Unlock()
+
+	// Split the body in three parts: the attributes are the arguments of the if block,
+	// the else block is the body of the else branch, and all other blocks are the body of the then branch.
+	// Only the arguments are evaluated here, the branches are evaluated by the custom component.
+	var (
+		argsBody ast.Body
+		thenBody ast.Body
+		elseBody ast.Body
+		elseSeen bool
+	)
+	for _, stmt := range in.block.Body {
+		blockStmt, ok := stmt.(*ast.BlockStmt)
+		switch {
+		case !ok:
+			argsBody = append(argsBody, stmt)
+		case blockStmt.GetBlockName() == elseType:
+			if elseSeen {
+				return fmt.Errorf("the else block can only be defined once in the if block")
+			}
+			if blockStmt.Label != "" {
+				return fmt.Errorf("the else block must not have a label")
+			}
+			elseSeen = true
+			elseBody = blockStmt.Body
+		default:
+			thenBody = append(thenBody, stmt)
+		}
+	}
+
+	eval := vm.New(argsBody)
+
+	var args IfArguments
+	if err := eval.Evaluate(scope, &args); err != nil {
+		return fmt.Errorf("decoding configuration: %w", err)
+	}
+
+	in.args = args
+
+	var (
+		childID string
+		body    ast.Body
+	)
+	switch {
+	case args.Condition:
+		childID, body = ifThenChildID, thenBody
+	case elseSeen:
+		childID, body = ifElseChildID, elseBody
+	}
+
+	// Drop the custom component of the previous branch when the condition changes.
+	// The runner pkg will stop it properly.
+	if childID != in.activeChildID {
+		in.activeChildID = ""
+		in.activeChild = nil
+	}
+
+	if childID != "" {
+		if in.activeChild == nil {
+			cc, err := in.moduleController.NewCustomComponent(childID, func(exports map[string]any) {})
+			if err != nil {
+				return fmt.Errorf("creating custom component: %w", err)
+			}
+			in.activeChildID = childID
+			in.activeChild = cc
+		}
+
+		// Expose the current scope to the branch.
+		customComponentRegistry := NewCustomComponentRegistry(in.customReg, vm.NewScope(deepCopyMap(scope.Variables)))
+		if err := in.activeChild.LoadBody(body, map[string]any{}, customComponentRegistry); err != nil {
+			return fmt.Errorf("updating custom component in if: %w", err)
+		}
+	}
+
+	// Trigger to stop the previous branch from running and to start running the new one.
+	if in.ifChildRunning {
+		select {
+		case in.ifChildUpdateChan <- struct{}{}: // queued trigger
+		default: // trigger already queued; no-op
+		}
+	}
+	return nil
+}
+
+func (in *IfConfigNode) UpdateBlock(b *ast.BlockStmt) {
+	in.mut.Lock()
+	defer in.mut.Unlock()
+	in.block = b
+}
+
+func (in *IfConfigNode) Run(ctx context.Context) error {
+	newCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	runner := runner.New(func(ifChild *ifChild) runner.Worker {
+		return &ifChildRunner{
+			child: ifChild,
+		}
+	})
+	defer runner.Stop()
+
+	updateTasks := func() error {
+		in.mut.Lock()
+		defer in.mut.Unlock()
+		in.ifChildRunning = true
+		var tasks []*ifChild
+		if in.activeChild != nil {
+			tasks = append(tasks, &ifChild{
+				id:           in.activeChildID,
+				cc:           in.activeChild,
+				logger:       log.With(in.logger, "if_path", in.nodeID, "child_id", in.activeChildID),
+				healthUpdate: in.setRunHealth,
+			})
+		}
+
+		// The runner starts new tasks before waiting for the stale ones to exit.
+		// Stop the previous branch first, so that a branch which is activated again
+		// doesn't collide with the module it replaces in the module registry.
+		if err := runner.ApplyTasks(newCtx, stillRunning(runner.Tasks(), tasks)); err != nil {
+			return err
+		}
+		return runner.ApplyTasks(newCtx, tasks)
+	}
+
+	in.setRunHealth(component.HealthTypeHealthy, "started if")
+
+	err := updateTasks()
+	if err != nil {
+		return fmt.Errorf("running if branch failed: %w", err)
+	}
+
+	in.run(ctx, updateTasks)
+	in.setRunHealth(component.HealthTypeExited, "if node shut down cleanly")
+
+	return nil
+}
+
+func (in *IfConfigNode) run(ctx context.Context, updateTasks func() error) {
+	for {
+		select {
+		case <-in.ifChildUpdateChan:
+			err := updateTasks()
+			if err != nil {
+				level.Error(in.logger).Log("msg", "error encountered while updating if branch", "err", err)
+				in.setRunHealth(component.HealthTypeUnhealthy, fmt.Sprintf("error encountered while updating if branch: %s", err))
+				// the error is not fatal, the node can still run in unhealthy mode
+			} else {
+				in.setRunHealth(component.HealthTypeHealthy, "if branch updated successfully")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CurrentHealth returns the current health of the IfConfigNode.
+//
+// The health of an IfConfigNode is determined by combining:
+//
+//  1. Health from the call to Run().
+//  2. Health from the last call to Evaluate().
+func (in *IfConfigNode) CurrentHealth() component.Health {
+	in.healthMut.RLock()
+	defer in.healthMut.RUnlock()
+	return component.LeastHealthy(in.runHealth, in.evalHealth)
+}
+
+func (in *IfConfigNode) setEvalHealth(t component.HealthType, msg string) {
+	in.healthMut.Lock()
+	defer in.healthMut.Unlock()
+
+	in.evalHealth = component.Health{
+		Health:     t,
+		Message:    msg,
+		UpdateTime: time.Now(),
+	}
+}
+
+func (in *IfConfigNode) setRunHealth(t component.HealthType, msg string) {
+	in.healthMut.Lock()
+	defer in.healthMut.Unlock()
+
+	in.runHealth = component.Health{
+		Health:     t,
+		Message:    msg,
+		UpdateTime: time.Now(),
+	}
+}
+
+func (in *IfConfigNode) AddDataFlowEdgeTo(nodeID string) {
+	in.dataFlowEdgeMut.Lock()
+	defer in.dataFlowEdgeMut.Unlock()
+	in.dataFlowEdgeRefs = append(in.dataFlowEdgeRefs, nodeID)
+}
+
+func (in *IfConfigNode) GetDataFlowEdgesTo() []string {
+	in.dataFlowEdgeMut.RLock()
+	defer in.dataFlowEdgeMut.RUnlock()
+	return in.dataFlowEdgeRefs
+}
+
+func (in *IfConfigNode) ResetDataFlowEdgeTo() {
+	in.dataFlowEdgeMut.Lock()
+	defer in.dataFlowEdgeMut.Unlock()
+	in.dataFlowEdgeRefs = []string{}
+}
+
+// stillRunning returns the running tasks which are part of the new tasks.
+func stillRunning(running []*ifChild, tasks []*ifChild) []*ifChild {
+	var res []*ifChild
+	for _, r := range running {
+		for _, t := range tasks {
+			if r.Equals(t) {
+				res = append(res, r)
+				break
+			}
+		}
+	}
+	return res
+}
+
+type ifChildRunner struct {
+	child *ifChild
+}
+
+type ifChild struct {
+	cc           CustomComponent
+	id           string
+	logger       log.Logger
+	healthUpdate func(t component.HealthType, msg string)
+}
+
+func (ir *ifChildRunner) Run(ctx context.Context) {
+	err := ir.child.cc.Run(ctx)
+	if err != nil {
+		level.Error(ir.child.logger).Log("msg", "if branch stopped running", "err", err)
+		ir.child.healthUpdate(component.HealthTypeUnhealthy, fmt.Sprintf("if branch stopped running: %s", err))
+	}
+}
+
+func (ic *ifChild) Hash() uint64 {
+	fnvHash := fnv.New64a()
+	fnvHash.Write([]byte(ic.id))
+	return fnvHash.Sum64()
+}
+
+// Equals compares the custom components too: a branch which is activated again
+// gets a new custom component, which must replace the one that ran before.
+func (ic *ifChild) Equals(other runner.Task) bool {
+	o := other.(*ifChild)
+	return ic.id == o.id && ic.cc == o.cc
+}
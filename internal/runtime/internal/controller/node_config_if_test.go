@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+func TestIfCreateBranch(t *testing.T) {
+	config := `if "default" {
+		condition = enabled
+		else {
+		}
+	}`
+	ifConfigNode := NewIfConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
+	require.NoError(t, ifConfigNode.Evaluate(vm.NewScope(map[string]interface{}{"enabled": true})))
+	require.Equal(t, []string{"then"}, ifConfigNode.moduleController.(*ModuleControllerMock).CustomComponents)
+	require.Equal(t, "then", ifConfigNode.activeChildID)
+
+	// Re-evaluating with the same condition keeps the branch.
+	require.NoError(t, ifConfigNode.Evaluate(vm.NewScope(map[string]interface{}{"enabled": true})))
+	require.Equal(t, []string{"then"}, ifConfigNode.moduleController.(*ModuleControllerMock).CustomComponents)
+
+	require.NoError(t, ifConfigNode.Evaluate(vm.NewScope(map[string]interface{}{"enabled": false})))
+	require.Equal(t, []string{"then", "else"}, ifConfigNode.moduleController.(*ModuleControllerMock).CustomComponents)
+	require.Equal(t, "else", ifConfigNode.activeChildID)
+}
+
+func TestIfWithoutElse(t *testing.T) {
+	config := `if "default" {
+		condition = false
+	}`
+	ifConfigNode := NewIfConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
+	require.NoError(t, ifConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))))
+	require.Empty(t, ifConfigNode.moduleController.(*ModuleControllerMock).CustomComponents)
+	require.Nil(t, ifConfigNode.activeChild)
+}
+
+func TestIfInvalidElse(t *testing.T) {
+	tt := []struct {
+		name        string
+		config      string
+		expectedErr string
+	}{
+		{
+			name: "duplicate else",
+			config: `if "default" {
+				condition = true
+				else {
+				}
+				else {
+				}
+			}`,
+			expectedErr: "the else block can only be defined once in the if block",
+		},
+		{
+			name: "labeled else",
+			config: `if "default" {
+				condition = true
+				else "x" {
+				}
+			}`,
+			expectedErr: "the else block must not have a label",
+		},
+		{
+			name: "condition not a bool",
+			config: `if "default" {
+				condition = "yes"
+			}`,
+			expectedErr: `"yes" should be bool, got string`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ifConfigNode := NewIfConfigNode(getBlockFromConfig(t, tc.config), getComponentGlobals(t), nil)
+			require.ErrorContains(t, ifConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))), tc.expectedErr)
+		})
+	}
+}
+
+func TestIfRunBranchAfterUpdate(t *testing.T) {
+	config := `if "default" {
+		condition = enabled
+	}`
+	ifConfigNode := NewIfConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
+	require.NoError(t, ifConfigNode.Evaluate(vm.NewScope(map[string]interface{}{"enabled": true})))
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go ifConfigNode.Run(ctx)
+
+	first := ifConfigNode.activeChild.(*CustomComponentMock)
+	require.Eventually(t, first.IsRunning.Load, 1*time.Second, 5*time.Millisecond)
+
+	// Disabling the branch stops it.
+	require.NoError(t, ifConfigNode.Evaluate(vm.NewScope(map[string]interface{}{"enabled": false})))
+	require.Eventually(t, func() bool { return !first.IsRunning.Load() }, 1*time.Second, 5*time.Millisecond)
+
+	// Enabling it again runs a new custom component.
+	require.NoError(t, ifConfigNode.Evaluate(vm.NewScope(map[string]interface{}{"enabled": true})))
+	second := ifConfigNode.activeChild.(*CustomComponentMock)
+	require.NotSame(t, first, second)
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.True(c, second.IsRunning.Load())
+		assert.False(c, first.IsRunning.Load())
+	}, 1*time.Second, 5*time.Millisecond)
+}
@@ -40,6 +40,7 @@ type ImportConfigNode struct {
 	globals       ComponentGlobals          // Need a copy of the globals to create other import nodes
 	block         *ast.BlockStmt            // Current Alloy blocks to derive config from
 	source        importsource.ImportSource // source retrieves the module content
+	skip          bool                      // skip the source when validating without network-backed imports
 	registry      *prometheus.Registry
 
 	OnBlockNodeUpdate func(cn BlockNode) // notifies the controller or the parent for reevaluation
@@ -84,6 +85,7 @@ func NewImportConfigNode(block *ast.BlockStmt, globals ComponentGlobals, sourceT
 		block:                    block,
 		OnBlockNodeUpdate:        globals.OnBlockNodeUpdate,
 		importChildrenUpdateChan: make(chan struct{}, 1),
+		skip:                     globals.ValidateOnly && globals.SkipNetworkImports && isNetworkSource(sourceType),
 	}
 	managedOpts := getImportManagedOptions(globals, cn)
 	cn.logger = managedOpts.Logger
@@ -91,6 +93,12 @@ func NewImportConfigNode(block *ast.BlockStmt, globals ComponentGlobals, sourceT
 	return cn
 }
 
+// isNetworkSource returns true if the source type fetches modules over the
+// network.
+func isNetworkSource(sourceType importsource.SourceType) bool {
+	return sourceType == importsource.HTTP || sourceType == importsource.Git
+}
+
 func getImportManagedOptions(globals ComponentGlobals, cn *ImportConfigNode) component.Options {
 	cn.registry = prometheus.NewRegistry()
 	parent, id := splitPath(cn.globalID)
@@ -183,6 +191,11 @@ func (cn *ImportConfigNode) CurrentHealth() component.Health {
 
 // Evaluate implements BlockNode and evaluates the import source.
 func (cn *ImportConfigNode) Evaluate(scope *vm.Scope) error {
+	if cn.skip {
+		cn.setEvalHealth(component.HealthTypeHealthy, "source skipped")
+		return nil
+	}
+
 	err := cn.source.Evaluate(scope)
 	switch err {
 	case nil:
@@ -427,6 +440,10 @@ func (cn *ImportConfigNode) Block() *ast.BlockStmt { return cn.block }
 // NodeID implements dag.Node and returns the unique ID for the config node.
 func (cn *ImportConfigNode) NodeID() string { return cn.nodeID }
 
+// Skipped returns true if the import source was not fetched because
+// network-backed imports are skipped while validating a config.
+func (cn *ImportConfigNode) Skipped() bool { return cn.skip }
+
 // ImportedDeclares returns all declare blocks that it imported.
 func (cn *ImportConfigNode) ImportedDeclares() map[string]ast.Body {
 	cn.mut.RLock()
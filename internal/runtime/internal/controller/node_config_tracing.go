@@ -15,6 +15,7 @@ type TracingConfigNode struct {
 	nodeID        string
 	componentName string
 	traceProvider trace.TracerProvider // Tracer shared between all managed components.
+	validateOnly  bool                 // Decode the block without applying it
 
 	mut   sync.RWMutex
 	block *ast.BlockStmt // Current Alloy blocks to derive config from
@@ -30,6 +31,7 @@ func NewTracingConfigNode(block *ast.BlockStmt, globals ComponentGlobals) *Traci
 		nodeID:        BlockComponentID(block).String(),
 		componentName: block.GetBlockName(),
 		traceProvider: globals.TraceProvider,
+		validateOnly:  globals.ValidateOnly,
 
 		block: block,
 		eval:  vm.New(block.Body),
@@ -43,6 +45,7 @@ func NewDefaulTracingConfigNode(globals ComponentGlobals) *TracingConfigNode {
 		nodeID:        tracingBlockID,
 		componentName: tracingBlockID,
 		traceProvider: globals.TraceProvider,
+		validateOnly:  globals.ValidateOnly,
 
 		block: nil,
 		eval:  nil,
@@ -65,6 +68,10 @@ func (cn *TracingConfigNode) Evaluate(scope *vm.Scope) error {
 		}
 	}
 
+	if cn.validateOnly {
+		return nil
+	}
+
 	t, ok := cn.traceProvider.(*tracing.Tracer)
 	if ok {
 		err := t.Update(args)
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path"
 	"strings"
@@ -43,6 +44,7 @@ type CustomComponentNode struct {
 	eval    *vm.Evaluator
 	managed CustomComponent     // Inner managed custom component
 	args    component.Arguments // Evaluated arguments for the managed component
+	skipped bool                // Set when the definition comes from a skipped import
 
 	// NOTE(rfratto): health and exports have their own mutex because they may be
 	// set asynchronously while mut is still being held (i.e., when calling Evaluate
@@ -202,6 +204,11 @@ func (cn *CustomComponentNode) evaluate(evalScope *vm.Scope) error {
 	}
 
 	template, customComponentRegistry, err := cn.getConfig(cn.importNamespace, cn.customComponentName)
+	if errors.Is(err, errImportSkipped) {
+		cn.skipped = true
+		return nil
+	}
+	cn.skipped = false
 	if err != nil {
 		return fmt.Errorf("loading custom component controller: %w", err)
 	}
@@ -213,6 +220,14 @@ func (cn *CustomComponentNode) evaluate(evalScope *vm.Scope) error {
 	return nil
 }
 
+// Skipped returns true if the custom component wasn't loaded because its
+// definition comes from an import which was skipped while validating a config.
+func (cn *CustomComponentNode) Skipped() bool {
+	cn.mut.RLock()
+	defer cn.mut.RUnlock()
+	return cn.skipped
+}
+
 func (cn *CustomComponentNode) Run(ctx context.Context) error {
 	cn.mut.RLock()
 	managed := cn.managed
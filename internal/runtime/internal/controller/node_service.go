@@ -19,6 +19,10 @@ type ServiceNode struct {
 	svc  service.Service
 	def  service.Definition
 
+	// validateOnly is set when the node is loaded to validate a configuration.
+	// The service's configuration is decoded, but the service isn't updated.
+	validateOnly bool
+
 	mut   sync.RWMutex
 	block *ast.BlockStmt // Current Alloy block to derive args from
 	eval  *vm.Evaluator
@@ -105,6 +109,11 @@ func (sn *ServiceNode) Evaluate(scope *vm.Scope) error {
 	// since services expect a non-pointer.
 	argsCopyValue := reflect.ValueOf(argsPointer).Elem().Interface()
 
+	if sn.validateOnly {
+		sn.args = argsCopyValue
+		return nil
+	}
+
 	if equality.DeepEqual(sn.args, argsCopyValue) {
 		// Ignore arguments which haven't changed. This reduces the cost of calling
 		// evaluate for services where evaluation is expensive (e.g., if
@@ -131,10 +131,12 @@ func newModule(o *moduleOptions) *module {
 	return &module{
 		o: o,
 		f: newController(controllerOptions{
-			IsModule:          true,
-			ModuleRegistry:    o.ModuleRegistry,
-			ComponentRegistry: o.ComponentRegistry,
-			WorkerPool:        o.WorkerPool,
+			IsModule:           true,
+			ModuleRegistry:     o.ModuleRegistry,
+			ComponentRegistry:  o.ComponentRegistry,
+			WorkerPool:         o.WorkerPool,
+			ValidateOnly:       o.ValidateOnly,
+			SkipNetworkImports: o.SkipNetworkImports,
 			Options: Options{
 				ControllerID:         o.ID,
 				Tracer:               o.Tracer,
@@ -230,4 +232,9 @@ type moduleControllerOptions struct {
 
 	// EnableCommunityComps enables the use of community components.
 	EnableCommunityComps bool
+
+	// ValidateOnly and SkipNetworkImports are inherited from the root
+	// controller when it's validating a config.
+	ValidateOnly       bool
+	SkipNetworkImports bool
 }
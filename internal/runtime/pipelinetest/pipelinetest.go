@@ -0,0 +1,230 @@
+// Package pipelinetest implements unit testing of Alloy configuration modules.
+//
+// A test file is written in Alloy syntax and contains one or more test blocks.
+// Each test loads a module (a configuration file using argument and export
+// blocks), passes fixture sinks to the module as arguments, feeds fixture
+// inputs into values exported by the module, and then asserts on what reached
+// the sinks:
+//
+//	test "drops_debug_lines" {
+//		module    = "pipeline.alloy"
+//		arguments = { tenant = "team-a" }
+//
+//		logs_input "receiver" {
+//			entry {
+//				line   = "level=debug msg=noise"
+//				labels = { job = "app" }
+//			}
+//			entry {
+//				line   = "level=info msg=hello"
+//				labels = { job = "app" }
+//			}
+//		}
+//
+//		logs_output "forward_to" {
+//			expect {
+//				line   = "level=info msg=hello"
+//				labels = { job = "app", tenant = "team-a" }
+//			}
+//		}
+//	}
+//
+// Modules are run in an isolated Alloy controller which doesn't have any
+// network-facing services, so components which need the HTTP or remote
+// configuration services can't be tested.
+package pipelinetest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+// File is a parsed test file.
+type File struct {
+	// Path of the test file. Relative paths in tests are resolved against the
+	// directory of Path.
+	Path string
+
+	Tests []Test `alloy:"test,block,optional"`
+}
+
+// Test is an individual test within a test file.
+type Test struct {
+	Name        string         `alloy:",label"`
+	Module      string         `alloy:"module,attr"`
+	Arguments   map[string]any `alloy:"arguments,attr,optional"`
+	Timeout     time.Duration  `alloy:"timeout,attr,optional"`
+	QuietPeriod time.Duration  `alloy:"quiet_period,attr,optional"`
+
+	LogsInputs    []LogsInput    `alloy:"logs_input,block,optional"`
+	MetricsInputs []MetricsInput `alloy:"metrics_input,block,optional"`
+	OTLPInputs    []OTLPInput    `alloy:"otlp_input,block,optional"`
+
+	LogsOutputs    []LogsOutput    `alloy:"logs_output,block,optional"`
+	MetricsOutputs []MetricsOutput `alloy:"metrics_output,block,optional"`
+	OTLPOutputs    []OTLPOutput    `alloy:"otlp_output,block,optional"`
+}
+
+// DefaultTest holds default settings for a Test.
+var DefaultTest = Test{
+	Timeout:     5 * time.Second,
+	QuietPeriod: 250 * time.Millisecond,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (t *Test) SetToDefault() {
+	*t = DefaultTest
+}
+
+// Validate implements syntax.Validator.
+func (t *Test) Validate() error {
+	if t.Module == "" {
+		return fmt.Errorf("module must not be empty")
+	}
+	if t.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than 0")
+	}
+	if t.QuietPeriod < 0 {
+		return fmt.Errorf("quiet_period must not be negative")
+	}
+	if len(t.LogsOutputs)+len(t.MetricsOutputs)+len(t.OTLPOutputs) == 0 {
+		return fmt.Errorf("at least one output block must be provided")
+	}
+
+	seen := make(map[string]struct{})
+	for _, name := range t.outputArguments() {
+		if _, ok := t.Arguments[name]; ok {
+			return fmt.Errorf("argument %q is set both in arguments and by an output block", name)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("argument %q is used by more than one output block", name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
+func (t *Test) outputArguments() []string {
+	var names []string
+	for _, o := range t.LogsOutputs {
+		names = append(names, o.Argument)
+	}
+	for _, o := range t.MetricsOutputs {
+		names = append(names, o.Argument)
+	}
+	for _, o := range t.OTLPOutputs {
+		names = append(names, o.Argument)
+	}
+	return names
+}
+
+// inputExports returns the names of the module exports which inputs are sent
+// to.
+func (t *Test) inputExports() []string {
+	var names []string
+	for _, in := range t.LogsInputs {
+		names = append(names, in.Export)
+	}
+	for _, in := range t.MetricsInputs {
+		names = append(names, in.Export)
+	}
+	for _, in := range t.OTLPInputs {
+		names = append(names, in.Export)
+	}
+	return names
+}
+
+// LogsInput sends log entries to a loki.LogsReceiver exported by the module.
+type LogsInput struct {
+	Export  string     `alloy:",label"`
+	Entries []LogEntry `alloy:"entry,block,optional"`
+}
+
+// LogEntry is a single log entry.
+type LogEntry struct {
+	Line               string            `alloy:"line,attr"`
+	Labels             map[string]string `alloy:"labels,attr,optional"`
+	StructuredMetadata map[string]string `alloy:"structured_metadata,attr,optional"`
+	Timestamp          time.Time         `alloy:"timestamp,attr,optional"`
+}
+
+// MetricsInput appends samples to a storage.Appendable exported by the
+// module.
+type MetricsInput struct {
+	Export  string   `alloy:",label"`
+	Samples []Sample `alloy:"sample,block,optional"`
+}
+
+// Sample is a single Prometheus sample. The metric name is set through the
+// __name__ label.
+type Sample struct {
+	Labels    map[string]string `alloy:"labels,attr"`
+	Value     float64           `alloy:"value,attr"`
+	Timestamp time.Time         `alloy:"timestamp,attr,optional"`
+}
+
+// OTLPInput sends OTLP JSON files to an otelcol.Consumer exported by the
+// module.
+type OTLPInput struct {
+	Export  string `alloy:",label"`
+	Traces  string `alloy:"traces,attr,optional"`
+	Metrics string `alloy:"metrics,attr,optional"`
+	Logs    string `alloy:"logs,attr,optional"`
+}
+
+// LogsOutput passes a list with a single loki.LogsReceiver to the module
+// argument named by Argument and checks the entries it receives.
+type LogsOutput struct {
+	Argument string     `alloy:",label"`
+	Expect   []LogEntry `alloy:"expect,block,optional"`
+}
+
+// MetricsOutput passes a list with a single storage.Appendable to the module
+// argument named by Argument and checks the samples it receives.
+type MetricsOutput struct {
+	Argument string   `alloy:",label"`
+	Expect   []Sample `alloy:"expect,block,optional"`
+}
+
+// OTLPOutput passes a list with a single otelcol.Consumer to the module
+// argument named by Argument and checks the amount of telemetry it receives.
+type OTLPOutput struct {
+	Argument   string `alloy:",label"`
+	Spans      *int   `alloy:"spans,attr,optional"`
+	LogRecords *int   `alloy:"log_records,attr,optional"`
+	DataPoints *int   `alloy:"data_points,attr,optional"`
+}
+
+// LoadFile reads and parses the test file at path.
+func LoadFile(path string) (*File, error) {
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parser.ParseFile(path, bb)
+	if err != nil {
+		return nil, err
+	}
+
+	var tf File
+	if err := vm.New(f).Evaluate(nil, &tf); err != nil {
+		return nil, err
+	}
+	tf.Path = path
+	return &tf, nil
+}
+
+// resolvePath returns path relative to the directory of the test file unless
+// path is absolute.
+func (f *File) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(f.Path), path)
+}
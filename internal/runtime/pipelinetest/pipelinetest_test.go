@@ -0,0 +1,83 @@
+package pipelinetest
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/grafana/alloy/internal/component/loki/process"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+)
+
+func TestLoadFile(t *testing.T) {
+	f, err := LoadFile(filepath.Join("testdata", "pipeline.alloytest"))
+	require.NoError(t, err)
+	require.Len(t, f.Tests, 2)
+
+	test := f.Tests[0]
+	require.Equal(t, "drops_debug_lines", test.Name)
+	require.Equal(t, DefaultTest.Timeout, test.Timeout)
+	require.Len(t, test.LogsInputs, 1)
+	require.Len(t, test.LogsInputs[0].Entries, 2)
+	require.Equal(t, filepath.Join("testdata", "pipeline.alloy"), f.resolvePath(test.Module))
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name        string
+		test        Test
+		expectedErr string
+	}{
+		{
+			name:        "missing output",
+			test:        Test{Module: "m.alloy", Timeout: time.Second},
+			expectedErr: "at least one output block must be provided",
+		},
+		{
+			name: "argument set twice",
+			test: Test{
+				Module:      "m.alloy",
+				Timeout:     time.Second,
+				Arguments:   map[string]any{"forward_to": "x"},
+				LogsOutputs: []LogsOutput{{Argument: "forward_to"}},
+			},
+			expectedErr: `argument "forward_to" is set both in arguments and by an output block`,
+		},
+		{
+			name: "duplicate output",
+			test: Test{
+				Module:         "m.alloy",
+				Timeout:        time.Second,
+				LogsOutputs:    []LogsOutput{{Argument: "forward_to"}},
+				MetricsOutputs: []MetricsOutput{{Argument: "forward_to"}},
+			},
+			expectedErr: `argument "forward_to" is used by more than one output block`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			require.EqualError(t, tc.test.Validate(), tc.expectedErr)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	f, err := LoadFile(filepath.Join("testdata", "pipeline.alloytest"))
+	require.NoError(t, err)
+
+	opts := Options{MinStability: featuregate.StabilityGenerallyAvailable}
+	results := Run(componenttest.TestContext(t), opts, f, nil)
+	require.Len(t, results, 2)
+
+	require.True(t, results[0].Passed(), "failures: %v, err: %v", results[0].Failures, results[0].Err)
+
+	require.NoError(t, results[1].Err)
+	require.Equal(t, []string{
+		`logs_output "forward_to": expected 0 entries, got 1`,
+		`logs_output "forward_to": unexpected entry {tenant="team-a"} "level=info msg=hello"`,
+	}, results[1].Failures)
+}
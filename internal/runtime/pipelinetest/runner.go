@@ -0,0 +1,650 @@
+package pipelinetest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/ckit/peer"
+	"github.com/grafana/loki/pkg/push"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/service"
+	cluster_service "github.com/grafana/alloy/internal/service/cluster"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	otel_service "github.com/grafana/alloy/internal/service/otel"
+)
+
+// pollInterval is how often sinks are checked while waiting for outputs.
+const pollInterval = 10 * time.Millisecond
+
+// sinkStartTimeout is how long to wait for a sink to start running.
+const sinkStartTimeout = 5 * time.Second
+
+// Options configure how tests are run.
+type Options struct {
+	// LogWriter receives logs from the controllers running the modules under
+	// test. Logs are discarded if LogWriter is nil.
+	LogWriter io.Writer
+
+	// MinStability is the minimum stability level of components that can be
+	// used by the modules under test.
+	MinStability featuregate.Stability
+
+	// EnableCommunityComps enables the use of community components.
+	EnableCommunityComps bool
+}
+
+// Result is the outcome of running a single test.
+type Result struct {
+	Name     string
+	Duration time.Duration
+
+	// Err is set when the test couldn't be run, for example because the module
+	// failed to load.
+	Err error
+
+	// Failures holds the list of failed assertions.
+	Failures []string
+}
+
+// Passed returns true if the test ran and all of its assertions passed.
+func (r Result) Passed() bool { return r.Err == nil && len(r.Failures) == 0 }
+
+// Run runs the tests in f sequentially. If filter is non-nil, only tests for
+// which filter returns true are run.
+func Run(ctx context.Context, opts Options, f *File, filter func(name string) bool) []Result {
+	var results []Result
+	for _, t := range f.Tests {
+		if filter != nil && !filter(t.Name) {
+			continue
+		}
+
+		start := time.Now()
+		failures, err := runTest(ctx, opts, f, t)
+		results = append(results, Result{
+			Name:     t.Name,
+			Duration: time.Since(start),
+			Err:      err,
+			Failures: failures,
+		})
+	}
+	return results
+}
+
+func runTest(ctx context.Context, opts Options, f *File, t Test) ([]string, error) {
+	modulePath := f.resolvePath(t.Module)
+	bb, err := os.ReadFile(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	source, err := runtime.ParseSource(modulePath, bb)
+	if err != nil {
+		return nil, err
+	}
+
+	dataPath, err := os.MkdirTemp("", "alloy-test-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dataPath)
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// Build the sinks and pass them to the module as arguments.
+	args := make(map[string]any, len(t.Arguments))
+	maps.Copy(args, t.Arguments)
+
+	logsSinks := make([]*logsSink, len(t.LogsOutputs))
+	for i, o := range t.LogsOutputs {
+		sink, e, err := startSink[*logsSink](ctx, &wg, logsSinkRegistration)
+		if err != nil {
+			return nil, err
+		}
+		logsSinks[i] = sink
+		args[o.Argument] = []loki.LogsReceiver{e.(logsSinkExports).Receiver}
+	}
+	metricsSinks := make([]*metricsSink, len(t.MetricsOutputs))
+	for i, o := range t.MetricsOutputs {
+		sink, e, err := startSink[*metricsSink](ctx, &wg, metricsSinkRegistration)
+		if err != nil {
+			return nil, err
+		}
+		metricsSinks[i] = sink
+		args[o.Argument] = []storage.Appendable{e.(metricsSinkExports).Receiver}
+	}
+	otlpSinks := make([]*otlpSink, len(t.OTLPOutputs))
+	for i, o := range t.OTLPOutputs {
+		sink, e, err := startSink[*otlpSink](ctx, &wg, otlpSinkRegistration)
+		if err != nil {
+			return nil, err
+		}
+		otlpSinks[i] = sink
+		args[o.Argument] = []otelcol.Consumer{e.(otlpSinkExports).Input}
+	}
+
+	exports := newExportsTracker()
+	r, clusterService, err := newController(opts, dataPath, exports.Set)
+	if err != nil {
+		return nil, err
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r.Run(ctx)
+	}()
+
+	if err := r.LoadSource(source, args, modulePath); err != nil {
+		return nil, fmt.Errorf("loading module %s: %w", modulePath, err)
+	}
+	if err := clusterService.ChangeState(ctx, peer.StateParticipant); err != nil {
+		return nil, fmt.Errorf("changing cluster state: %w", err)
+	}
+
+	// Feed the inputs to the module once it exports the values they are sent
+	// to.
+	sendCtx, sendCancel := context.WithTimeout(ctx, t.Timeout)
+	defer sendCancel()
+
+	if err := exports.Wait(sendCtx, t.inputExports()); err != nil {
+		return nil, err
+	}
+	for _, in := range t.LogsInputs {
+		if err := sendLogs(sendCtx, exports, in); err != nil {
+			return nil, fmt.Errorf("logs_input %q: %w", in.Export, err)
+		}
+	}
+	for _, in := range t.MetricsInputs {
+		if err := sendMetrics(sendCtx, exports, in); err != nil {
+			return nil, fmt.Errorf("metrics_input %q: %w", in.Export, err)
+		}
+	}
+	for _, in := range t.OTLPInputs {
+		if err := sendOTLP(sendCtx, f, exports, in); err != nil {
+			return nil, fmt.Errorf("otlp_input %q: %w", in.Export, err)
+		}
+	}
+
+	// Wait for the expected amount of data to reach the sinks, and then wait
+	// for the quiet period to catch any unexpected extra data.
+	satisfied := func() bool {
+		for i, o := range t.LogsOutputs {
+			if len(logsSinks[i].Entries()) < len(o.Expect) {
+				return false
+			}
+		}
+		for i, o := range t.MetricsOutputs {
+			if len(metricsSinks[i].Samples()) < len(o.Expect) {
+				return false
+			}
+		}
+		for i, o := range t.OTLPOutputs {
+			spans, logRecords, dataPoints := otlpSinks[i].Counts()
+			if (o.Spans != nil && spans < *o.Spans) ||
+				(o.LogRecords != nil && logRecords < *o.LogRecords) ||
+				(o.DataPoints != nil && dataPoints < *o.DataPoints) {
+				return false
+			}
+		}
+		return true
+	}
+	waitFor(sendCtx, satisfied)
+	sleepContext(ctx, t.QuietPeriod)
+
+	var failures []string
+	for i, o := range t.LogsOutputs {
+		failures = append(failures, checkLogs(o, logsSinks[i].Entries())...)
+	}
+	for i, o := range t.MetricsOutputs {
+		failures = append(failures, checkMetrics(o, metricsSinks[i].Samples())...)
+	}
+	for i, o := range t.OTLPOutputs {
+		failures = append(failures, checkOTLP(o, otlpSinks[i])...)
+	}
+	return failures, nil
+}
+
+// startSink runs the sink described by reg with a componenttest controller
+// until ctx is canceled. It returns the sink and its exports once the sink is
+// running.
+func startSink[T component.Component](ctx context.Context, wg *sync.WaitGroup, reg component.Registration) (T, component.Exports, error) {
+	var zero T
+
+	ctrl := componenttest.NewControllerFromReg(nil, reg)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = ctrl.Run(ctx, sinkArguments{})
+	}()
+
+	if err := ctrl.WaitRunning(sinkStartTimeout); err != nil {
+		return zero, nil, fmt.Errorf("starting %s: %w", reg.Name, err)
+	}
+	if err := ctrl.WaitExports(sinkStartTimeout); err != nil {
+		return zero, nil, fmt.Errorf("starting %s: %w", reg.Name, err)
+	}
+	sink, err := ctrl.GetComponent()
+	if err != nil {
+		return zero, nil, err
+	}
+	return sink.(T), ctrl.Exports(), nil
+}
+
+// newController builds an Alloy controller to run a module under test.
+func newController(opts Options, dataPath string, onExportsChange func(map[string]any)) (*runtime.Runtime, *cluster_service.Service, error) {
+	w := opts.LogWriter
+	if w == nil {
+		w = io.Discard
+	}
+	l, err := logging.New(w, logging.DefaultOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reg := prometheus.NewRegistry()
+
+	// The cluster service always runs as a single-node cluster so components
+	// which rely on clustering can be loaded.
+	clusterService, err := cluster_service.New(cluster_service.Options{
+		Log:              l,
+		Metrics:          reg,
+		EnableClustering: false,
+		NodeName:         "alloy-test",
+		AdvertiseAddress: "127.0.0.1:80",
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("building cluster service: %w", err)
+	}
+
+	r := runtime.New(runtime.Options{
+		// A controller with an ID is loaded as a module, which is required
+		// for the argument and export blocks of the tested module.
+		ControllerID:         "pipelinetest",
+		Logger:               l,
+		DataPath:             dataPath,
+		Reg:                  reg,
+		MinStability:         opts.MinStability,
+		EnableCommunityComps: opts.EnableCommunityComps,
+		OnExportsChange:      onExportsChange,
+		Services: []service.Service{
+			clusterService,
+			labelstore.New(l, reg),
+			livedebugging.New(),
+			otel_service.New(l),
+		},
+	})
+	return r, clusterService, nil
+}
+
+// exportsTracker holds the latest exports of a module.
+type exportsTracker struct {
+	mut     sync.RWMutex
+	exports map[string]any
+	changed chan struct{} // Closed and replaced every time exports are set.
+}
+
+func newExportsTracker() *exportsTracker {
+	return &exportsTracker{changed: make(chan struct{})}
+}
+
+func (et *exportsTracker) Set(exports map[string]any) {
+	et.mut.Lock()
+	defer et.mut.Unlock()
+	et.exports = exports
+	close(et.changed)
+	et.changed = make(chan struct{})
+}
+
+// Wait blocks until the module exports all of names or ctx is canceled.
+func (et *exportsTracker) Wait(ctx context.Context, names []string) error {
+	for {
+		et.mut.RLock()
+		var missing string
+		for _, name := range names {
+			if _, ok := et.exports[name]; !ok {
+				missing = name
+				break
+			}
+		}
+		changed := et.changed
+		et.mut.RUnlock()
+
+		if missing == "" {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("module does not export %q: %w", missing, ctx.Err())
+		case <-changed:
+		}
+	}
+}
+
+func (et *exportsTracker) Get(name string) (any, error) {
+	et.mut.RLock()
+	defer et.mut.RUnlock()
+
+	v, ok := et.exports[name]
+	if !ok {
+		return nil, fmt.Errorf("module does not export %q", name)
+	}
+	return v, nil
+}
+
+// exportedValues returns the list of values held by the export with the given
+// name. Exports may either hold a single value or a list of values.
+func exportedValues[T any](exports *exportsTracker, name string) ([]T, error) {
+	v, err := exports.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []any
+	switch v := v.(type) {
+	case []any:
+		list = v
+	case []T:
+		return v, nil
+	default:
+		list = []any{v}
+	}
+
+	res := make([]T, 0, len(list))
+	for _, elem := range list {
+		typed, ok := elem.(T)
+		if !ok {
+			return nil, fmt.Errorf("export %q has unexpected type %T", name, elem)
+		}
+		res = append(res, typed)
+	}
+	return res, nil
+}
+
+func sendLogs(ctx context.Context, exports *exportsTracker, in LogsInput) error {
+	receivers, err := exportedValues[loki.LogsReceiver](exports, in.Export)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range in.Entries {
+		entry := loki.Entry{
+			Labels: toLabelSet(e.Labels),
+			Entry: logproto.Entry{
+				Timestamp:          e.Timestamp,
+				Line:               e.Line,
+				StructuredMetadata: toLabelsAdapter(e.StructuredMetadata),
+			},
+		}
+		if entry.Timestamp.IsZero() {
+			entry.Timestamp = time.Now()
+		}
+
+		for _, receiver := range receivers {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("timed out sending log entry: %w", ctx.Err())
+			case receiver.Chan() <- entry.Clone():
+			}
+		}
+	}
+	return nil
+}
+
+func sendMetrics(ctx context.Context, exports *exportsTracker, in MetricsInput) error {
+	appendables, err := exportedValues[storage.Appendable](exports, in.Export)
+	if err != nil {
+		return err
+	}
+
+	for _, appendable := range appendables {
+		app := appendable.Appender(ctx)
+		for _, s := range in.Samples {
+			ts := s.Timestamp
+			if ts.IsZero() {
+				ts = time.Now()
+			}
+			if _, err := app.Append(0, labels.FromMap(s.Labels), ts.UnixMilli(), s.Value); err != nil {
+				_ = app.Rollback()
+				return err
+			}
+		}
+		if err := app.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendOTLP(ctx context.Context, f *File, exports *exportsTracker, in OTLPInput) error {
+	consumers, err := exportedValues[otelcol.Consumer](exports, in.Export)
+	if err != nil {
+		return err
+	}
+
+	if in.Traces != "" {
+		bb, err := os.ReadFile(f.resolvePath(in.Traces))
+		if err != nil {
+			return err
+		}
+		td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(bb)
+		if err != nil {
+			return fmt.Errorf("decoding traces: %w", err)
+		}
+		for _, c := range consumers {
+			if err := c.ConsumeTraces(ctx, td); err != nil {
+				return err
+			}
+		}
+	}
+	if in.Metrics != "" {
+		bb, err := os.ReadFile(f.resolvePath(in.Metrics))
+		if err != nil {
+			return err
+		}
+		md, err := (&pmetric.JSONUnmarshaler{}).UnmarshalMetrics(bb)
+		if err != nil {
+			return fmt.Errorf("decoding metrics: %w", err)
+		}
+		for _, c := range consumers {
+			if err := c.ConsumeMetrics(ctx, md); err != nil {
+				return err
+			}
+		}
+	}
+	if in.Logs != "" {
+		bb, err := os.ReadFile(f.resolvePath(in.Logs))
+		if err != nil {
+			return err
+		}
+		ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(bb)
+		if err != nil {
+			return fmt.Errorf("decoding logs: %w", err)
+		}
+		for _, c := range consumers {
+			if err := c.ConsumeLogs(ctx, ld); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkLogs(o LogsOutput, entries []loki.Entry) []string {
+	var failures []string
+	if len(entries) != len(o.Expect) {
+		failures = append(failures, fmt.Sprintf("logs_output %q: expected %d entries, got %d", o.Argument, len(o.Expect), len(entries)))
+	}
+
+	// Entries may arrive in any order when a pipeline fans out, so match each
+	// expected entry against the first unmatched received entry it describes.
+	matched := make([]bool, len(entries))
+outer:
+	for _, expect := range o.Expect {
+		for i, got := range entries {
+			if !matched[i] && entryMatches(expect, got) {
+				matched[i] = true
+				continue outer
+			}
+		}
+		failures = append(failures, fmt.Sprintf("logs_output %q: missing entry %s", o.Argument, formatExpectedEntry(expect)))
+	}
+
+	var unexpected []string
+	for i, got := range entries {
+		if !matched[i] {
+			unexpected = append(unexpected, fmt.Sprintf("%s %q", got.Labels, got.Line))
+		}
+	}
+	sort.Strings(unexpected)
+	for _, u := range unexpected {
+		failures = append(failures, fmt.Sprintf("logs_output %q: unexpected entry %s", o.Argument, u))
+	}
+	return failures
+}
+
+// entryMatches returns true if got matches expect. Labels and structured
+// metadata are only compared when they're set in expect.
+func entryMatches(expect LogEntry, got loki.Entry) bool {
+	if expect.Line != got.Line {
+		return false
+	}
+	if expect.Labels != nil && !toLabelSet(expect.Labels).Equal(got.Labels) {
+		return false
+	}
+	if expect.StructuredMetadata != nil {
+		gotMetadata := make(map[string]string, len(got.StructuredMetadata))
+		for _, md := range got.StructuredMetadata {
+			gotMetadata[md.Name] = md.Value
+		}
+		if !maps.Equal(expect.StructuredMetadata, gotMetadata) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatExpectedEntry(expect LogEntry) string {
+	res := fmt.Sprintf("%q", expect.Line)
+	if expect.Labels != nil {
+		res = fmt.Sprintf("%s %s", toLabelSet(expect.Labels), res)
+	}
+	if expect.StructuredMetadata != nil {
+		res = fmt.Sprintf("%s with structured metadata %v", res, expect.StructuredMetadata)
+	}
+	return res
+}
+
+func checkMetrics(o MetricsOutput, samples []collectedSample) []string {
+	var failures []string
+	if len(samples) != len(o.Expect) {
+		failures = append(failures, fmt.Sprintf("metrics_output %q: expected %d samples, got %d", o.Argument, len(o.Expect), len(samples)))
+	}
+
+	// Samples may arrive in any order, so match each expected sample against
+	// the first unmatched received sample with the same labels and value.
+	matched := make([]bool, len(samples))
+outer:
+	for _, expect := range o.Expect {
+		expectLabels := labels.FromMap(expect.Labels)
+		for i, got := range samples {
+			if !matched[i] && labels.Equal(expectLabels, got.Labels) && expect.Value == got.Value {
+				matched[i] = true
+				continue outer
+			}
+		}
+		failures = append(failures, fmt.Sprintf("metrics_output %q: missing sample %s %v", o.Argument, expectLabels, expect.Value))
+	}
+
+	var unexpected []string
+	for i, got := range samples {
+		if !matched[i] {
+			unexpected = append(unexpected, fmt.Sprintf("%s %v", got.Labels, got.Value))
+		}
+	}
+	sort.Strings(unexpected)
+	for _, u := range unexpected {
+		failures = append(failures, fmt.Sprintf("metrics_output %q: unexpected sample %s", o.Argument, u))
+	}
+	return failures
+}
+
+func checkOTLP(o OTLPOutput, sink *otlpSink) []string {
+	var failures []string
+	spans, logRecords, dataPoints := sink.Counts()
+
+	check := func(kind string, expect *int, got int) {
+		if expect != nil && *expect != got {
+			failures = append(failures, fmt.Sprintf("otlp_output %q: expected %d %s, got %d", o.Argument, *expect, kind, got))
+		}
+	}
+	check("spans", o.Spans, spans)
+	check("log records", o.LogRecords, logRecords)
+	check("data points", o.DataPoints, dataPoints)
+	return failures
+}
+
+func toLabelSet(m map[string]string) model.LabelSet {
+	ls := make(model.LabelSet, len(m))
+	for k, v := range m {
+		ls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return ls
+}
+
+func toLabelsAdapter(m map[string]string) push.LabelsAdapter {
+	if len(m) == 0 {
+		return nil
+	}
+	res := make(push.LabelsAdapter, 0, len(m))
+	for k, v := range m {
+		res = append(res, push.LabelAdapter{Name: k, Value: v})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// waitFor blocks until cond returns true or ctx is canceled.
+func waitFor(ctx context.Context, cond func() bool) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for !cond() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,269 @@
+package pipelinetest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/storage"
+	otelconsumer "go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+// sinkArguments holds the arguments of every sink. Sinks aren't configurable.
+type sinkArguments struct{}
+
+// Sinks are fake components which collect the data a module under test writes
+// to one of its outputs. They're run by a componenttest.Controller, and their
+// exports are passed to the module as arguments.
+var (
+	logsSinkRegistration = component.Registration{
+		Name:      "pipelinetest.logs_sink",
+		Stability: featuregate.StabilityGenerallyAvailable,
+		Args:      sinkArguments{},
+		Exports:   logsSinkExports{},
+		Build: func(opts component.Options, _ component.Arguments) (component.Component, error) {
+			return newLogsSink(opts), nil
+		},
+	}
+
+	metricsSinkRegistration = component.Registration{
+		Name:      "pipelinetest.metrics_sink",
+		Stability: featuregate.StabilityGenerallyAvailable,
+		Args:      sinkArguments{},
+		Exports:   metricsSinkExports{},
+		Build: func(opts component.Options, _ component.Arguments) (component.Component, error) {
+			return newMetricsSink(opts), nil
+		},
+	}
+
+	otlpSinkRegistration = component.Registration{
+		Name:      "pipelinetest.otlp_sink",
+		Stability: featuregate.StabilityGenerallyAvailable,
+		Args:      sinkArguments{},
+		Exports:   otlpSinkExports{},
+		Build: func(opts component.Options, _ component.Arguments) (component.Component, error) {
+			return newOTLPSink(opts), nil
+		},
+	}
+)
+
+// logsSinkExports holds the values exported by a logs sink.
+type logsSinkExports struct {
+	Receiver loki.LogsReceiver `alloy:"receiver,attr"`
+}
+
+// logsSink collects every entry sent to its exported receiver.
+type logsSink struct {
+	receiver loki.LogsReceiver
+
+	mut     sync.Mutex
+	entries []loki.Entry
+}
+
+var _ component.Component = (*logsSink)(nil)
+
+func newLogsSink(o component.Options) *logsSink {
+	s := &logsSink{receiver: loki.NewLogsReceiver()}
+
+	// Immediately export the receiver which remains the same for the sink
+	// lifetime.
+	o.OnStateChange(logsSinkExports{Receiver: s.receiver})
+	return s
+}
+
+// Run implements component.Component.
+func (s *logsSink) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-s.receiver.Chan():
+			s.mut.Lock()
+			s.entries = append(s.entries, entry)
+			s.mut.Unlock()
+		}
+	}
+}
+
+// Update implements component.Component.
+func (s *logsSink) Update(_ component.Arguments) error { return nil }
+
+func (s *logsSink) Entries() []loki.Entry {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return append([]loki.Entry(nil), s.entries...)
+}
+
+// collectedSample is a sample received by a metricsSink.
+type collectedSample struct {
+	Labels    labels.Labels
+	Timestamp int64
+	Value     float64
+}
+
+// metricsSinkExports holds the values exported by a metrics sink.
+type metricsSinkExports struct {
+	Receiver storage.Appendable `alloy:"receiver,attr"`
+}
+
+// metricsSink collects every float sample committed to its exported
+// receiver. Unlike testappender.CollectingAppender, it keeps every sample
+// rather than the latest sample of each series, so outputs can be checked in
+// order.
+type metricsSink struct {
+	mut     sync.Mutex
+	samples []collectedSample
+}
+
+var (
+	_ component.Component = (*metricsSink)(nil)
+	_ storage.Appendable  = (*metricsSink)(nil)
+)
+
+func newMetricsSink(o component.Options) *metricsSink {
+	s := &metricsSink{}
+	o.OnStateChange(metricsSinkExports{Receiver: s})
+	return s
+}
+
+// Run implements component.Component.
+func (s *metricsSink) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// Update implements component.Component.
+func (s *metricsSink) Update(_ component.Arguments) error { return nil }
+
+// Appender implements storage.Appendable.
+func (s *metricsSink) Appender(_ context.Context) storage.Appender {
+	return &metricsSinkAppender{sink: s}
+}
+
+func (s *metricsSink) Samples() []collectedSample {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return append([]collectedSample(nil), s.samples...)
+}
+
+type metricsSinkAppender struct {
+	sink    *metricsSink
+	pending []collectedSample
+}
+
+var _ storage.Appender = (*metricsSinkAppender)(nil)
+
+func (a *metricsSinkAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	a.pending = append(a.pending, collectedSample{Labels: l, Timestamp: t, Value: v})
+	return ref, nil
+}
+
+func (a *metricsSinkAppender) Commit() error {
+	a.sink.mut.Lock()
+	defer a.sink.mut.Unlock()
+	a.sink.samples = append(a.sink.samples, a.pending...)
+	a.pending = nil
+	return nil
+}
+
+func (a *metricsSinkAppender) Rollback() error {
+	a.pending = nil
+	return nil
+}
+
+func (a *metricsSinkAppender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, _ exemplar.Exemplar) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (a *metricsSinkAppender) UpdateMetadata(ref storage.SeriesRef, _ labels.Labels, _ metadata.Metadata) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (a *metricsSinkAppender) AppendHistogram(ref storage.SeriesRef, _ labels.Labels, _ int64, _ *histogram.Histogram, _ *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+func (a *metricsSinkAppender) AppendCTZeroSample(ref storage.SeriesRef, _ labels.Labels, _, _ int64) (storage.SeriesRef, error) {
+	return ref, nil
+}
+
+// otlpSinkExports holds the values exported by an OTLP sink.
+type otlpSinkExports struct {
+	Input otelcol.Consumer `alloy:"input,attr"`
+}
+
+// otlpSink counts the telemetry sent to its exported consumer.
+type otlpSink struct {
+	mut        sync.Mutex
+	spans      int
+	logRecords int
+	dataPoints int
+}
+
+var (
+	_ component.Component = (*otlpSink)(nil)
+	_ otelcol.Consumer    = (*otlpSink)(nil)
+)
+
+func newOTLPSink(o component.Options) *otlpSink {
+	s := &otlpSink{}
+	o.OnStateChange(otlpSinkExports{Input: s})
+	return s
+}
+
+// Run implements component.Component.
+func (s *otlpSink) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// Update implements component.Component.
+func (s *otlpSink) Update(_ component.Arguments) error { return nil }
+
+// Capabilities implements otelcol.Consumer.
+func (s *otlpSink) Capabilities() otelconsumer.Capabilities {
+	return otelconsumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements otelcol.Consumer.
+func (s *otlpSink) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.spans += td.SpanCount()
+	return nil
+}
+
+// ConsumeMetrics implements otelcol.Consumer.
+func (s *otlpSink) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.dataPoints += md.DataPointCount()
+	return nil
+}
+
+// ConsumeLogs implements otelcol.Consumer.
+func (s *otlpSink) ConsumeLogs(_ context.Context, ld plog.Logs) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.logRecords += ld.LogRecordCount()
+	return nil
+}
+
+// Counts returns the number of spans, log records, and metric data points
+// received so far.
+func (s *otlpSink) Counts() (spans, logRecords, dataPoints int) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.spans, s.logRecords, s.dataPoints
+}
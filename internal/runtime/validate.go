@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/internal/controller"
+	"github.com/grafana/alloy/internal/runtime/internal/importsource"
+	"github.com/grafana/alloy/internal/runtime/internal/worker"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax/diag"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+// ValidateOptions holds options for Validate.
+type ValidateOptions struct {
+	// Logger to use for controller logs. Must not be nil.
+	Logger *logging.Logger
+
+	// MinStability is the minimum stability level of features that can be used
+	// by the validated config.
+	MinStability featuregate.Stability
+
+	// EnableCommunityComps enables the use of community components.
+	EnableCommunityComps bool
+
+	// Services which may be configured by the validated config. Services are
+	// only used for their definitions; they are never updated or run.
+	Services []service.Service
+
+	// SkipNetworkImports skips fetching import.http and import.git modules.
+	// Custom components defined by a skipped import, and anything which
+	// depends on them, aren't validated.
+	SkipNetworkImports bool
+}
+
+// Validate type-checks source without running it. The source is loaded into a
+// controller like LoadSource does: the component graph is built, references
+// between blocks are checked, and the arguments of every component, service,
+// and custom component are decoded. Components are never built or run, so
+// exports of components keep their zero values during validation. Arguments
+// which reference these exports and only fail their own validation are
+// reported as warnings, since they can't be verified before running.
+//
+// configPath is used to resolve relative paths used by import blocks.
+//
+// The returned diagnostics may contain warnings even if there are no errors.
+func Validate(opts ValidateOptions, source *Source, configPath string) diag.Diagnostics {
+	workerPool := worker.NewDefaultWorkerPool()
+
+	f := newController(controllerOptions{
+		Options: Options{
+			Logger:               opts.Logger,
+			MinStability:         opts.MinStability,
+			EnableCommunityComps: opts.EnableCommunityComps,
+			Services:             opts.Services,
+		},
+		ModuleRegistry:     newModuleRegistry(),
+		WorkerPool:         workerPool,
+		ValidateOnly:       true,
+		SkipNetworkImports: opts.SkipNetworkImports,
+	})
+	defer f.loader.Cleanup(true)
+
+	modulePath, err := util.ExtractDirPath(configPath)
+	if err != nil {
+		level.Warn(f.log).Log("msg", "failed to extract directory path from configPath", "configPath", configPath, "err", err)
+	}
+
+	return f.loader.Apply(controller.ApplyOptions{
+		ComponentBlocks: source.components,
+		ConfigBlocks:    source.configBlocks,
+		DeclareBlocks:   source.declareBlocks,
+		ArgScope: vm.NewScope(map[string]interface{}{
+			importsource.ModulePath: modulePath,
+		}),
+	})
+}
@@ -0,0 +1,168 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
+	_ "github.com/grafana/alloy/internal/runtime/internal/testcomponents"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/syntax/diag"
+)
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		name             string
+		config           string
+		expectedErrors   []string
+		expectedWarnings []string
+	}{
+		{
+			name:   "valid",
+			config: testFile,
+		},
+		{
+			name: "invalid argument",
+			config: `
+				testcomponents.tick "ticker" {
+					frequency = true
+				}
+			`,
+			expectedErrors: []string{`true should be string, got bool`},
+		},
+		{
+			name: "missing reference",
+			config: `
+				testcomponents.passthrough "forwarded" {
+					input = testcomponents.passthrough.missing.output
+				}
+			`,
+			expectedErrors: []string{`component "testcomponents.passthrough.missing.output" does not exist or is out of scope`},
+		},
+		{
+			name: "stability level",
+			config: `
+				testcomponents.experimental "exp" { }
+			`,
+			expectedErrors: []string{`component "testcomponents.experimental" is at stability level "experimental", which is below the minimum allowed stability level "public-preview". Use --stability.level command-line flag to enable "experimental" features`},
+		},
+		{
+			name: "skipped network import",
+			config: `
+				import.git "remote" {
+					repository = "https://example.com/modules.git"
+					path       = "module.alloy"
+				}
+
+				remote.component "default" { }
+
+				testcomponents.passthrough "forwarded" {
+					input = remote.component.default.output
+				}
+			`,
+			expectedWarnings: []string{`import.git.remote was not fetched; custom components using it were not validated`},
+		},
+		{
+			name: "unverifiable export reference",
+			config: `
+				testcomponents.passthrough "name" {
+					input = "name"
+				}
+
+				test.validated "default" {
+					name = testcomponents.passthrough.name.output
+				}
+			`,
+			expectedWarnings: []string{`test.validated.default references exports of other components, which are only known at runtime, so its arguments could not be verified: decoding configuration: name must not be empty`},
+		},
+		{
+			name: "invalid argument with export reference",
+			config: `
+				testcomponents.passthrough "name" {
+					input = "name"
+				}
+
+				test.validated "default" {
+					name = [testcomponents.passthrough.name.output]
+				}
+			`,
+			expectedErrors: []string{`[testcomponents.passthrough.name.output] should be string, got array`},
+		},
+		{
+			name: "invalid argument without export reference",
+			config: `
+				test.validated "default" {
+					name = ""
+				}
+			`,
+			expectedErrors: []string{`Failed to build component: decoding configuration: name must not be empty`},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := logging.New(os.Stderr, logging.DefaultOptions)
+			require.NoError(t, err)
+
+			source, err := ParseSource(t.Name(), []byte(tc.config))
+			require.NoError(t, err)
+
+			diags := Validate(ValidateOptions{
+				Logger:             l,
+				MinStability:       featuregate.StabilityPublicPreview,
+				SkipNetworkImports: true,
+			}, source, "")
+
+			var errs, warnings []string
+			for _, d := range diags {
+				switch d.Severity {
+				case diag.SeverityLevelError:
+					errs = append(errs, d.Message)
+				case diag.SeverityLevelWarn:
+					warnings = append(warnings, d.Message)
+				}
+			}
+			require.Equal(t, tc.expectedErrors, errs)
+			require.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "test.validated",
+		Stability: featuregate.StabilityPublicPreview,
+		Args:      validatedArguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return &validatedComponent{}, nil
+		},
+	})
+}
+
+// validatedArguments are the arguments of test.validated, which are rejected
+// by their Validate method when name is empty.
+type validatedArguments struct {
+	Name string `alloy:"name,attr"`
+}
+
+func (args *validatedArguments) Validate() error {
+	if args.Name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	return nil
+}
+
+type validatedComponent struct{}
+
+func (c *validatedComponent) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (c *validatedComponent) Update(_ component.Arguments) error { return nil }
@@ -53,11 +53,12 @@ type Options struct {
 	ReadyFunc  func() bool
 	ReloadFunc func() error
 
-	HTTPListenAddr   string                // Address to listen for HTTP traffic on.
-	MemoryListenAddr string                // Address to accept in-memory traffic on.
-	EnablePProf      bool                  // Whether pprof endpoints should be exposed.
-	MinStability     featuregate.Stability // Minimum stability level to utilize for feature gates
-	BundleContext    SupportBundleContext  // Context for delivering a support bundle
+	HTTPListenAddr    string                // Address to listen for HTTP traffic on.
+	MemoryListenAddr  string                // Address to accept in-memory traffic on.
+	EnablePProf       bool                  // Whether pprof endpoints should be exposed.
+	EnableOpenMetrics bool                  // Whether /metrics serves OpenMetrics to scrapers which request it.
+	MinStability      featuregate.Stability // Minimum stability level to utilize for feature gates
+	BundleContext     SupportBundleContext  // Context for delivering a support bundle
 }
 
 // Arguments holds runtime settings for the HTTP service.
@@ -239,7 +240,12 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 
 	r.Handle(
 		"/metrics",
-		promhttp.HandlerFor(s.gatherer, promhttp.HandlerOpts{}),
+		promhttp.HandlerFor(s.gatherer, promhttp.HandlerOpts{
+			// OpenMetrics is required to expose exemplars, but it changes the
+			// exposition of every metric for scrapers which negotiate it, so
+			// it's opt-in.
+			EnableOpenMetrics: s.opts.EnableOpenMetrics,
+		}),
 	)
 	if s.opts.EnablePProf {
 		r.PathPrefix("/debug/pprof").Handler(http.DefaultServeMux)
@@ -6,17 +6,22 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/loki/process/stages"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/componenttest"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/internal/service/remotecfg"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/phayes/freeport"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
 )
@@ -330,22 +335,132 @@ func TestUnhealthy(t *testing.T) {
 	})
 }
 
+func TestMetricsExemplars(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	env, err := newTestEnvironment(t, func(opts *Options) { opts.EnableOpenMetrics = true })
+	require.NoError(t, err)
+	require.NoError(t, env.ApplyConfig(""))
+
+	// Exemplars are created by stage.metrics from the trace ID of log lines.
+	var stagesCfg struct {
+		Stages []stages.StageConfig `alloy:"stage,enum,optional"`
+	}
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		stage.json {
+			expressions = { "trace_id" = "trace_id" }
+		}
+		stage.metrics {
+			metric.counter {
+				name            = "requests_total"
+				match_all       = true
+				action          = "inc"
+				exemplar_source = "trace_id"
+			}
+		}
+	`), &stagesCfg))
+	pl, err := stages.NewPipeline(util.TestLogger(t), stagesCfg.Stages, nil, env.registry, featuregate.StabilityGenerallyAvailable)
+	require.NoError(t, err)
+
+	in := make(chan stages.Entry, 1)
+	in <- stages.Entry{
+		Extracted: map[string]interface{}{},
+		Entry: loki.Entry{
+			Labels: model.LabelSet{},
+			Entry:  logproto.Entry{Timestamp: time.Now(), Line: `{"trace_id": "abc123"}`},
+		},
+	}
+	close(in)
+	for range pl.Run(in) {
+	}
+
+	go func() {
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	scrape := func(t require.TestingT, accept string) (string, string) {
+		cli, err := config.NewClientFromConfig(config.HTTPClientConfig{}, "test")
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/metrics", env.ListenAddr()), nil)
+		require.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		resp, err := cli.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		buf, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.Header.Get("Content-Type"), string(buf)
+	}
+
+	// Scrapers which request OpenMetrics receive the exemplars when it's
+	// enabled.
+	util.Eventually(t, func(t require.TestingT) {
+		contentType, body := scrape(t, "application/openmetrics-text;version=1.0.0")
+		require.Contains(t, contentType, "application/openmetrics-text")
+		require.Contains(t, body, `loki_process_custom_requests_total 1.0 # {trace_id="abc123"} 1.0`)
+	})
+
+	// Other scrapers still receive the Prometheus text format, without
+	// exemplars.
+	contentType, body := scrape(t, "")
+	require.Contains(t, contentType, "text/plain")
+	require.Contains(t, body, "loki_process_custom_requests_total 1\n")
+	require.NotContains(t, body, "abc123")
+}
+
+func TestMetricsOpenMetricsDisabled(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	env, err := newTestEnvironment(t)
+	require.NoError(t, err)
+	require.NoError(t, env.ApplyConfig(""))
+
+	go func() {
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	// The Prometheus text format is served even to scrapers which request
+	// OpenMetrics.
+	util.Eventually(t, func(t require.TestingT) {
+		cli, err := config.NewClientFromConfig(config.HTTPClientConfig{}, "test")
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/metrics", env.ListenAddr()), nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0")
+
+		resp, err := cli.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	})
+}
+
 type testEnvironment struct {
 	svc        *Service
 	addr       string
+	registry   *prometheus.Registry
 	components []*component.Info
 }
 
-func newTestEnvironment(t *testing.T) (*testEnvironment, error) {
+func newTestEnvironment(t *testing.T, setOpts ...func(*Options)) (*testEnvironment, error) {
 	port, err := freeport.GetFreePort()
 	if err != nil {
 		return nil, err
 	}
 
-	svc := New(Options{
+	registry := prometheus.NewRegistry()
+	opts := Options{
 		Logger:   util.TestAlloyLogger(t),
 		Tracer:   noop.NewTracerProvider(),
-		Gatherer: prometheus.NewRegistry(),
+		Gatherer: registry,
 
 		ReadyFunc:  func() bool { return true },
 		ReloadFunc: func() error { return nil },
@@ -353,11 +468,16 @@ func newTestEnvironment(t *testing.T) (*testEnvironment, error) {
 		HTTPListenAddr:   fmt.Sprintf("127.0.0.1:%d", port),
 		MemoryListenAddr: "alloy.internal:12345",
 		EnablePProf:      true,
-	})
+	}
+	for _, set := range setOpts {
+		set(&opts)
+	}
+	svc := New(opts)
 
 	return &testEnvironment{
-		svc:  svc,
-		addr: fmt.Sprintf("127.0.0.1:%d", port),
+		svc:      svc,
+		addr:     fmt.Sprintf("127.0.0.1:%d", port),
+		registry: registry,
 	}, nil
 }
 
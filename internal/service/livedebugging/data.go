@@ -1,5 +1,10 @@
 package livedebugging
 
+import (
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
 type DataType string
 
 const (
@@ -9,6 +14,7 @@ const (
 	OtelMetric       DataType = "otel_metric"
 	OtelLog          DataType = "otel_log"
 	OtelTrace        DataType = "otel_trace"
+	Profile          DataType = "profile"
 )
 
 type DataOption func(Data) Data
@@ -20,6 +26,40 @@ func WithTargetComponentIDs(ids []string) DataOption {
 	}
 }
 
+// WithLabels sets the label sets that the data can be filtered on.
+func WithLabels(labelsFunc func() []map[string]string) DataOption {
+	return func(d Data) Data {
+		d.LabelsFunc = labelsFunc
+		return d
+	}
+}
+
+// WithLabelSets sets the Loki label sets that the data can be filtered on.
+func WithLabelSets(sets ...model.LabelSet) DataOption {
+	return WithLabels(func() []map[string]string {
+		res := make([]map[string]string, 0, len(sets))
+		for _, set := range sets {
+			m := make(map[string]string, len(set))
+			for name, value := range set {
+				m[string(name)] = string(value)
+			}
+			res = append(res, m)
+		}
+		return res
+	})
+}
+
+// WithPrometheusLabels sets the Prometheus label sets that the data can be filtered on.
+func WithPrometheusLabels(lbls ...labels.Labels) DataOption {
+	return WithLabels(func() []map[string]string {
+		res := make([]map[string]string, 0, len(lbls))
+		for _, l := range lbls {
+			res = append(res, l.Map())
+		}
+		return res
+	})
+}
+
 type Data struct {
 	// ID of the component that created the data.
 	ComponentID ComponentID
@@ -29,10 +69,13 @@ type Data struct {
 	// from the component that created it.
 	TargetComponentIDs []string
 	Type               DataType
-	// Count is the number of spans, metrics, logs that the data represent.
+	// Count is the number of spans, metrics, logs, profiles that the data represent.
 	Count uint64
 	// The data string is passed as a function to only compute the string if needed.
 	DataFunc func() string
+	// LabelsFunc returns the label sets of the data, such as the labels of a log entry or the attributes of OTel data.
+	// It is only called when the data is filtered by a live debugging consumer, and may be nil.
+	LabelsFunc func() []map[string]string
 }
 
 func NewData(componentID ComponentID, dataType DataType, count uint64, dataFunc func() string, opts ...DataOption) Data {
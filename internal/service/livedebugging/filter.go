@@ -0,0 +1,320 @@
+package livedebugging
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/regexp"
+	"github.com/prometheus/prometheus/model/labels"
+	"golang.org/x/time/rate"
+)
+
+// StreamOptions select which data is sent to a live debugging consumer.
+type StreamOptions struct {
+	// Filter selects the data to send. All data is selected if Filter is nil.
+	Filter *Filter
+	// SampleProb is the probability for selected data to be sent, between 0 and 1.
+	SampleProb float64
+	// MaxItemsPerSecond limits how much data is sent per second. There is no limit if it is 0.
+	MaxItemsPerSecond int
+}
+
+// Wrap returns a callback which only forwards the data selected by the
+// options to callback. The returned callback is meant to be registered with
+// a CallbackManager so that the options are enforced when data is published.
+func (o StreamOptions) Wrap(callback func(Data)) func(Data) {
+	var limiter *rate.Limiter
+	if o.MaxItemsPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(o.MaxItemsPerSecond), o.MaxItemsPerSecond)
+	}
+
+	return func(data Data) {
+		if o.SampleProb < 1 && rand.Float64() > o.SampleProb {
+			return
+		}
+		if o.Filter != nil {
+			// The filter may need the data string, keep it so that it's only computed once.
+			var (
+				text     string
+				computed bool
+				dataFunc = data.DataFunc
+			)
+			data.DataFunc = func() string {
+				if !computed {
+					text, computed = dataFunc(), true
+				}
+				return text
+			}
+			if !o.Filter.Matches(data) {
+				return
+			}
+		}
+		if limiter != nil && !limiter.Allow() {
+			return
+		}
+		callback(data)
+	}
+}
+
+// Filter selects live debugging data with label matchers and line filters,
+// similar to a LogQL query:
+//
+//	http_requests_total{job="api", status=~"5.."} |= "timeout" != "healthcheck"
+//
+// The selector is optional and may start with a metric name, which matches the
+// __name__ label. Label names may contain any character other than spaces,
+// quotes and the `{}=!~,` characters, so that OpenTelemetry attributes such as
+// service.name can be matched. Line filters match the data as it is shown on
+// the live debugging stream.
+type Filter struct {
+	matchers    []*labels.Matcher
+	lineFilters []lineFilter
+}
+
+type lineFilter struct {
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+// Matches returns true if the data is selected by the filter. The label
+// matchers must all match at least one of the label sets of the data, and
+// every line filter must match.
+func (f *Filter) Matches(data Data) bool {
+	if len(f.matchers) > 0 && !f.matchesLabels(data) {
+		return false
+	}
+	if len(f.lineFilters) == 0 {
+		return true
+	}
+
+	text := data.DataFunc()
+	for _, lf := range f.lineFilters {
+		if !lf.matches(text) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *Filter) matchesLabels(data Data) bool {
+	var sets []map[string]string
+	if data.LabelsFunc != nil {
+		sets = data.LabelsFunc()
+	}
+	if len(sets) == 0 {
+		// Data without labels is matched against an empty label set.
+		sets = []map[string]string{nil}
+	}
+
+	for _, set := range sets {
+		if matchesAll(f.matchers, set) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(matchers []*labels.Matcher, set map[string]string) bool {
+	for _, m := range matchers {
+		if !m.Matches(set[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (lf lineFilter) matches(text string) bool {
+	switch lf.op {
+	case "|=":
+		return strings.Contains(text, lf.value)
+	case "!=":
+		return !strings.Contains(text, lf.value)
+	case "|~":
+		return lf.re.MatchString(text)
+	case "!~":
+		return !lf.re.MatchString(text)
+	}
+	return false
+}
+
+// ParseFilter parses a filter expression. See Filter for the syntax.
+func ParseFilter(expr string) (*Filter, error) {
+	p := &filterParser{input: expr}
+	f, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+	return f, nil
+}
+
+type filterParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterParser) parse() (*Filter, error) {
+	var f Filter
+
+	p.skipSpace()
+	if !p.eof() && !strings.ContainsRune("{|!", p.peek()) {
+		name := p.readName()
+		if name == "" {
+			return nil, fmt.Errorf("unexpected character %q at position %d", p.peek(), p.pos)
+		}
+		f.matchers = append(f.matchers, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, name))
+		p.skipSpace()
+	}
+
+	if !p.eof() && p.peek() == '{' {
+		p.pos++
+		matchers, err := p.parseMatchers()
+		if err != nil {
+			return nil, err
+		}
+		f.matchers = append(f.matchers, matchers...)
+	}
+
+	for {
+		p.skipSpace()
+		if p.eof() {
+			break
+		}
+		op := p.readOp("|=", "!=", "|~", "!~")
+		if op == "" {
+			return nil, fmt.Errorf("expected a line filter operator at position %d", p.pos)
+		}
+		p.skipSpace()
+		value, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		lf := lineFilter{op: op, value: value}
+		if op == "|~" || op == "!~" {
+			if lf.re, err = regexp.Compile(value); err != nil {
+				return nil, err
+			}
+		}
+		f.lineFilters = append(f.lineFilters, lf)
+	}
+
+	return &f, nil
+}
+
+func (p *filterParser) parseMatchers() ([]*labels.Matcher, error) {
+	var matchers []*labels.Matcher
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil, fmt.Errorf("missing closing brace")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return matchers, nil
+		}
+
+		name := p.readName()
+		if name == "" {
+			return nil, fmt.Errorf("expected a label name at position %d", p.pos)
+		}
+		p.skipSpace()
+
+		var matchType labels.MatchType
+		switch p.readOp("=~", "!~", "!=", "=") {
+		case "=":
+			matchType = labels.MatchEqual
+		case "!=":
+			matchType = labels.MatchNotEqual
+		case "=~":
+			matchType = labels.MatchRegexp
+		case "!~":
+			matchType = labels.MatchNotRegexp
+		default:
+			return nil, fmt.Errorf("expected a matcher operator after %q", name)
+		}
+		p.skipSpace()
+
+		value, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		m, err := labels.NewMatcher(matchType, name, value)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+
+		p.skipSpace()
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		} else if p.eof() || p.peek() != '}' {
+			return nil, fmt.Errorf("expected ',' or '}' at position %d", p.pos)
+		}
+	}
+}
+
+func (p *filterParser) eof() bool { return p.pos >= len(p.input) }
+
+func (p *filterParser) peek() rune { return rune(p.input[p.pos]) }
+
+func (p *filterParser) skipSpace() {
+	for !p.eof() && strings.ContainsRune(" \t\r\n", p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *filterParser) readName() string {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\r\n\"`{}=!~,|", p.peek()) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *filterParser) readOp(ops ...string) string {
+	for _, op := range ops {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// readString reads a double-quoted string with Go escape sequences or a raw
+// string between backticks.
+func (p *filterParser) readString() (string, error) {
+	if p.eof() {
+		return "", fmt.Errorf("expected a string at the end of the filter")
+	}
+
+	switch quote := p.input[p.pos]; quote {
+	case '`':
+		end := strings.IndexByte(p.input[p.pos+1:], '`')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated raw string at position %d", p.pos)
+		}
+		value := p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return value, nil
+	case '"':
+		for i := p.pos + 1; i < len(p.input); i++ {
+			switch p.input[i] {
+			case '\\':
+				i++
+			case '"':
+				value, err := strconv.Unquote(p.input[p.pos : i+1])
+				if err != nil {
+					return "", fmt.Errorf("invalid string at position %d: %w", p.pos, err)
+				}
+				p.pos = i + 1
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("unterminated string at position %d", p.pos)
+	default:
+		return "", fmt.Errorf("expected a string at position %d", p.pos)
+	}
+}
@@ -0,0 +1,196 @@
+package livedebugging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	tt := []struct {
+		name        string
+		expr        string
+		expectedErr string
+	}{
+		{name: "empty", expr: ""},
+		{name: "metric name", expr: "http_requests_total"},
+		{name: "metric name and matchers", expr: `http_requests_total{job="api", status=~"5.."}`},
+		{name: "attribute names with dots", expr: `{service.name="checkout", http.status_code!="200"}`},
+		{name: "line filters", expr: "|= \"timeout\" != `healthcheck` |~ \"err(or)?\" !~ \"debug\""},
+		{name: "matchers and line filters", expr: `{job="api"} |= "timeout"`},
+		{name: "empty selector", expr: `{}`},
+		{name: "missing closing brace", expr: `{job="api",`, expectedErr: "missing closing brace"},
+		{name: "missing operator", expr: `{job}`, expectedErr: `expected a matcher operator after "job"`},
+		{name: "unquoted value", expr: `{job=api}`, expectedErr: "expected a string at position 5"},
+		{name: "missing comma", expr: `{job="api" env="dev"}`, expectedErr: "expected ',' or '}' at position 11"},
+		{name: "unterminated string", expr: `|= "timeout`, expectedErr: "unterminated string at position 3"},
+		{name: "invalid line filter operator", expr: `{job="api"} == "x"`, expectedErr: "expected a line filter operator at position 12"},
+		{name: "invalid matcher regexp", expr: `{job=~"("}`, expectedErr: "missing closing )"},
+		{name: "invalid line filter regexp", expr: `|~ "("`, expectedErr: "missing closing )"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseFilter(tc.expr)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	newData := func(text string, sets ...map[string]string) Data {
+		return NewData("test", LokiLog, 1, func() string { return text }, WithLabels(func() []map[string]string { return sets }))
+	}
+
+	tt := []struct {
+		name     string
+		expr     string
+		data     Data
+		expected bool
+	}{
+		{
+			name:     "empty filter",
+			expr:     "",
+			data:     newData("anything"),
+			expected: true,
+		},
+		{
+			name:     "matching label",
+			expr:     `{job="api"}`,
+			data:     newData("entry", map[string]string{"job": "api"}),
+			expected: true,
+		},
+		{
+			name:     "not matching label",
+			expr:     `{job="api"}`,
+			data:     newData("entry", map[string]string{"job": "db"}),
+			expected: false,
+		},
+		{
+			name:     "regexp and negative matchers",
+			expr:     `{status=~"5..", env!="dev"}`,
+			data:     newData("entry", map[string]string{"status": "503", "env": "prod"}),
+			expected: true,
+		},
+		{
+			name:     "matchers must match the same label set",
+			expr:     `{job="api", env="prod"}`,
+			data:     newData("entry", map[string]string{"job": "api"}, map[string]string{"env": "prod"}),
+			expected: false,
+		},
+		{
+			name:     "any label set matches",
+			expr:     `{job="api"}`,
+			data:     newData("entry", map[string]string{"job": "db"}, map[string]string{"job": "api"}),
+			expected: true,
+		},
+		{
+			name:     "metric name",
+			expr:     `http_requests_total{job="api"}`,
+			data:     newData("sample", map[string]string{"__name__": "http_requests_total", "job": "api"}),
+			expected: true,
+		},
+		{
+			name:     "other metric name",
+			expr:     `http_requests_total`,
+			data:     newData("sample", map[string]string{"__name__": "up"}),
+			expected: false,
+		},
+		{
+			name:     "data without labels matches an empty label set",
+			expr:     `{job=""}`,
+			data:     NewData("test", LokiLog, 1, func() string { return "entry" }),
+			expected: true,
+		},
+		{
+			name:     "line filters",
+			expr:     `|= "timeout" != "healthcheck"`,
+			data:     newData("request timeout"),
+			expected: true,
+		},
+		{
+			name:     "negative line filter",
+			expr:     `|= "timeout" != "healthcheck"`,
+			data:     newData("healthcheck timeout"),
+			expected: false,
+		},
+		{
+			name:     "regexp line filters",
+			expr:     `|~ "status=5\\d\\d" !~ "(?i)debug"`,
+			data:     newData("status=502 level=info"),
+			expected: true,
+		},
+		{
+			name:     "labels and line filter",
+			expr:     "{job=\"api\"} |= `timeout`",
+			data:     newData("healthcheck", map[string]string{"job": "api"}),
+			expected: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := ParseFilter(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, filter.Matches(tc.data))
+		})
+	}
+}
+
+func TestStreamOptionsWrap(t *testing.T) {
+	newData := func(text string) Data {
+		return NewData("test", LokiLog, 1, func() string { return text })
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		filter, err := ParseFilter(`|= "error"`)
+		require.NoError(t, err)
+
+		var received []string
+		callback := StreamOptions{Filter: filter, SampleProb: 1}.Wrap(func(data Data) {
+			received = append(received, data.DataFunc())
+		})
+		callback(newData("level=info"))
+		callback(newData("level=error"))
+		require.Equal(t, []string{"level=error"}, received)
+	})
+
+	t.Run("data is only computed once", func(t *testing.T) {
+		filter, err := ParseFilter(`|= "error"`)
+		require.NoError(t, err)
+
+		calls := 0
+		callback := StreamOptions{Filter: filter, SampleProb: 1}.Wrap(func(data Data) {
+			data.DataFunc()
+		})
+		callback(NewData("test", LokiLog, 1, func() string {
+			calls++
+			return "error"
+		}))
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		count := 0
+		callback := StreamOptions{SampleProb: 0}.Wrap(func(Data) { count++ })
+		for range 100 {
+			callback(newData("entry"))
+		}
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("max items per second", func(t *testing.T) {
+		count := 0
+		callback := StreamOptions{SampleProb: 1, MaxItemsPerSecond: 10}.Wrap(func(Data) { count++ })
+		for range 100 {
+			callback(newData("entry"))
+		}
+		// The burst of the limiter is the number of items per second.
+		require.LessOrEqual(t, count, 11)
+		require.GreaterOrEqual(t, count, 10)
+	})
+}
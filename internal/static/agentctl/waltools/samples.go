@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/model/timestamp"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/prometheus/prometheus/tsdb/chunks"
@@ -131,3 +134,149 @@ func collectSamples(r *wlog.Reader, labelsByRef map[chunks.HeadSeriesRef]labels.
 
 	return r.Err()
 }
+
+// Sample is a sample read from the WAL. H or FH is set for native histogram
+// samples, Exemplar for exemplars, Metadata for the metadata of the series,
+// and V is set for float samples.
+type Sample struct {
+	T        int64
+	V        float64
+	H        *histogram.Histogram
+	FH       *histogram.FloatHistogram
+	Exemplar *exemplar.Exemplar
+	Metadata *metadata.Metadata
+}
+
+// ReadSamples reads the latest checkpoint and the segments of the WAL in order,
+// and calls f for every sample and exemplar of the series matching the label
+// selector with a timestamp between mint and maxt inclusive, and for every
+// metadata record of these series. It returns the number of samples with a ref
+// ID to which there is no series defined.
+func ReadSamples(walDir string, selectorStr string, mint, maxt int64, f func(l labels.Labels, s Sample) error) (int, error) {
+	w, err := wlog.Open(nil, walDir)
+	if err != nil {
+		return 0, err
+	}
+	defer w.Close()
+
+	matchers, err := parser.ParseMetricSelector(selectorStr)
+	if err != nil {
+		return 0, err
+	}
+	selector := labels.Selector(matchers)
+
+	var (
+		labelsByRef = make(map[chunks.HeadSeriesRef]labels.Labels)
+		skippedRefs = make(map[chunks.HeadSeriesRef]struct{})
+		invalidRefs int
+	)
+
+	// lookup returns the labels of a series, and whether its samples should be
+	// passed to f.
+	lookup := func(ref chunks.HeadSeriesRef, t int64) (labels.Labels, bool) {
+		if l, ok := labelsByRef[ref]; ok {
+			return l, t >= mint && t <= maxt
+		}
+		if _, ok := skippedRefs[ref]; !ok {
+			invalidRefs++
+		}
+		return labels.EmptyLabels(), false
+	}
+
+	err = walIterate(w, func(r *wlog.Reader) error {
+		var dec record.Decoder
+
+		for r.Next() {
+			rec := r.Record()
+
+			switch dec.Type(rec) {
+			case record.Series:
+				series, err := dec.Series(rec, nil)
+				if err != nil {
+					return err
+				}
+				for _, s := range series {
+					if selector.Matches(s.Labels) {
+						labelsByRef[s.Ref] = s.Labels.Copy()
+					} else {
+						skippedRefs[s.Ref] = struct{}{}
+					}
+				}
+
+			case record.Samples:
+				samples, err := dec.Samples(rec, nil)
+				if err != nil {
+					return err
+				}
+				for _, s := range samples {
+					if l, ok := lookup(s.Ref, s.T); ok {
+						if err := f(l, Sample{T: s.T, V: s.V}); err != nil {
+							return err
+						}
+					}
+				}
+
+			case record.HistogramSamples:
+				samples, err := dec.HistogramSamples(rec, nil)
+				if err != nil {
+					return err
+				}
+				for _, s := range samples {
+					if l, ok := lookup(s.Ref, s.T); ok {
+						if err := f(l, Sample{T: s.T, H: s.H}); err != nil {
+							return err
+						}
+					}
+				}
+
+			case record.FloatHistogramSamples:
+				samples, err := dec.FloatHistogramSamples(rec, nil)
+				if err != nil {
+					return err
+				}
+				for _, s := range samples {
+					if l, ok := lookup(s.Ref, s.T); ok {
+						if err := f(l, Sample{T: s.T, FH: s.FH}); err != nil {
+							return err
+						}
+					}
+				}
+
+			case record.Exemplars:
+				exemplars, err := dec.Exemplars(rec, nil)
+				if err != nil {
+					return err
+				}
+				for _, e := range exemplars {
+					if l, ok := lookup(e.Ref, e.T); ok {
+						ex := exemplar.Exemplar{Labels: e.Labels.Copy(), Value: e.V, Ts: e.T, HasTs: true}
+						if err := f(l, Sample{T: e.T, Exemplar: &ex}); err != nil {
+							return err
+						}
+					}
+				}
+
+			case record.Metadata:
+				meta, err := dec.Metadata(rec, nil)
+				if err != nil {
+					return err
+				}
+				for _, m := range meta {
+					// Metadata isn't bound to a time, so it's only filtered by the
+					// label selector.
+					l, ok := labelsByRef[m.Ref]
+					if !ok {
+						continue
+					}
+					md := metadata.Metadata{Type: record.ToMetricType(m.Type), Unit: m.Unit, Help: m.Help}
+					if err := f(l, Sample{Metadata: &md}); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		return r.Err()
+	})
+	return invalidRefs, err
+}
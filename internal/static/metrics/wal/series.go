@@ -9,6 +9,7 @@ import (
 
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/tsdb/chunks"
 )
 
@@ -21,6 +22,10 @@ type memSeries struct {
 
 	// Last recorded timestamp. Used by gc to determine if a series is stale.
 	lastTs int64
+
+	// Last metadata written to the WAL for the series. Nil if no metadata has
+	// been written yet.
+	meta *metadata.Metadata
 }
 
 // updateTimestamp obtains the lock on s and will attempt to update lastTs.
@@ -174,6 +174,7 @@ func NewStorage(logger log.Logger, registerer prometheus.Registerer, path string
 			pendingHistograms:      make([]record.RefHistogramSample, 0, 100),
 			pendingFloatHistograms: make([]record.RefFloatHistogramSample, 0, 100),
 			pendingExamplars:       make([]record.RefExemplar, 0, 10),
+			pendingMetadata:        make([]record.RefMetadata, 0, 10),
 		}
 	}
 
@@ -345,8 +346,10 @@ func (w *Storage) loadWAL(r *wlog.Reader, multiRef map[chunks.HeadSeriesRef]chun
 					return
 				}
 				decoded <- floatHistograms
-			case record.Tombstones, record.Exemplars:
-				// We don't care about decoding tombstones or exemplars
+			case record.Tombstones, record.Exemplars, record.Metadata:
+				// We don't care about decoding tombstones, exemplars or metadata.
+				// Metadata is logged again the first time it's updated after a
+				// restart.
 				// TODO: If decide to decode exemplars, we should make sure to prepopulate
 				// stripeSeries.exemplars in the next block by using setLatestExemplar.
 				continue
@@ -660,6 +663,7 @@ type appender struct {
 	pendingExamplars       []record.RefExemplar
 	pendingHistograms      []record.RefHistogramSample
 	pendingFloatHistograms []record.RefFloatHistogramSample
+	pendingMetadata        []record.RefMetadata
 
 	// Pointers to the series referenced by each element of pendingSamples.
 	// Series lock is not held on elements.
@@ -672,6 +676,10 @@ type appender struct {
 	// Pointers to the series referenced by each element of pendingFloatHistograms.
 	// Series lock is not held on elements.
 	floatHistogramSeries []*memSeries
+
+	// Pointers to the series referenced by each element of pendingMetadata.
+	// Series lock is not held on elements.
+	metadataSeries []*memSeries
 }
 
 var _ storage.Appender = (*appender)(nil)
@@ -855,14 +863,90 @@ func (a *appender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int
 	return storage.SeriesRef(series.ref), nil
 }
 
-func (a *appender) AppendCTZeroSample(_ storage.SeriesRef, _ labels.Labels, _ int64, _ int64) (storage.SeriesRef, error) {
-	// TODO(ptodev): implement this later
-	return 0, nil
+// AppendCTZeroSample appends a sample with a value of zero at the created
+// timestamp ct of the series, so that Remote Write 2.0 receivers can learn
+// when a counter was reset.
+func (a *appender) AppendCTZeroSample(ref storage.SeriesRef, l labels.Labels, t, ct int64) (storage.SeriesRef, error) {
+	if ct >= t {
+		// This mirrors the error of the TSDB's headAppender.
+		return 0, fmt.Errorf("CT is newer or the same as sample's timestamp, ignoring")
+	}
+
+	series := a.w.series.GetByID(chunks.HeadSeriesRef(ref))
+	if series == nil {
+		// Ensure no empty or duplicate labels have gotten through. This mirrors the
+		// equivalent validation code in the TSDB's headAppender.
+		l = l.WithoutEmpty()
+		if len(l) == 0 {
+			return 0, fmt.Errorf("empty labelset: %w", tsdb.ErrInvalidSample)
+		}
+
+		if lbl, dup := l.HasDuplicateLabelNames(); dup {
+			return 0, fmt.Errorf("label name %q is not unique: %w", lbl, tsdb.ErrInvalidSample)
+		}
+
+		var created bool
+		series, created = a.getOrCreate(l)
+		if created {
+			a.pendingSeries = append(a.pendingSeries, record.RefSeries{
+				Ref:    series.ref,
+				Labels: l,
+			})
+
+			a.w.metrics.numActiveSeries.Inc()
+			a.w.metrics.totalCreatedSeries.Inc()
+		}
+	}
+
+	series.Lock()
+	defer series.Unlock()
+
+	// The zero sample must not be out of order with the samples we already
+	// have for this series.
+	if ct <= series.lastTs {
+		return 0, storage.ErrOutOfOrderCT
+	}
+
+	// NOTE(rfratto): always modify pendingSamples and sampleSeries together.
+	a.pendingSamples = append(a.pendingSamples, record.RefSample{
+		Ref: series.ref,
+		T:   ct,
+		V:   0,
+	})
+	a.sampleSeries = append(a.sampleSeries, series)
+
+	a.w.metrics.totalAppendedSamples.Inc()
+	return storage.SeriesRef(series.ref), nil
 }
 
-func (a *appender) UpdateMetadata(ref storage.SeriesRef, _ labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
-	// TODO(rfratto): implement pushing metadata to WAL
-	return 0, nil
+// UpdateMetadata logs the metadata of a series to the WAL when it changes, so
+// that it can be sent natively over Remote Write 2.0.
+func (a *appender) UpdateMetadata(ref storage.SeriesRef, l labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
+	series := a.w.series.GetByID(chunks.HeadSeriesRef(ref))
+	if series == nil {
+		series = a.w.series.GetByHash(l.Hash(), l)
+	}
+	if series == nil {
+		// Metadata is only logged for series which already have samples.
+		return 0, nil
+	}
+
+	series.Lock()
+	changed := series.meta == nil || *series.meta != m
+	series.Unlock()
+
+	if changed {
+		// NOTE: always modify pendingMetadata and metadataSeries together.
+		a.pendingMetadata = append(a.pendingMetadata, record.RefMetadata{
+			Ref:  series.ref,
+			Type: record.GetMetricType(m.Type),
+			Unit: m.Unit,
+			Help: m.Help,
+		})
+		a.metadataSeries = append(a.metadataSeries, series)
+	}
+
+	return storage.SeriesRef(series.ref), nil
 }
 
 // Commit submits the collected samples and purges the batch.
@@ -902,6 +986,14 @@ func (a *appender) log() error {
 		buf = buf[:0]
 	}
 
+	if len(a.pendingMetadata) > 0 {
+		buf = encoder.Metadata(a.pendingMetadata, buf)
+		if err := a.w.wal.Log(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
 	if len(a.pendingSamples) > 0 {
 		buf = encoder.Samples(a.pendingSamples, buf)
 		if err := a.w.wal.Log(buf); err != nil {
@@ -957,6 +1049,16 @@ func (a *appender) log() error {
 			a.w.metrics.totalOutOfOrderSamples.Inc()
 		}
 	}
+	for i, m := range a.pendingMetadata {
+		series = a.metadataSeries[i]
+		series.Lock()
+		series.meta = &metadata.Metadata{
+			Type: record.ToMetricType(m.Type),
+			Unit: m.Unit,
+			Help: m.Help,
+		}
+		series.Unlock()
+	}
 
 	return nil
 }
@@ -968,9 +1070,11 @@ func (a *appender) clearData() {
 	a.pendingHistograms = a.pendingHistograms[:0]
 	a.pendingFloatHistograms = a.pendingFloatHistograms[:0]
 	a.pendingExamplars = a.pendingExamplars[:0]
+	a.pendingMetadata = a.pendingMetadata[:0]
 	a.sampleSeries = a.sampleSeries[:0]
 	a.histogramSeries = a.histogramSeries[:0]
 	a.floatHistogramSeries = a.floatHistogramSeries[:0]
+	a.metadataSeries = a.metadataSeries[:0]
 }
 
 func (a *appender) Rollback() error {
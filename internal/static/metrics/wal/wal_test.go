@@ -10,8 +10,10 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb"
@@ -181,6 +183,90 @@ func TestStorage_DuplicateExemplarsIgnored(t *testing.T) {
 	require.Equal(t, 4, len(collector.exemplars))
 }
 
+func TestStorage_Metadata(t *testing.T) {
+	walDir := t.TempDir()
+	s, err := NewStorage(log.NewNopLogger(), nil, walDir)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	lbls := labels.FromStrings("__name__", "http_requests_total")
+	meta := metadata.Metadata{Type: model.MetricTypeCounter, Help: "Total HTTP requests."}
+
+	app := s.Appender(t.Context())
+
+	// Metadata for unknown series is ignored.
+	ref, err := app.UpdateMetadata(0, labels.FromStrings("__name__", "unknown"), meta)
+	require.NoError(t, err)
+	require.Zero(t, ref)
+
+	ref, err = app.Append(0, lbls, 10, 1)
+	require.NoError(t, err)
+	_, err = app.UpdateMetadata(ref, lbls, meta)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// Unchanged metadata is not logged again.
+	app = s.Appender(t.Context())
+	_, err = app.UpdateMetadata(ref, lbls, meta)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// Changed metadata is logged.
+	app = s.Appender(t.Context())
+	meta.Unit = "requests"
+	_, err = app.UpdateMetadata(ref, lbls, meta)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	var collector walDataCollector
+	replayer := walReplayer{w: &collector}
+	require.NoError(t, replayer.Replay(s.wal.Dir()))
+
+	require.Equal(t, []record.RefMetadata{
+		{Ref: chunks.HeadSeriesRef(ref), Type: record.GetMetricType(model.MetricTypeCounter), Help: "Total HTTP requests."},
+		{Ref: chunks.HeadSeriesRef(ref), Type: record.GetMetricType(model.MetricTypeCounter), Unit: "requests", Help: "Total HTTP requests."},
+	}, collector.metadata)
+}
+
+func TestStorage_CTZeroSample(t *testing.T) {
+	walDir := t.TempDir()
+	s, err := NewStorage(log.NewNopLogger(), nil, walDir)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, s.Close())
+	}()
+
+	lbls := labels.FromStrings("__name__", "http_requests_total")
+
+	app := s.Appender(t.Context())
+	_, err = app.AppendCTZeroSample(0, lbls, 100, 100)
+	require.Error(t, err)
+
+	ref, err := app.AppendCTZeroSample(0, lbls, 100, 50)
+	require.NoError(t, err)
+	_, err = app.Append(ref, lbls, 100, 5)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	// A created timestamp older than the last sample is rejected.
+	app = s.Appender(t.Context())
+	_, err = app.AppendCTZeroSample(ref, lbls, 200, 80)
+	require.ErrorIs(t, err, storage.ErrOutOfOrderCT)
+	require.NoError(t, app.Commit())
+
+	var collector walDataCollector
+	replayer := walReplayer{w: &collector}
+	require.NoError(t, replayer.Replay(s.wal.Dir()))
+
+	require.Len(t, collector.series, 1)
+	require.Equal(t, []record.RefSample{
+		{Ref: chunks.HeadSeriesRef(ref), T: 50, V: 0},
+		{Ref: chunks.HeadSeriesRef(ref), T: 100, V: 5},
+	}, collector.samples)
+}
+
 func TestStorage_ExistingWAL(t *testing.T) {
 	walDir := t.TempDir()
 
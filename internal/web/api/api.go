@@ -7,7 +7,6 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"path"
 	"strconv"
@@ -58,6 +57,8 @@ func (a *AlloyAPI) RegisterRoutes(urlPrefix string, r *mux.Router) {
 
 	r.Handle(path.Join(urlPrefix, "/graph"), graph(a.alloy, a.CallbackManager, a.logger))
 	r.Handle(path.Join(urlPrefix, "/graph/{moduleID:.+}"), graph(a.alloy, a.CallbackManager, a.logger))
+
+	r.Handle(path.Join(urlPrefix, "/tools/relabel"), relabelHandler()).Methods(http.MethodPost)
 }
 
 func getRemoteCfgHost(host service.Host) (service.Host, error) {
@@ -293,19 +294,35 @@ func liveDebugging(h service.Host, callbackManager livedebugging.CallbackManager
 		dataCh := make(chan string, 1000)
 		ctx := r.Context()
 
-		sampleProb := setSampleProb(w, r.URL.Query().Get("sampleProb"))
+		var filter *livedebugging.Filter
+		if filterParam := r.URL.Query().Get("filter"); filterParam != "" {
+			filter, err = livedebugging.ParseFilter(filterParam)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		maxItemsPerSecond, err := parseMaxItemsPerSecond(r.URL.Query().Get("maxItemsPerSecond"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		streamOpts := livedebugging.StreamOptions{
+			Filter:            filter,
+			SampleProb:        setSampleProb(w, r.URL.Query().Get("sampleProb")),
+			MaxItemsPerSecond: maxItemsPerSecond,
+		}
 
 		id := livedebugging.CallbackID(uuid.New().String())
 
 		droppedData := false
-		err = callbackManager.AddCallback(host, id, componentID, func(data livedebugging.Data) {
+		err = callbackManager.AddCallback(host, id, componentID, streamOpts.Wrap(func(data livedebugging.Data) {
 			select {
 			case <-ctx.Done():
 				return
 			default:
-				if sampleProb < 1 && rand.Float64() > sampleProb {
-					return
-				}
 				// Avoid blocking the channel when the channel is full
 				select {
 				case dataCh <- data.DataFunc():
@@ -316,7 +333,7 @@ func liveDebugging(h service.Host, callbackManager livedebugging.CallbackManager
 					}
 				}
 			}
-		})
+		}))
 
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -375,6 +392,18 @@ func setSampleProb(w http.ResponseWriter, sampleProbParam string) (sampleProb fl
 	return sampleProb
 }
 
+// maxItemsPerSecond is expected to be a non-negative integer, 0 means no limit.
+func parseMaxItemsPerSecond(param string) (int, error) {
+	if param == "" {
+		return 0, nil
+	}
+	maxItemsPerSecond, err := strconv.Atoi(param)
+	if err != nil || maxItemsPerSecond < 0 {
+		return 0, fmt.Errorf("invalid max items per second %q", param)
+	}
+	return maxItemsPerSecond, nil
+}
+
 // window is expected to be in seconds, between 1 and 60.
 func setWindow(w http.ResponseWriter, windowParam string) time.Duration {
 	const defaultWindow = 5 * time.Second
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+)
+
+// maxRelabelRequestSize is the maximum size of the body of a relabel request.
+const maxRelabelRequestSize = 1 << 20
+
+type relabelRequest struct {
+	// Rules are rule blocks in Alloy syntax.
+	Rules string `json:"rules"`
+	// Inputs are the label sets to apply the rules to.
+	Inputs []map[string]string `json:"inputs"`
+}
+
+type relabelResponse struct {
+	Results []relabelResult `json:"results"`
+}
+
+type relabelResult struct {
+	Input labels.Labels `json:"input"`
+	// Steps are the results of every applied rule, in order.
+	Steps []relabelStep `json:"steps"`
+	// Output is empty if the label set was dropped.
+	Output  labels.Labels `json:"output"`
+	Dropped bool          `json:"dropped"`
+}
+
+type relabelStep struct {
+	// Rule is the position of the rule in the request, starting at 1 like in
+	// the output of the relabel tools command.
+	Rule    int           `json:"rule"`
+	Action  string        `json:"action"`
+	Labels  labels.Labels `json:"labels"`
+	Dropped bool          `json:"dropped"`
+}
+
+// relabelHandler applies relabeling rules to label sets, and returns the
+// result of every rule along with the final label sets.
+func relabelHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req relabelRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRelabelRequestSize)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		rules, err := alloy_relabel.ParseRules([]byte(req.Rules))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := relabelResponse{Results: make([]relabelResult, 0, len(req.Inputs))}
+		for _, input := range req.Inputs {
+			lbls := labels.FromMap(input)
+			steps, output, keep := alloy_relabel.ProcessSteps(lbls, rules...)
+
+			result := relabelResult{
+				Input:   lbls,
+				Steps:   make([]relabelStep, 0, len(steps)),
+				Output:  output,
+				Dropped: !keep,
+			}
+			for i, step := range steps {
+				result.Steps = append(result.Steps, relabelStep{
+					Rule:    i + 1,
+					Action:  step.Rule.Action.String(),
+					Labels:  step.Labels,
+					Dropped: !step.Keep,
+				})
+			}
+			resp.Results = append(resp.Results, result)
+		}
+
+		bb, err := json.Marshal(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(bb)
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelabelHandler(t *testing.T) {
+	tt := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "rules applied",
+			body: `{
+				"rules": "rule {\n  source_labels = [\"__address__\"]\n  target_label = \"instance\"\n}\nrule {\n  source_labels = [\"job\"]\n  regex = \"drop-.*\"\n  action = \"drop\"\n}",
+				"inputs": [
+					{"__address__": "localhost:9090", "job": "prometheus"},
+					{"__address__": "localhost:8080", "job": "drop-me"}
+				]
+			}`,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"results":[` +
+				`{"input":{"__address__":"localhost:9090","job":"prometheus"},"steps":[` +
+				`{"rule":1,"action":"replace","labels":{"__address__":"localhost:9090","instance":"localhost:9090","job":"prometheus"},"dropped":false},` +
+				`{"rule":2,"action":"drop","labels":{"__address__":"localhost:9090","instance":"localhost:9090","job":"prometheus"},"dropped":false}],` +
+				`"output":{"__address__":"localhost:9090","instance":"localhost:9090","job":"prometheus"},"dropped":false},` +
+				`{"input":{"__address__":"localhost:8080","job":"drop-me"},"steps":[` +
+				`{"rule":1,"action":"replace","labels":{"__address__":"localhost:8080","instance":"localhost:8080","job":"drop-me"},"dropped":false},` +
+				`{"rule":2,"action":"drop","labels":{},"dropped":true}],` +
+				`"output":{},"dropped":true}]}`,
+		},
+		{
+			name:         "invalid request",
+			body:         `{"rules": 1}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid request",
+		},
+		{
+			name:         "invalid rules",
+			body:         `{"rules": "rule { action = \"unknown\" }"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "unknown",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/web/tools/relabel", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			relabelHandler().ServeHTTP(rec, req)
+
+			require.Equal(t, tc.expectedCode, rec.Code)
+			if tc.expectedCode == http.StatusOK {
+				require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+				require.JSONEq(t, tc.expectedBody, rec.Body.String())
+			} else {
+				require.Contains(t, rec.Body.String(), tc.expectedBody)
+			}
+		})
+	}
+}
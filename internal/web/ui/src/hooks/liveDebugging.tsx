@@ -4,6 +4,8 @@ export const useLiveDebugging = (
   componentID: string,
   enabled: boolean,
   sampleProb: number,
+  filter: string,
+  maxItemsPerSecond: number,
   setData: React.Dispatch<React.SetStateAction<string[]>>
 ) => {
   const [loading, setLoading] = useState(false);
@@ -22,7 +24,14 @@ export const useLiveDebugging = (
       setLoading(true);
 
       try {
-        const response = await fetch(`./api/v0/web/debug/${componentID}?sampleProb=${sampleProb}`, {
+        const params = new URLSearchParams({ sampleProb: String(sampleProb) });
+        if (filter) {
+          params.set('filter', filter);
+        }
+        if (maxItemsPerSecond > 0) {
+          params.set('maxItemsPerSecond', String(maxItemsPerSecond));
+        }
+        const response = await fetch(`./api/v0/web/debug/${componentID}?${params.toString()}`, {
           signal: abortController.signal,
           cache: 'no-cache',
           credentials: 'same-origin',
@@ -77,7 +86,7 @@ export const useLiveDebugging = (
     return () => {
       abortController.abort();
     };
-  }, [componentID, enabled, sampleProb, setData]);
+  }, [componentID, enabled, sampleProb, filter, maxItemsPerSecond, setData]);
 
   return { loading, error };
 };
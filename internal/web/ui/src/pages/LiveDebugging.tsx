@@ -18,7 +18,16 @@ function PageLiveDebugging() {
   const [sampleProb, setSampleProb] = useState(1);
   const [sliderProb, setSliderProb] = useState(100);
   const [filterValue, setFilterValue] = useState('');
-  const { loading, error } = useLiveDebugging(String(componentID), enabled, sampleProb, setData);
+  const [streamFilter, setStreamFilter] = useState('');
+  const [maxItemsPerSecond, setMaxItemsPerSecond] = useState(0);
+  const { loading, error } = useLiveDebugging(
+    String(componentID),
+    enabled,
+    sampleProb,
+    streamFilter,
+    maxItemsPerSecond,
+    setData
+  );
 
   const filteredData = data.filter((n) => n.toLowerCase().includes(filterValue.toLowerCase()));
 
@@ -88,8 +97,45 @@ function PageLiveDebugging() {
     </Field>
   );
 
+  // The stream filter and the rate limit are applied by the server, the stream
+  // restarts when they change.
+  function handleStreamFilterKeyDown(event: React.KeyboardEvent<HTMLInputElement>) {
+    if (event.key === 'Enter') {
+      setStreamFilter(event.currentTarget.value.trim());
+    }
+  }
+
+  function handleMaxItemsChange(event: React.FocusEvent<HTMLInputElement>) {
+    const value = parseInt(event.currentTarget.value, 10);
+    setMaxItemsPerSecond(isNaN(value) || value < 0 ? 0 : value);
+  }
+
+  const streamFilterControl = (
+    <Field className={styles.streamFilter}>
+      <Input
+        placeholder='Stream filter, e.g. {job="api"} |= "error"'
+        title="Filter applied by the server, press Enter to apply"
+        onKeyDown={handleStreamFilterKeyDown}
+      />
+    </Field>
+  );
+
+  const maxItemsControl = (
+    <Field className={styles.maxItems}>
+      <Input
+        type="number"
+        min={0}
+        placeholder="Max items/s"
+        title="Maximum number of items sent per second, 0 for no limit"
+        onBlur={handleMaxItemsChange}
+      />
+    </Field>
+  );
+
   const controls = (
     <>
+      {streamFilterControl}
+      {maxItemsControl}
       {filterControl}
       {samplingControl}
       {toggleEnableButton()}
@@ -379,9 +379,39 @@ func (n blockName) ValidAttribute() bool {
 
 // ParseExpression parses a single expression.
 //
-//	Expression = BinOpExpr
+//	Expression = CondExpr
 func (p *parser) ParseExpression() ast.Expr {
-	return p.parseBinOp(1)
+	return p.parseCondExpr()
+}
+
+// parseCondExpr parses a conditional expression. If there is no conditional
+// expression in the current state, the result of parseBinOp is returned
+// instead.
+//
+//	CondExpr = BinOpExpr [ "?" Expression ":" Expression ]
+//
+// Conditional expressions have the lowest precedence of all operators and are
+// right-associative, so a ? b : c ? d : e is parsed as a ? b : (c ? d : e).
+func (p *parser) parseCondExpr() ast.Expr {
+	cond := p.parseBinOp(1)
+	if p.tok != token.QUESTION {
+		return cond
+	}
+
+	questionPos := p.pos
+	p.next() // Consume ?
+
+	trueExpr := p.ParseExpression()
+	colonPos, _, _ := p.expect(token.COLON)
+	falseExpr := p.ParseExpression()
+
+	return &ast.ConditionalExpr{
+		Cond:        cond,
+		True:        trueExpr,
+		False:       falseExpr,
+		QuestionPos: questionPos,
+		ColonPos:    colonPos,
+	}
 }
 
 // parseBinOp is the entrypoint for binary expressions. If there is no binary
@@ -51,6 +51,8 @@ import (
 //   RBRACK  = "]"
 //   COMMA   = ","
 //   DOT     = "."
+//   QUESTION = "?"
+//   COLON    = ":"
 //
 // The EBNF for escape_sequence is currently undocumented; see scanEscape for
 // details. The escape sequences supported by Alloy are the same as the escape
@@ -338,6 +340,10 @@ scanAgain:
 		case '.':
 			// NOTE: Fractions starting with '.' are handled by outer switch
 			tok = token.DOT
+		case '?':
+			tok = token.QUESTION
+		case ':':
+			tok = token.COLON
 
 		default:
 			// s.next() reports invalid BOMs so we don't need to repeat the error.
@@ -458,6 +458,22 @@ func (vm *Evaluator) evaluateExpr(scope *Scope, assoc map[value.Value]ast.Node,
 			}
 		}
 
+	case *ast.ConditionalExpr:
+		cond, err := vm.evaluateExpr(scope, assoc, expr.Cond)
+		if err != nil {
+			return value.Null, err
+		}
+		if cond.Type() != value.TypeBool {
+			return value.Null, value.TypeError{Value: cond, Expected: value.TypeBool}
+		}
+
+		// Only the selected branch is evaluated, so errors in the other branch
+		// (such as accessing a field which doesn't exist) are not reported.
+		if cond.Bool() {
+			return vm.evaluateExpr(scope, assoc, expr.True)
+		}
+		return vm.evaluateExpr(scope, assoc, expr.False)
+
 	case *ast.ParenExpr:
 		return vm.evaluateExpr(scope, assoc, expr.Inner)
 
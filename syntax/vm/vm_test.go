@@ -137,6 +137,14 @@ func TestVM_Evaluate(t *testing.T) {
 		{`!true`, bool(false)},
 		{`!false`, bool(true)},
 		{`-15`, int(-15)},
+
+		// Conditional
+		{`true ? 1 : 2`, int(1)},
+		{`false ? 1 : 2`, int(2)},
+		{`foobar > 40 ? "big" : "small"`, string("big")},
+		{`false ? 1 : true ? 2 : 3`, int(2)},
+		{`(true ? 1 : 2) + 3`, int(4)},
+		{`true ? 1 : {}.missing`, int(1)}, // Untaken branch isn't evaluated
 	}
 
 	for _, tc := range tt {
@@ -250,6 +258,35 @@ func TestVM_Evaluate_AccessExpr(t *testing.T) {
 	})
 }
 
+func TestVM_Evaluate_ConditionalExpr(t *testing.T) {
+	t.Run("Non-bool condition", func(t *testing.T) {
+		expr, err := parser.ParseExpression(`"yes" ? 1 : 2`)
+		require.NoError(t, err)
+
+		eval := vm.New(expr)
+
+		var v interface{}
+		err = eval.Evaluate(nil, &v)
+		require.EqualError(t, err, `1:1: "yes" should be bool, got string`)
+	})
+
+	t.Run("Error in selected branch", func(t *testing.T) {
+		expr, err := parser.ParseExpression(`false ? 1 : { a = 15 }.b`)
+		require.NoError(t, err)
+
+		eval := vm.New(expr)
+
+		var v interface{}
+		err = eval.Evaluate(nil, &v)
+		require.EqualError(t, err, `1:24: field "b" does not exist`)
+	})
+
+	t.Run("Missing colon", func(t *testing.T) {
+		_, err := parser.ParseExpression(`true ? 1`)
+		require.EqualError(t, err, `1:9: expected :, got TERMINATOR`)
+	})
+}
+
 func trimWhitespace(in string) string {
 	f := token.NewFile("")
 